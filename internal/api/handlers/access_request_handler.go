@@ -0,0 +1,421 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dione-docs-backend/internal/authz"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AccessRequestHandler struct {
+	repo       *repository.Repository
+	authorizer *authz.Authorizer
+}
+
+func NewAccessRequestHandler(repo *repository.Repository, authorizer *authz.Authorizer) *AccessRequestHandler {
+	return &AccessRequestHandler{
+		repo:       repo,
+		authorizer: authorizer,
+	}
+}
+
+type CreateAccessRequestRequest struct {
+	AccessType string `json:"access_type" binding:"required"` // "viewer", "commenter" veya "editor"
+	Message    string `json:"message" binding:"max=1000"`
+}
+
+type ResolveAccessRequestRequest struct {
+	AccessType string `json:"access_type"` // boşsa istenen erişim verilir
+}
+
+type AccessRequestResponse struct {
+	ID             uuid.UUID                  `json:"id"`
+	DocumentID     uuid.UUID                  `json:"document_id"`
+	DocumentTitle  string                     `json:"document_title,omitempty"`
+	RequesterID    uuid.UUID                  `json:"requester_id"`
+	RequesterEmail string                     `json:"requester_email"`
+	AccessType     string                     `json:"access_type"`
+	Message        string                     `json:"message,omitempty"`
+	Status         models.AccessRequestStatus `json:"status"`
+	ResolvedBy     *uuid.UUID                 `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time                 `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time                  `json:"created_at"`
+}
+
+// requestableAccess, erişim isteklerinde ve onaylarında verilebilecek erişim tipleridir.
+// Yönetici erişimi yalnızca doğrudan paylaşımla verilebilir.
+func requestableAccess(accessType string) bool {
+	switch models.AccessType(accessType) {
+	case models.AccessTypeViewer, models.AccessTypeCommenter, models.AccessTypeEditor:
+		return true
+	}
+	return false
+}
+
+func (h *AccessRequestHandler) requestToResponse(request *models.AccessRequest, doc *models.Document) AccessRequestResponse {
+	response := AccessRequestResponse{
+		ID:          request.ID,
+		DocumentID:  request.DocumentID,
+		RequesterID: request.RequesterID,
+		AccessType:  request.AccessType,
+		Message:     request.Message,
+		Status:      request.Status,
+		ResolvedBy:  request.ResolvedBy,
+		ResolvedAt:  request.ResolvedAt,
+		CreatedAt:   request.CreatedAt,
+	}
+	if doc != nil {
+		response.DocumentTitle = doc.Title
+	}
+	var requester models.User
+	if err := h.repo.User.GetByID(request.RequesterID, &requester); err == nil {
+		response.RequesterEmail = requester.Email
+	}
+	return response
+}
+
+// notify, erişim isteğiyle ilgili bir bildirimi kullanıcıya iletir. Bildirim kaydedilemezse yalnızca loglanır.
+func (h *AccessRequestHandler) notify(userID uuid.UUID, notificationType models.NotificationType, doc *models.Document, actorID uuid.UUID, message string) {
+	if err := h.repo.Notification.Create(&models.Notification{
+		UserID:     userID,
+		Type:       notificationType,
+		DocumentID: &doc.ID,
+		ActorID:    &actorID,
+		Message:    message,
+	}); err != nil {
+		log.Printf("Erişim isteği bildirimi kaydedilemedi (belge %s, kullanıcı %s): %v", doc.ID, userID, err)
+	}
+}
+
+// loadDocument, path'teki belgeyi isteği yapan kullanıcının çalışma alanında yükler.
+// Sorun varsa yanıtı yazar ve false döner.
+func (h *AccessRequestHandler) loadDocument(c *gin.Context) (*models.Document, uuid.UUID, bool) {
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz belge ID'si"})
+		return nil, uuid.Nil, false
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return nil, uuid.Nil, false
+	}
+
+	organizationID, _ := utils.GetOrganizationFromContext(c)
+	var doc models.Document
+	if err := h.repo.Document.InOrganization(organizationID).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return nil, uuid.Nil, false
+	}
+	return &doc, userID, true
+}
+
+// loadRequest, path'teki bekleyen erişim isteğini yükler ve belgeye ait olduğunu doğrular.
+// Sorun varsa yanıtı yazar ve nil döner.
+func (h *AccessRequestHandler) loadRequest(c *gin.Context, doc *models.Document) *models.AccessRequest {
+	requestID, err := uuid.Parse(c.Param("request_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz erişim isteği ID'si"})
+		return nil
+	}
+
+	var request models.AccessRequest
+	if err := h.repo.AccessReq.GetByID(requestID, &request); err != nil || request.DocumentID != doc.ID {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Erişim isteği bulunamadı"})
+		return nil
+	}
+	if request.Status != models.AccessRequestStatusPending {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Bu erişim isteği zaten yanıtlanmış"})
+		return nil
+	}
+	return &request
+}
+
+// CreateAccessRequest godoc
+// @Tags Access Requests
+// @Summary Request access to a document
+// @Description Asks the owner and admins of the document for the given role. A pending request of the same user is updated instead of creating a new one.
+// @Accept json
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param request body CreateAccessRequestRequest true "Requested role and message"
+// @Success 201 {object} AccessRequestResponse
+// @Success 200 {object} AccessRequestResponse "Pending request updated"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/access-requests [post]
+func (h *AccessRequestHandler) CreateAccessRequest(c *gin.Context) {
+	doc, userID, ok := h.loadDocument(c)
+	if !ok {
+		return
+	}
+
+	var body CreateAccessRequestRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+	if !requestableAccess(body.AccessType) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz erişim tipi. 'viewer', 'commenter' veya 'editor' olmalıdır"})
+		return
+	}
+
+	role, err := h.authorizer.Role(userID, doc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Yetki kontrolü sırasında bir hata oluştu."})
+		return
+	}
+	if role.AtLeast(authz.Role(body.AccessType)) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Bu belgede zaten bu erişime sahipsiniz"})
+		return
+	}
+
+	var requester models.User
+	if err := h.repo.User.GetByID(userID, &requester); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Kullanıcı bilgisi alınamadı: " + err.Error()})
+		return
+	}
+	allowed, err := sharingAllowed(h.repo, doc, &requester)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Paylaşım kısıtlamaları kontrol edilemedi: " + err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Organizasyon, belgenin sizinle paylaşılmasına izin vermiyor"})
+		return
+	}
+
+	request, err := h.repo.AccessReq.GetPendingByDocumentAndUser(doc.ID, userID)
+	if err == nil {
+		request.AccessType = body.AccessType
+		request.Message = body.Message
+		if err := h.repo.AccessReq.Update(request); err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erişim isteği güncellenemedi: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, h.requestToResponse(request, doc))
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erişim istekleri kontrol edilemedi: " + err.Error()})
+		return
+	}
+
+	request = &models.AccessRequest{
+		DocumentID:  doc.ID,
+		RequesterID: userID,
+		AccessType:  body.AccessType,
+		Message:     body.Message,
+		Status:      models.AccessRequestStatusPending,
+	}
+	if err := h.repo.AccessReq.Create(request); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erişim isteği oluşturulamadı: " + err.Error()})
+		return
+	}
+
+	h.notify(doc.OwnerID, models.NotificationTypeAccessRequested, doc, userID,
+		fmt.Sprintf("%s, \"%s\" belgesi için %s erişimi istiyor.", requester.Email, doc.Title, body.AccessType))
+
+	c.JSON(http.StatusCreated, h.requestToResponse(request, doc))
+}
+
+// GetAccessRequests godoc
+// @Tags Access Requests
+// @Summary List access requests of a document
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param status query string false "pending (default), approved, denied or all"
+// @Success 200 {array} AccessRequestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/access-requests [get]
+func (h *AccessRequestHandler) GetAccessRequests(c *gin.Context) {
+	doc, userID, ok := h.loadDocument(c)
+	if !ok {
+		return
+	}
+
+	if !authorize(c, h.authorizer, userID, authz.ActionShare, doc, "Bu belgenin erişim isteklerini görüntüleme yetkiniz yok") {
+		return
+	}
+
+	var status models.AccessRequestStatus
+	switch query := c.DefaultQuery("status", string(models.AccessRequestStatusPending)); query {
+	case "all":
+	case string(models.AccessRequestStatusPending), string(models.AccessRequestStatusApproved), string(models.AccessRequestStatusDenied):
+		status = models.AccessRequestStatus(query)
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz erişim isteği durumu"})
+		return
+	}
+
+	requests, err := h.repo.AccessReq.GetByDocumentID(doc.ID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erişim istekleri alınamadı: " + err.Error()})
+		return
+	}
+
+	responses := make([]AccessRequestResponse, 0, len(requests))
+	for i := range requests {
+		responses = append(responses, h.requestToResponse(&requests[i], doc))
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetMyAccessRequests godoc
+// @Tags Access Requests
+// @Summary List access requests sent by the current user
+// @Produce json
+// @Success 200 {array} AccessRequestResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/access-requests [get]
+func (h *AccessRequestHandler) GetMyAccessRequests(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	organizationID, _ := utils.GetOrganizationFromContext(c)
+	requests, err := h.repo.AccessReq.InOrganization(organizationID).GetByRequesterID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erişim istekleri alınamadı: " + err.Error()})
+		return
+	}
+
+	documents := h.repo.Document.InOrganization(organizationID)
+	responses := make([]AccessRequestResponse, 0, len(requests))
+	for i := range requests {
+		var doc models.Document
+		if err := documents.GetByID(requests[i].DocumentID, &doc); err != nil {
+			continue
+		}
+		responses = append(responses, h.requestToResponse(&requests[i], &doc))
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// ApproveAccessRequest godoc
+// @Tags Access Requests
+// @Summary Approve an access request
+// @Description Grants the requester an accepted permission with the requested role, or with access_type if given. Any pending invitation or existing permission of the requester is replaced.
+// @Accept json
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param request_id path string true "Access request ID"
+// @Param request body ResolveAccessRequestRequest false "Role to grant"
+// @Success 200 {object} AccessRequestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/access-requests/{request_id}/approve [post]
+func (h *AccessRequestHandler) ApproveAccessRequest(c *gin.Context) {
+	doc, userID, ok := h.loadDocument(c)
+	if !ok {
+		return
+	}
+	if !authorize(c, h.authorizer, userID, authz.ActionShare, doc, "Bu belgenin erişim isteklerini yanıtlama yetkiniz yok") {
+		return
+	}
+	request := h.loadRequest(c, doc)
+	if request == nil {
+		return
+	}
+
+	var body ResolveAccessRequestRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+			return
+		}
+	}
+	accessType := request.AccessType
+	if body.AccessType != "" {
+		if !requestableAccess(body.AccessType) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz erişim tipi. 'viewer', 'commenter' veya 'editor' olmalıdır"})
+			return
+		}
+		accessType = body.AccessType
+	}
+
+	if err := h.repo.AccessReq.Approve(request, accessType, userID); err != nil {
+		if errors.Is(err, repository.ErrAccessRequestNotPending) {
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "Bu erişim isteği zaten yanıtlanmış"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erişim isteği onaylanamadı: " + err.Error()})
+		return
+	}
+	now := time.Now()
+	request.Status, request.AccessType, request.ResolvedBy, request.ResolvedAt = models.AccessRequestStatusApproved, accessType, &userID, &now
+
+	h.notify(request.RequesterID, models.NotificationTypeAccessApproved, doc, userID,
+		fmt.Sprintf("\"%s\" belgesine erişim isteğiniz onaylandı (%s).", doc.Title, accessType))
+
+	c.JSON(http.StatusOK, h.requestToResponse(request, doc))
+}
+
+// DenyAccessRequest godoc
+// @Tags Access Requests
+// @Summary Deny an access request
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param request_id path string true "Access request ID"
+// @Success 200 {object} AccessRequestResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/access-requests/{request_id}/deny [post]
+func (h *AccessRequestHandler) DenyAccessRequest(c *gin.Context) {
+	doc, userID, ok := h.loadDocument(c)
+	if !ok {
+		return
+	}
+	if !authorize(c, h.authorizer, userID, authz.ActionShare, doc, "Bu belgenin erişim isteklerini yanıtlama yetkiniz yok") {
+		return
+	}
+	request := h.loadRequest(c, doc)
+	if request == nil {
+		return
+	}
+
+	denied, err := h.repo.AccessReq.Deny(request.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erişim isteği reddedilemedi: " + err.Error()})
+		return
+	}
+	if !denied {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Bu erişim isteği zaten yanıtlanmış"})
+		return
+	}
+	now := time.Now()
+	request.Status, request.ResolvedBy, request.ResolvedAt = models.AccessRequestStatusDenied, &userID, &now
+
+	h.notify(request.RequesterID, models.NotificationTypeAccessDenied, doc, userID,
+		fmt.Sprintf("\"%s\" belgesine erişim isteğiniz reddedildi.", doc.Title))
+
+	c.JSON(http.StatusOK, h.requestToResponse(request, doc))
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/services"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AccessTokenHandler struct {
+	repo   *repository.Repository
+	tokens *services.AccessTokenService
+}
+
+func NewAccessTokenHandler(repo *repository.Repository, tokens *services.AccessTokenService) *AccessTokenHandler {
+	return &AccessTokenHandler{
+		repo:   repo,
+		tokens: tokens,
+	}
+}
+
+// CreateAccessTokenRequest, yeni kişisel erişim token'ının adını, kapsamlarını ve isteğe bağlı bitiş zamanını içerir.
+type CreateAccessTokenRequest struct {
+	Name      string              `json:"name" binding:"required,max=100"`
+	Scopes    []models.TokenScope `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time          `json:"expires_at"`
+}
+
+type AccessTokenResponse struct {
+	ID         uuid.UUID           `json:"id"`
+	Name       string              `json:"name"`
+	Prefix     string              `json:"prefix"`
+	Scopes     []models.TokenScope `json:"scopes"`
+	ExpiresAt  *time.Time          `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time          `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+// CreatedAccessTokenResponse, token değerini yalnızca oluşturulduğunda bir kez döndürür.
+type CreatedAccessTokenResponse struct {
+	AccessTokenResponse
+	Token string `json:"token"`
+}
+
+// GetAccessTokens godoc
+// @Summary List personal access tokens
+// @Description Returns the current user's personal access tokens that have not been revoked. Token values are never returned again after creation.
+// @Tags Access Tokens
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} AccessTokenResponse
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /api/v1/me/tokens [get]
+func (h *AccessTokenHandler) GetAccessTokens(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	tokens, err := h.repo.AccessToken.GetActiveByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve access tokens"})
+		return
+	}
+
+	response := make([]AccessTokenResponse, 0, len(tokens))
+	for i := range tokens {
+		response = append(response, accessTokenToResponse(&tokens[i]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateAccessToken godoc
+// @Summary Create a personal access token
+// @Description Creates a personal access token for scripts and automation. Send it as "Authorization: Bearer <token>". Available scopes: documents:read, documents:write, chat:write, admin.
+// @Tags Access Tokens
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateAccessTokenRequest true "Token name, scopes and optional expiry"
+// @Success 201 {object} CreatedAccessTokenResponse
+// @Failure 400 {object} ErrorResponse "Invalid request data, unknown scope or expiry in the past"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /api/v1/me/tokens [post]
+func (h *AccessTokenHandler) CreateAccessToken(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var req CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request data"})
+		return
+	}
+	scopes := make([]models.TokenScope, 0, len(req.Scopes))
+	seen := make(map[models.TokenScope]bool)
+	for _, scope := range req.Scopes {
+		if !models.ValidTokenScope(scope) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unknown scope: " + string(scope)})
+			return
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Expiry must be in the future"})
+		return
+	}
+
+	token, secret, err := h.tokens.Create(userID, req.Name, scopes, req.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create access token"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreatedAccessTokenResponse{
+		AccessTokenResponse: accessTokenToResponse(token),
+		Token:               secret,
+	})
+}
+
+// RevokeAccessToken godoc
+// @Summary Revoke a personal access token
+// @Description Revokes one of the current user's personal access tokens. Requests using it are rejected immediately.
+// @Tags Access Tokens
+// @Produce json
+// @Security BearerAuth
+// @Param token_id path string true "Token ID"
+// @Success 204 "Token revoked"
+// @Failure 400 {object} ErrorResponse "Invalid token ID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Token not found"
+// @Router /api/v1/me/tokens/{token_id} [delete]
+func (h *AccessTokenHandler) RevokeAccessToken(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+	tokenID, err := uuid.Parse(c.Param("token_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid token ID"})
+		return
+	}
+
+	revoked, err := h.repo.AccessToken.Revoke(userID, tokenID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to revoke access token"})
+		return
+	}
+	if revoked == 0 {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Token not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func accessTokenToResponse(token *models.PersonalAccessToken) AccessTokenResponse {
+	return AccessTokenResponse{
+		ID:         token.ID,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.ScopeList(),
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
@@ -0,0 +1,626 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/services"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AdminHandler, site yöneticilerinin kullanıcıları ve içerikleri yönettiği uç noktalardır.
+// Her işlem denetim kaydına (audit log) yazılır.
+type AdminHandler struct {
+	repo          *repository.Repository
+	sessions      *services.SessionService
+	disconnectors []services.SessionDisconnector
+}
+
+func NewAdminHandler(repo *repository.Repository, sessions *services.SessionService, disconnectors []services.SessionDisconnector) *AdminHandler {
+	return &AdminHandler{
+		repo:          repo,
+		sessions:      sessions,
+		disconnectors: disconnectors,
+	}
+}
+
+type AdminUserResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`
+	FullName      string     `json:"full_name,omitempty"`
+	EmailVerified bool       `json:"email_verified"`
+	IsSiteAdmin   bool       `json:"is_site_admin"`
+	Disabled      bool       `json:"disabled"`
+	DisabledAt    *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// AdminUserDetailResponse, kullanıcının hesap güvenliğiyle ilgili durumunu da içerir.
+type AdminUserDetailResponse struct {
+	AdminUserResponse
+	MFAEnabled         bool `json:"mfa_enabled"`
+	ActiveSessions     int  `json:"active_sessions"`
+	ActiveAccessTokens int  `json:"active_access_tokens"`
+}
+
+type AdminUserListResponse struct {
+	Items    []AdminUserResponse `json:"items"`
+	Total    int64               `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+}
+
+// DisableUserRequest, hesabı devre dışı bırakma sebebini içerir; sebep denetim kaydına yazılır.
+type DisableUserRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+type AdminTeamPermissionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	TeamID     uuid.UUID `json:"team_id"`
+	AccessType string    `json:"access_type"`
+	SharedBy   uuid.UUID `json:"shared_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AdminDocumentResponse, belgenin içeriği olmadan üst verilerini ve tüm izinlerini döndürür.
+// Silinmiş belgelerde DeletedAt doludur.
+type AdminDocumentResponse struct {
+	DocumentResponse
+	OwnerEmail      string                        `json:"owner_email,omitempty"`
+	DeletedAt       *time.Time                    `json:"deleted_at,omitempty"`
+	Permissions     []PermissionResponse          `json:"permissions"`
+	TeamPermissions []AdminTeamPermissionResponse `json:"team_permissions"`
+}
+
+type AuditLogResponse struct {
+	ID         uuid.UUID              `json:"id"`
+	ActorID    uuid.UUID              `json:"actor_id"`
+	Action     models.AuditAction     `json:"action"`
+	TargetType models.AuditTargetType `json:"target_type,omitempty"`
+	TargetID   *uuid.UUID             `json:"target_id,omitempty"`
+	Details    json.RawMessage        `json:"details,omitempty" swaggertype:"object"`
+	IPAddress  string                 `json:"ip_address,omitempty"`
+	UserAgent  string                 `json:"user_agent,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+type AuditLogListResponse struct {
+	Items    []AuditLogResponse `json:"items"`
+	Total    int64              `json:"total"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+}
+
+func adminUserToResponse(user *models.User) AdminUserResponse {
+	return AdminUserResponse{
+		ID:            user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		FullName:      user.FullName,
+		EmailVerified: user.EmailVerified,
+		IsSiteAdmin:   user.IsSiteAdmin,
+		Disabled:      user.IsDisabled(),
+		DisabledAt:    user.DisabledAt,
+		CreatedAt:     user.CreatedAt,
+	}
+}
+
+func auditLogToResponse(entry *models.AuditLog) AuditLogResponse {
+	return AuditLogResponse{
+		ID:         entry.ID,
+		ActorID:    entry.ActorID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Details:    entry.Details,
+		IPAddress:  entry.IPAddress,
+		UserAgent:  entry.UserAgent,
+		CreatedAt:  entry.CreatedAt,
+	}
+}
+
+// SearchUsers godoc
+// @Summary Search users
+// @Description Lists users newest first. The q parameter matches username, email or full name (case-insensitive). Site administrators only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Search text"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} AdminUserListResponse
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Site administrator access required"
+// @Router /api/v1/admin/users [get]
+func (h *AdminHandler) SearchUsers(c *gin.Context) {
+	page, pageSize := parsePagination(c)
+	query := c.Query("q")
+
+	users, total, err := h.repo.User.Search(query, pageSize, (page-1)*pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve users"})
+		return
+	}
+	h.audit(c, models.AuditActionUserSearch, "", nil, gin.H{"query": query, "page": page})
+
+	items := make([]AdminUserResponse, len(users))
+	for i := range users {
+		items[i] = adminUserToResponse(&users[i])
+	}
+	c.JSON(http.StatusOK, AdminUserListResponse{
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+// GetUser godoc
+// @Summary Get a user
+// @Description Returns a user with their two-factor status and the number of active sessions and personal access tokens. Site administrators only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {object} AdminUserDetailResponse
+// @Failure 400 {object} ErrorResponse "Invalid user ID"
+// @Failure 403 {object} ErrorResponse "Site administrator access required"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Router /api/v1/admin/users/{user_id} [get]
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+
+	mfaEnabled, err := h.repo.MFA.IsEnabled(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve two-factor status"})
+		return
+	}
+	sessions, err := h.repo.Session.GetActiveByUserID(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve sessions"})
+		return
+	}
+	tokens, err := h.repo.AccessToken.GetActiveByUserID(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve access tokens"})
+		return
+	}
+	h.audit(c, models.AuditActionUserView, models.AuditTargetUser, &user.ID, nil)
+
+	c.JSON(http.StatusOK, AdminUserDetailResponse{
+		AdminUserResponse:  adminUserToResponse(user),
+		MFAEnabled:         mfaEnabled,
+		ActiveSessions:     len(sessions),
+		ActiveAccessTokens: len(tokens),
+	})
+}
+
+// DisableUser godoc
+// @Summary Disable a user account
+// @Description Disables the account so it can no longer sign in, revokes all of its sessions and personal access tokens and closes its open collaboration and chat connections. Site administrators cannot disable their own account. Revoked tokens are not restored when the account is enabled again.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param request body DisableUserRequest false "Reason for the audit log"
+// @Success 200 {object} AdminUserResponse
+// @Failure 400 {object} ErrorResponse "Invalid request data or own account"
+// @Failure 403 {object} ErrorResponse "Site administrator access required"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 409 {object} ErrorResponse "Account is already disabled"
+// @Router /api/v1/admin/users/{user_id}/disable [post]
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	var req DisableUserRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request data"})
+			return
+		}
+	}
+
+	user, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+	if actorID, _ := utils.GetUserIDFromContext(c); actorID == user.ID {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "You cannot disable your own account"})
+		return
+	}
+	if user.IsDisabled() {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Account is already disabled"})
+		return
+	}
+
+	now := time.Now()
+	if err := h.repo.User.UpdateFields(user.ID, map[string]interface{}{"disabled_at": now}); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to disable account"})
+		return
+	}
+	user.DisabledAt = &now
+
+	sessions, err := h.sessions.RevokeAll(user.ID, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to revoke sessions"})
+		return
+	}
+	tokens, err := h.repo.AccessToken.RevokeAll(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to revoke access tokens"})
+		return
+	}
+	h.disconnect(user.ID)
+	h.audit(c, models.AuditActionUserDisable, models.AuditTargetUser, &user.ID, gin.H{
+		"reason":                req.Reason,
+		"revoked_sessions":      sessions,
+		"revoked_access_tokens": tokens,
+	})
+
+	c.JSON(http.StatusOK, adminUserToResponse(user))
+}
+
+// EnableUser godoc
+// @Summary Enable a user account
+// @Description Allows a disabled account to sign in again. Site administrators only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {object} AdminUserResponse
+// @Failure 400 {object} ErrorResponse "Invalid user ID"
+// @Failure 403 {object} ErrorResponse "Site administrator access required"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Failure 409 {object} ErrorResponse "Account is not disabled"
+// @Router /api/v1/admin/users/{user_id}/enable [post]
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	user, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+	if !user.IsDisabled() {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Account is not disabled"})
+		return
+	}
+
+	if err := h.repo.User.UpdateFields(user.ID, map[string]interface{}{"disabled_at": nil}); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to enable account"})
+		return
+	}
+	user.DisabledAt = nil
+	h.audit(c, models.AuditActionUserEnable, models.AuditTargetUser, &user.ID, nil)
+
+	c.JSON(http.StatusOK, adminUserToResponse(user))
+}
+
+// ForceLogout godoc
+// @Summary Sign a user out everywhere
+// @Description Revokes all active sessions of the user. Personal access tokens are not affected. Site administrators only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {object} RevokeSessionsResponse
+// @Failure 400 {object} ErrorResponse "Invalid user ID"
+// @Failure 403 {object} ErrorResponse "Site administrator access required"
+// @Failure 404 {object} ErrorResponse "User not found"
+// @Router /api/v1/admin/users/{user_id}/logout [post]
+func (h *AdminHandler) ForceLogout(c *gin.Context) {
+	user, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.sessions.RevokeAll(user.ID, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to revoke sessions"})
+		return
+	}
+	h.audit(c, models.AuditActionUserLogout, models.AuditTargetUser, &user.ID, gin.H{"revoked_sessions": revoked})
+
+	c.JSON(http.StatusOK, RevokeSessionsResponse{Revoked: revoked})
+}
+
+// ResetUserMFA godoc
+// @Summary Reset a user's two-factor authentication
+// @Description Turns off two-factor authentication for the user and deletes their recovery codes, for example when the authenticator device is lost. Site administrators only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Success 204 "Two-factor authentication reset"
+// @Failure 400 {object} ErrorResponse "Invalid user ID"
+// @Failure 403 {object} ErrorResponse "Site administrator access required"
+// @Failure 404 {object} ErrorResponse "User not found or two-factor authentication not enabled"
+// @Router /api/v1/admin/users/{user_id}/mfa [delete]
+func (h *AdminHandler) ResetUserMFA(c *gin.Context) {
+	user, ok := h.targetUser(c)
+	if !ok {
+		return
+	}
+
+	enabled, err := h.repo.MFA.IsEnabled(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve two-factor status"})
+		return
+	}
+	if !enabled {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Two-factor authentication is not enabled"})
+		return
+	}
+
+	if err := h.repo.MFA.Delete(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to reset two-factor authentication"})
+		return
+	}
+	h.audit(c, models.AuditActionUserMFAReset, models.AuditTargetUser, &user.ID, nil)
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetDocument godoc
+// @Summary Get any document's metadata and permissions
+// @Description Returns a document's metadata without its content, including deleted documents, together with all user permissions in any status and all team permissions. Site administrators only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Document ID"
+// @Success 200 {object} AdminDocumentResponse
+// @Failure 400 {object} ErrorResponse "Invalid document ID"
+// @Failure 403 {object} ErrorResponse "Site administrator access required"
+// @Failure 404 {object} ErrorResponse "Document not found"
+// @Router /api/v1/admin/documents/{id} [get]
+func (h *AdminHandler) GetDocument(c *gin.Context) {
+	doc, ok := h.targetDocument(c)
+	if !ok {
+		return
+	}
+
+	permissions, err := h.repo.Permission.GetAllByDocument(doc.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve permissions"})
+		return
+	}
+	teamPermissions, err := h.repo.Team.GetDocumentPermissions(doc.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve team permissions"})
+		return
+	}
+
+	emails := make(map[uuid.UUID]string)
+	email := func(userID uuid.UUID) string {
+		if address, ok := emails[userID]; ok {
+			return address
+		}
+		var user models.User
+		if err := h.repo.User.GetByID(userID, &user); err != nil {
+			log.Printf("Admin GetDocument: user %s could not be loaded: %v", userID, err)
+		}
+		emails[userID] = user.Email
+		return user.Email
+	}
+
+	response := AdminDocumentResponse{
+		DocumentResponse: documentToResponse(doc),
+		OwnerEmail:       email(doc.OwnerID),
+		Permissions:      make([]PermissionResponse, len(permissions)),
+		TeamPermissions:  make([]AdminTeamPermissionResponse, len(teamPermissions)),
+	}
+	response.Content = nil
+	if doc.DeletedAt.Valid {
+		response.DeletedAt = &doc.DeletedAt.Time
+	}
+	for i, perm := range permissions {
+		response.Permissions[i] = PermissionResponse{
+			ID:         perm.ID,
+			DocumentID: perm.DocumentID,
+			UserID:     perm.UserID,
+			UserEmail:  email(perm.UserID),
+			AccessType: perm.AccessType,
+			Status:     perm.Status,
+			ExpiresAt:  perm.ExpiresAt,
+		}
+		if perm.SharedBy != uuid.Nil {
+			response.Permissions[i].SharedBy = email(perm.SharedBy)
+		}
+	}
+	for i, perm := range teamPermissions {
+		response.TeamPermissions[i] = AdminTeamPermissionResponse{
+			ID:         perm.ID,
+			TeamID:     perm.TeamID,
+			AccessType: perm.AccessType,
+			SharedBy:   perm.SharedBy,
+			CreatedAt:  perm.CreatedAt,
+		}
+	}
+	h.audit(c, models.AuditActionDocumentView, models.AuditTargetDocument, &doc.ID, nil)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RestoreDocument godoc
+// @Summary Restore a deleted document
+// @Description Restores a soft-deleted document so that it is listed and accessible again with its existing versions and permissions. Site administrators only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Document ID"
+// @Success 200 {object} DocumentResponse
+// @Failure 400 {object} ErrorResponse "Invalid document ID"
+// @Failure 403 {object} ErrorResponse "Site administrator access required"
+// @Failure 404 {object} ErrorResponse "Document not found"
+// @Failure 409 {object} ErrorResponse "Document is not deleted"
+// @Router /api/v1/admin/documents/{id}/restore [post]
+func (h *AdminHandler) RestoreDocument(c *gin.Context) {
+	doc, ok := h.targetDocument(c)
+	if !ok {
+		return
+	}
+	if !doc.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Document is not deleted"})
+		return
+	}
+
+	restored, err := h.repo.Document.Restore(doc.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to restore document"})
+		return
+	}
+	if restored == 0 {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Document is not deleted"})
+		return
+	}
+	h.audit(c, models.AuditActionDocumentRestore, models.AuditTargetDocument, &doc.ID, gin.H{"deleted_at": doc.DeletedAt.Time})
+
+	doc.DeletedAt = gorm.DeletedAt{}
+	response := documentToResponse(doc)
+	response.Content = nil
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAuditLogs godoc
+// @Summary List the audit log
+// @Description Lists administrative actions newest first, optionally filtered by the acting administrator, the target or the action. Site administrators only.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param actor_id query string false "Administrator user ID"
+// @Param target_id query string false "Target user or document ID"
+// @Param action query string false "Action, e.g. user.disable"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} AuditLogListResponse
+// @Failure 400 {object} ErrorResponse "Invalid filter"
+// @Failure 403 {object} ErrorResponse "Site administrator access required"
+// @Router /api/v1/admin/audit-logs [get]
+func (h *AdminHandler) GetAuditLogs(c *gin.Context) {
+	page, pageSize := parsePagination(c)
+	filter := repository.AuditLogFilter{
+		Action: models.AuditAction(c.Query("action")),
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+	}
+	var err error
+	if filter.ActorID, err = parseOptionalUUID(c.Query("actor_id")); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid actor_id"})
+		return
+	}
+	if filter.TargetID, err = parseOptionalUUID(c.Query("target_id")); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid target_id"})
+		return
+	}
+
+	entries, total, err := h.repo.AuditLog.List(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve audit log"})
+		return
+	}
+
+	items := make([]AuditLogResponse, len(entries))
+	for i := range entries {
+		items[i] = auditLogToResponse(&entries[i])
+	}
+	c.JSON(http.StatusOK, AuditLogListResponse{
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+// parseOptionalUUID, boş değer için nil döndürür.
+func parseOptionalUUID(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// targetUser, path'teki user_id ile kullanıcıyı yükler; bulunamazsa yanıtı yazar ve false döner.
+func (h *AdminHandler) targetUser(c *gin.Context) (*models.User, bool) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user ID"})
+		return nil, false
+	}
+
+	var user models.User
+	if err := h.repo.User.GetByID(userID, &user); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve user"})
+		}
+		return nil, false
+	}
+	return &user, true
+}
+
+// targetDocument, path'teki id ile belgeyi silinmiş olsa bile yükler; bulunamazsa yanıtı yazar ve false döner.
+func (h *AdminHandler) targetDocument(c *gin.Context) (*models.Document, bool) {
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid document ID"})
+		return nil, false
+	}
+
+	doc, err := h.repo.Document.GetByIDWithDeleted(docID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Document not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve document"})
+		}
+		return nil, false
+	}
+	return doc, true
+}
+
+// disconnect, kullanıcının açık tüm belgelerdeki canlı düzenleme ve sohbet bağlantılarını kapatır.
+func (h *AdminHandler) disconnect(userID uuid.UUID) {
+	for _, disconnector := range h.disconnectors {
+		for _, docID := range disconnector.ActiveDocuments() {
+			disconnector.DisconnectUser(docID, userID)
+		}
+	}
+}
+
+// audit, yöneticinin işlemini denetim kaydına yazar. İşlem bu noktada tamamlanmış olduğundan
+// kayıt yazılamazsa yanıt değişmez; hata loglanır.
+func (h *AdminHandler) audit(c *gin.Context, action models.AuditAction, targetType models.AuditTargetType, targetID *uuid.UUID, details gin.H) {
+	actorID, _ := utils.GetUserIDFromContext(c)
+	entry := &models.AuditLog{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IPAddress:  c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	}
+	if details != nil {
+		data, err := json.Marshal(details)
+		if err != nil {
+			log.Printf("Audit log details for %s could not be encoded: %v", action, err)
+		}
+		entry.Details = data
+	}
+	if err := h.repo.AuditLog.Create(entry); err != nil {
+		log.Printf("Audit log entry %s by %s for %v could not be saved: %v", action, actorID, targetID, err)
+	}
+}
@@ -1,54 +1,118 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/dione-docs-backend/internal/config"
 	"github.com/dione-docs-backend/internal/models"
 	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/services"
 	"github.com/dione-docs-backend/internal/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthHandler struct {
-	repo *repository.Repository
-	cfg  *config.Config
+	repo     *repository.Repository
+	cfg      *config.Config
+	sessions *services.SessionService
+	accounts *services.AccountService
+	mfa      *services.MFAService
 }
 
-func NewAuthHandler(repo *repository.Repository, cfg *config.Config) *AuthHandler {
+func NewAuthHandler(repo *repository.Repository, cfg *config.Config, sessions *services.SessionService, accounts *services.AccountService, mfa *services.MFAService) *AuthHandler {
 	return &AuthHandler{
-		repo: repo,
-		cfg:  cfg,
+		repo:     repo,
+		cfg:      cfg,
+		sessions: sessions,
+		accounts: accounts,
+		mfa:      mfa,
 	}
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required"`
+	DeviceName string `json:"device_name" binding:"max=100"`
 }
 
 type RegisterRequest struct {
-	Username string `json:"username" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
+	Username   string `json:"username" binding:"required"`
+	FullName   string `json:"full_name" binding:"max=100"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required,min=8"`
+	DeviceName string `json:"device_name" binding:"max=100"`
+}
+
+// LoginMFARequest, girişin ikinci adımıdır. Code, doğrulama uygulamasındaki TOTP kodu veya bir kurtarma kodudur.
+type LoginMFARequest struct {
+	MFAToken   string `json:"mfa_token" binding:"required"`
+	Code       string `json:"code" binding:"required"`
+	DeviceName string `json:"device_name" binding:"max=100"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
 	Password string `json:"password" binding:"required,min=8"`
 }
 
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
 type UserResponse struct {
-	UserId   string `json:"userId"`
-	Email    string `json:"email"`
-	FullName string `json:"fullName"`
+	UserId        string            `json:"userId"`
+	Username      string            `json:"username"`
+	Email         string            `json:"email"`
+	FullName      string            `json:"fullName"`
+	EmailVerified bool              `json:"emailVerified"`
+	PendingEmail  string            `json:"pendingEmail,omitempty"`
+	IsSiteAdmin   bool              `json:"isSiteAdmin,omitempty"`
+	AvatarURLs    map[string]string `json:"avatarUrls,omitempty"` // boyut (piksel) -> URL
+}
+
+// TokenResponse, giriş, kayıt ve token yenileme sonrasında dönen token çiftidir.
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	SessionID    string `json:"session_id"`
+}
+
+// MFAChallengeResponse, iki adımlı doğrulaması açık kullanıcının şifresi doğrulandığında döner.
+// İstemci MFAToken'ı kodla birlikte /auth/login/mfa'ya göndererek girişi tamamlar.
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int    `json:"expires_in"`
 }
 
-type LoginResponse struct {
-	Token string `json:"token"`
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name,omitempty"`
+	IPAddress  string    `json:"ip_address,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	Current    bool      `json:"current"`
 }
 
-type RegisterResponse struct {
-	Token string `json:"token"`
+type RevokeSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
 }
 
 type ErrorResponse struct {
@@ -57,14 +121,16 @@ type ErrorResponse struct {
 
 // LoginHandler godoc
 // @Summary Login endpoint
-// @Description Authenticates a user and returns a JWT token.
+// @Description Authenticates a user, starts a new session and returns a short-lived access token with a refresh token. If the user has two-factor authentication enabled, an MFA challenge is returned instead and the login is completed with /auth/login/mfa.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param login body LoginRequest true "Login credentials"
-// @Success 200 {object} LoginResponse
+// @Success 200 {object} TokenResponse
+// @Success 202 {object} MFAChallengeResponse "Two-factor code required"
 // @Failure 400 {object} ErrorResponse "Invalid request data"
 // @Failure 401 {object} ErrorResponse "Invalid credentials"
+// @Failure 403 {object} ErrorResponse "Account is disabled"
 // @Router /api/v1/login [post]
 func (h *AuthHandler) LoginHandler(c *gin.Context) {
 	var req LoginRequest
@@ -83,28 +149,95 @@ func (h *AuthHandler) LoginHandler(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid credentials"})
 		return
 	}
+	if user.IsDisabled() {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Account is disabled"})
+		return
+	}
+
+	mfaEnabled, err := h.mfa.IsEnabled(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to check two-factor settings"})
+		return
+	}
+	if mfaEnabled {
+		token, expiresIn, err := h.mfa.IssueChallenge(user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to generate token"})
+			return
+		}
+		c.JSON(http.StatusAccepted, MFAChallengeResponse{MFARequired: true, MFAToken: token, ExpiresIn: expiresIn})
+		return
+	}
+
+	pair, err := h.sessions.Start(user.ID, sessionMetadata(c, req.DeviceName))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newTokenResponse(pair))
+}
+
+// LoginMFAHandler godoc
+// @Summary Complete login with a two-factor code
+// @Description Completes a login that returned an MFA challenge, using a code from the authenticator app or an unused recovery code.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param login body LoginMFARequest true "MFA challenge token and code"
+// @Success 200 {object} TokenResponse
+// @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 401 {object} ErrorResponse "Invalid or expired challenge, or invalid code"
+// @Failure 403 {object} ErrorResponse "Account is disabled"
+// @Router /api/v1/auth/login/mfa [post]
+func (h *AuthHandler) LoginMFAHandler(c *gin.Context) {
+	var req LoginMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request data"})
+		return
+	}
+
+	userID, err := h.mfa.CompleteChallenge(req.MFAToken, req.Code)
+	switch {
+	case errors.Is(err, services.ErrInvalidMFAToken), errors.Is(err, services.ErrMFANotEnabled):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid or expired two-factor challenge"})
+		return
+	case errors.Is(err, services.ErrInvalidMFACode):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid two-factor code"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify two-factor code"})
+		return
+	}
+
+	// Hesap, şifre doğrulandıktan sonra devre dışı bırakılmış olabilir.
+	var user models.User
+	if err := h.repo.User.GetByID(userID, &user); err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid or expired two-factor challenge"})
+		return
+	}
+	if user.IsDisabled() {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Account is disabled"})
+		return
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": user.ID.String(),
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
-	})
-	tokenString, err := token.SignedString([]byte(h.cfg.JWTSecret))
+	pair, err := h.sessions.Start(userID, sessionMetadata(c, req.DeviceName))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, LoginResponse{Token: tokenString})
+	c.JSON(http.StatusOK, newTokenResponse(pair))
 }
 
 // RegisterHandler godoc
 // @Summary Register endpoint
-// @Description Registers a new user and returns a JWT token.
+// @Description Registers a new user, starts a new session and returns a short-lived access token with a refresh token.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param register body RegisterRequest true "Registration data"
-// @Success 201 {object} RegisterResponse
+// @Success 201 {object} TokenResponse
 // @Failure 400 {object} ErrorResponse "Invalid request data"
 // @Failure 409 {object} ErrorResponse "Email already in use"
 // @Router /api/v1/register [post]
@@ -130,6 +263,7 @@ func (h *AuthHandler) RegisterHandler(c *gin.Context) {
 	user := &models.User{
 		ID:           uuid.New(),
 		Username:     req.Username,
+		FullName:     strings.TrimSpace(req.FullName),
 		Email:        req.Email,
 		PasswordHash: string(hashedPassword),
 	}
@@ -139,17 +273,328 @@ func (h *AuthHandler) RegisterHandler(c *gin.Context) {
 		return
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": user.ID.String(),
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
-	})
-	tokenString, err := token.SignedString([]byte(h.cfg.JWTSecret))
+	// Bu adrese hesap açılmadan önce gönderilmiş belge davetleri bekleyen davetiyelere dönüşür.
+	if converted, err := h.repo.EmailInvite.ConvertForUser(user); err != nil {
+		log.Printf("Could not convert email invitations for %s: %v", user.Email, err)
+	} else if converted > 0 {
+		log.Printf("Converted %d email invitations for %s", converted, user.Email)
+	}
+
+	if err := h.accounts.SendVerification(user); err != nil {
+		log.Printf("Could not send verification email to %s: %v", user.Email, err)
+	}
+
+	pair, err := h.sessions.Start(user.ID, sessionMetadata(c, req.DeviceName))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, RegisterResponse{Token: tokenString})
+	c.JSON(http.StatusCreated, newTokenResponse(pair))
+}
+
+// RefreshHandler godoc
+// @Summary Refresh access token
+// @Description Exchanges a refresh token for a new access token. The refresh token is rotated; presenting an already used refresh token revokes its session.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param refresh body RefreshRequest true "Refresh token"
+// @Success 200 {object} TokenResponse
+// @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 401 {object} ErrorResponse "Invalid or expired refresh token"
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) RefreshHandler(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request data"})
+		return
+	}
+
+	pair, err := h.sessions.Refresh(req.RefreshToken, sessionMetadata(c, ""))
+	if errors.Is(err, services.ErrInvalidRefreshToken) {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid or expired refresh token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to refresh token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newTokenResponse(pair))
+}
+
+// LogoutHandler godoc
+// @Summary Logout
+// @Description Revokes the current session so that its access and refresh tokens stop working.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 204 "Logged out"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /api/v1/auth/logout [post]
+func (h *AuthHandler) LogoutHandler(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+	sessionID, err := utils.GetSessionIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	if _, err := h.sessions.Revoke(userID, sessionID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to logout"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetSessions godoc
+// @Summary List active sessions
+// @Description Returns the active sessions of the current user, marking the one used for this request.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} SessionResponse
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /api/v1/auth/sessions [get]
+func (h *AuthHandler) GetSessions(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+	currentID, _ := utils.GetSessionIDFromContext(c)
+
+	sessions, err := h.repo.Session.GetActiveByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve sessions"})
+		return
+	}
+
+	response := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, SessionResponse{
+			ID:         session.ID.String(),
+			DeviceName: session.DeviceName,
+			IPAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			CreatedAt:  session.CreatedAt,
+			Current:    session.ID == currentID,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeSession godoc
+// @Summary Revoke a session
+// @Description Revokes one of the current user's sessions, signing that device out.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Param session_id path string true "Session ID"
+// @Success 204 "Session revoked"
+// @Failure 400 {object} ErrorResponse "Invalid session ID"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 404 {object} ErrorResponse "Session not found"
+// @Router /api/v1/auth/sessions/{session_id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+	sessionID, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid session ID"})
+		return
+	}
+
+	revoked, err := h.sessions.Revoke(userID, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to revoke session"})
+		return
+	}
+	if !revoked {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Session not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeAllSessions godoc
+// @Summary Revoke all other sessions
+// @Description Revokes every session of the current user except the one used for this request.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} RevokeSessionsResponse
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /api/v1/auth/sessions [delete]
+func (h *AuthHandler) RevokeAllSessions(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+	currentID, err := utils.GetSessionIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	revoked, err := h.sessions.RevokeAll(userID, &currentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, RevokeSessionsResponse{Revoked: revoked})
+}
+
+// ForgotPasswordHandler godoc
+// @Summary Request a password reset
+// @Description Emails a single-use password reset link if the address belongs to an account. The response is the same whether or not the account exists.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body ForgotPasswordRequest true "Account email"
+// @Success 202 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Router /api/v1/auth/password/forgot [post]
+func (h *AuthHandler) ForgotPasswordHandler(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request data"})
+		return
+	}
+
+	if err := h.accounts.RequestPasswordReset(req.Email); err != nil {
+		log.Printf("Could not send password reset email to %s: %v", req.Email, err)
+	}
+
+	c.JSON(http.StatusAccepted, MessageResponse{Message: "If an account exists for this email, a password reset link has been sent"})
+}
+
+// ResetPasswordHandler godoc
+// @Summary Reset password
+// @Description Sets a new password using a token from a password reset email. The token can be used once; all sessions of the user are signed out.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "Reset token and new password"
+// @Success 204 "Password changed"
+// @Failure 400 {object} ErrorResponse "Invalid request data or invalid/expired token"
+// @Router /api/v1/auth/password/reset [post]
+func (h *AuthHandler) ResetPasswordHandler(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request data"})
+		return
+	}
+
+	err := h.accounts.ResetPassword(req.Token, req.Password)
+	if errors.Is(err, services.ErrInvalidAccountToken) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid or expired token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to reset password"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// VerifyEmailHandler godoc
+// @Summary Verify email address
+// @Description Marks the account's email address as verified using a token from a verification email.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body VerifyEmailRequest true "Verification token"
+// @Success 204 "Email verified"
+// @Failure 400 {object} ErrorResponse "Invalid request data or invalid/expired token"
+// @Router /api/v1/auth/email/verify [post]
+func (h *AuthHandler) VerifyEmailHandler(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request data"})
+		return
+	}
+
+	err := h.accounts.VerifyEmail(req.Token)
+	if errors.Is(err, services.ErrInvalidAccountToken) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid or expired token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify email"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResendVerificationHandler godoc
+// @Summary Resend verification email
+// @Description Sends a new email verification link to the current user. Earlier links stop working.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} MessageResponse
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 409 {object} ErrorResponse "Email already verified"
+// @Router /api/v1/auth/email/verification [post]
+func (h *AuthHandler) ResendVerificationHandler(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	var user models.User
+	if err := h.repo.User.GetByID(userID, &user); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		return
+	}
+	if user.EmailVerified {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Email already verified"})
+		return
+	}
+
+	if err := h.accounts.SendVerification(&user); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to send verification email"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, MessageResponse{Message: "Verification email sent"})
+}
+
+// sessionMetadata, oturum kaydında saklanacak istemci bilgilerini istekten toplar.
+func sessionMetadata(c *gin.Context, deviceName string) services.SessionMetadata {
+	return services.SessionMetadata{
+		DeviceName: deviceName,
+		IPAddress:  c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	}
+}
+
+func newTokenResponse(pair *services.TokenPair) TokenResponse {
+	return TokenResponse{
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
+		SessionID:    pair.SessionID.String(),
+	}
 }
 
 // GetCurrentUser godoc
@@ -177,9 +622,5 @@ func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	}
 
 	// Kullanıcı bilgilerini dön
-	c.JSON(http.StatusOK, UserResponse{
-		UserId:   user.ID.String(),
-		Email:    user.Email,
-		FullName: user.Username, // Eğer User model'inde FullName field'ı varsa onu kullan
-	})
+	c.JSON(http.StatusOK, newUserResponse(&user))
 }
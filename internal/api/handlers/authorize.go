@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/dione-docs-backend/internal/authz"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// authorize, kullanıcının belge üzerinde işlemi yapabildiğini doğrular. Yetki yoksa denied
+// mesajıyla 403, kontrol başarısız olursa 500 yazar ve false döner.
+func authorize(c *gin.Context, authorizer *authz.Authorizer, userID uuid.UUID, action authz.Action, doc *models.Document, denied string) bool {
+	allowed, err := authorizer.Can(userID, action, doc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Yetki kontrolü sırasında bir hata oluştu."})
+		return false
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: denied})
+		return false
+	}
+	return true
+}
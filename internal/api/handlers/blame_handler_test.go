@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	middleware "github.com/dione-docs-backend/internal/api/middlewares"
+	"github.com/dione-docs-backend/internal/authz"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// asQueryUser, kimlik doğrulama middleware'inin yerine "user" sorgu parametresindeki kullanıcıyı context'e yazar.
+func asQueryUser(c *gin.Context) {
+	c.Set("user_id", c.Query("user"))
+	c.Next()
+}
+
+func TestBlameCreditsConcurrentEditors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	owner, alice, bob := uuid.New(), uuid.New(), uuid.New()
+	doc := &models.Document{
+		ID:      uuid.New(),
+		OwnerID: owner,
+		Version: 1,
+		Content: []byte(`{"ops":[{"insert":"Base\n"}]}`),
+	}
+	attributions := newFakeAttributions()
+	repo := &repository.Repository{
+		Document:    newFakeDocuments(doc),
+		Permission:  &fakePermissions{access: map[uuid.UUID]string{alice: "editor", bob: "editor"}},
+		Comment:     &fakeComments{},
+		Suggestion:  &fakeSuggestions{},
+		Attribution: attributions,
+		User: &fakeUsers{users: map[uuid.UUID]models.User{
+			owner: {ID: owner, Username: "owner"},
+			alice: {ID: alice, Username: "alice"},
+			bob:   {ID: bob, Username: "bob"},
+		}},
+	}
+	authorizer := authz.NewAuthorizer(repo.Permission)
+	hubs := NewHubManager(repo)
+	docHandler := NewDocumentHandler(repo, hubs, authorizer)
+	blameHandler := NewBlameHandler(repo, hubs, services.NewBlameService(repo.Attribution), authorizer)
+
+	router := gin.New()
+	router.GET("/documents/:id/ws", asQueryUser, middleware.RequireAction(authorizer, repo.Document, authz.ActionView, "id"), hubs.ServeWs)
+	router.PUT("/documents/:id/content", docHandler.UpdateDocumentContent)
+	router.GET("/documents/:id/blame", asQueryUser, blameHandler.GetDocumentBlame)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	connect := func(userID uuid.UUID) *websocket.Conn {
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/documents/" + doc.ID.String() + "/ws?user=" + userID.String()
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			t.Fatalf("dial as %s: %v", userID, err)
+		}
+		return conn
+	}
+	aliceConn := connect(alice)
+	defer aliceConn.Close()
+	bobConn := connect(bob)
+	defer bobConn.Close()
+
+	waitForOperations := func(want int) {
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			hubs.Flush(doc.ID)
+			if attributions.operationCount(doc.ID) == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("recorded %d operations, want %d", attributions.operationCount(doc.ID), want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	send := func(conn *websocket.Conn, ops string) {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"version":1,"ops":`+ops+`}`)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	// İki istemci de 1. versiyonu görerek düzenler. Bob'un operasyonu Alice'inki uygulandıktan
+	// sonra ulaşır ve ona göre yeniden temellendirilmelidir.
+	send(aliceConn, `[{"insert":"Alice "}]`)
+	waitForOperations(2) // temel kayıt ve Alice'in operasyonu
+	send(bobConn, `[{"retain":4},{"insert":" Bob"}]`)
+	waitForOperations(3)
+
+	// Canlı oturumun içeriği kaydedilir; bu kayıt yazar bilgisini değiştirmemelidir.
+	saved := httptest.NewRecorder()
+	body := bytes.NewBufferString(`{"content":{"ops":[{"insert":"Alice Base Bob\n"}]}}`)
+	router.ServeHTTP(saved, httptest.NewRequest(http.MethodPut, "/documents/"+doc.ID.String()+"/content", body))
+	if saved.Code != http.StatusOK {
+		t.Fatalf("save content: status %d: %s", saved.Code, saved.Body)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/documents/"+doc.ID.String()+"/blame?user="+owner.String(), nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("blame: status %d: %s", recorder.Code, recorder.Body)
+	}
+
+	var response DocumentBlameResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode blame: %v", err)
+	}
+	want := []services.BlameRange{
+		{Start: 0, Length: 6, UserID: alice, Version: 2},  // "Alice "
+		{Start: 6, Length: 4, UserID: owner, Version: 1},  // "Base"
+		{Start: 10, Length: 4, UserID: bob, Version: 2},   // " Bob"
+		{Start: 14, Length: 1, UserID: owner, Version: 1}, // "\n"
+	}
+	if len(response.Ranges) != len(want) {
+		t.Fatalf("ranges = %+v, want %+v", response.Ranges, want)
+	}
+	for i := range want {
+		if response.Ranges[i] != want[i] {
+			t.Errorf("range %d = %+v, want %+v", i, response.Ranges[i], want[i])
+		}
+	}
+	if len(response.Authors) != 3 {
+		t.Errorf("authors = %+v, want owner, alice and bob", response.Authors)
+	}
+}
@@ -7,13 +7,13 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/dione-docs-backend/internal/authz"
 	"github.com/dione-docs-backend/internal/collaboration"
 	"github.com/dione-docs-backend/internal/config"
 	"github.com/dione-docs-backend/internal/models"
 	"github.com/dione-docs-backend/internal/repository"
 	"github.com/dione-docs-backend/internal/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
@@ -23,18 +23,21 @@ type ChatHandler struct {
 	hubManager *ChatHubManager
 	wsUpgrader websocket.Upgrader
 	config     *config.Config
+	authorizer *authz.Authorizer
 }
 
 type ChatHubManager struct {
-	hubs map[uuid.UUID]*collaboration.ChatHub
-	mu   sync.Mutex
-	repo *repository.Repository
+	hubs       map[uuid.UUID]*collaboration.ChatHub
+	mu         sync.Mutex
+	repo       *repository.Repository
+	authorizer *authz.Authorizer
 }
 
-func NewChatHubManager(repo *repository.Repository) *ChatHubManager {
+func NewChatHubManager(repo *repository.Repository, authorizer *authz.Authorizer) *ChatHubManager {
 	return &ChatHubManager{
-		hubs: make(map[uuid.UUID]*collaboration.ChatHub),
-		repo: repo,
+		hubs:       make(map[uuid.UUID]*collaboration.ChatHub),
+		repo:       repo,
+		authorizer: authorizer,
 	}
 }
 
@@ -46,17 +49,41 @@ func (m *ChatHubManager) GetOrCreateHub(docID uuid.UUID) *collaboration.ChatHub
 		return hub
 	}
 
-	hub := collaboration.NewChatHub(docID, m.repo)
+	hub := collaboration.NewChatHub(docID, m.repo, m.authorizer)
 	m.hubs[docID] = hub
 	go hub.Run()
 	return hub
 }
 
-func NewChatHandler(repo *repository.Repository, hubManager *ChatHubManager, cfg *config.Config) *ChatHandler {
+// DisconnectUser, belge için aktif bir sohbet hub'ı varsa kullanıcının bağlantılarını kapatır.
+func (m *ChatHubManager) DisconnectUser(docID, userID uuid.UUID) {
+	m.mu.Lock()
+	hub, ok := m.hubs[docID]
+	m.mu.Unlock()
+
+	if ok {
+		hub.DisconnectUser(userID)
+	}
+}
+
+// ActiveDocuments, sohbet hub'ı açık olan belgeleri döndürür.
+func (m *ChatHubManager) ActiveDocuments() []uuid.UUID {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	docIDs := make([]uuid.UUID, 0, len(m.hubs))
+	for docID := range m.hubs {
+		docIDs = append(docIDs, docID)
+	}
+	return docIDs
+}
+
+func NewChatHandler(repo *repository.Repository, hubManager *ChatHubManager, cfg *config.Config, authorizer *authz.Authorizer) *ChatHandler {
 	return &ChatHandler{
 		repo:       repo,
 		hubManager: hubManager,
 		config:     cfg,
+		authorizer: authorizer,
 		wsUpgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
@@ -68,44 +95,21 @@ func NewChatHandler(repo *repository.Repository, hubManager *ChatHubManager, cfg
 }
 
 func (h *ChatHandler) ServeChatWs(c *gin.Context) {
-	log.Println("--- [CHAT-HANDLER-DEBUG] ServeChatWs: Connection request received. ---")
-
-	tokenString := c.Query("token")
-	if tokenString == "" {
-		log.Println("[CHAT-HANDLER-DEBUG] FATAL: Token not found in query. Responding with 401.")
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Authorization token not provided"})
-		return
-	}
-
-	claims := &utils.Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(h.config.JWTSecret), nil
-	})
-	if err != nil || !token.Valid {
-		log.Printf("[CHAT-HANDLER-DEBUG] FATAL: Token is invalid. Error: %v. Responding with 401.", err)
-		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid or expired token"})
-		return
-	}
-
-	userID, err := uuid.Parse(claims.UserID)
+	// Token (Authorization başlığı veya ?token=) JWTMiddleware tarafından doğrulandı.
+	userID, err := utils.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("[CHAT-HANDLER-DEBUG] FATAL: UserID in token is not a valid UUID. Error: %v. Responding with 400.", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid user identifier in token"})
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Authorization token not provided"})
 		return
 	}
 
 	docIDStr := c.Param("id")
 	docID, err := uuid.Parse(docIDStr)
 	if err != nil {
-		log.Printf("[CHAT-HANDLER-DEBUG] FATAL: DocumentID in path is not a valid UUID. Error: %v. Responding with 400.", err)
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid document ID"})
 		return
 	}
 
-	log.Println("[CHAT-HANDLER-DEBUG] Step 5: Checking document access permissions...")
-	if !h.canUserAccessDocument(userID, docID) {
-		log.Printf("[CHAT-HANDLER-DEBUG] FATAL: canUserAccessDocument returned false. User %s denied access to doc %s. Responding with 403.", userID, docID)
-		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Access to this document is denied"})
+	if !h.authorizeDocument(c, userID, docID) {
 		return
 	}
 
@@ -113,7 +117,7 @@ func (h *ChatHandler) ServeChatWs(c *gin.Context) {
 
 	conn, err := h.wsUpgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
-		log.Printf("[CHAT-HANDLER-DEBUG] FATAL: Failed to upgrade connection to WebSocket. Error: %v", err)
+		log.Printf("Failed to upgrade chat connection for doc %s: %v", docID, err)
 		return
 	}
 
@@ -134,8 +138,7 @@ func (h *ChatHandler) GetMessages(c *gin.Context) {
 		return
 	}
 
-	if !h.canUserAccessDocument(userID, docID) {
-		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Access to this document is denied"})
+	if !h.authorizeDocument(c, userID, docID) {
 		return
 	}
 
@@ -148,20 +151,20 @@ func (h *ChatHandler) GetMessages(c *gin.Context) {
 	c.JSON(http.StatusOK, messages)
 }
 
-func (h *ChatHandler) canUserAccessDocument(userID, docID uuid.UUID) bool {
+// authorizeDocument, belgenin aktif çalışma alanında bulunduğunu ve kullanıcının belgeyi
+// görüntüleyebildiğini doğrular. Sorun varsa yanıtı yazar ve false döner.
+func (h *ChatHandler) authorizeDocument(c *gin.Context, userID, docID uuid.UUID) bool {
+	organizationID, _ := utils.GetOrganizationFromContext(c)
 	var doc models.Document
-	if err := h.repo.Document.GetByID(docID, &doc); err != nil {
+	if err := h.repo.Document.InOrganization(organizationID).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Document not found"})
 		return false
 	}
 
-	if doc.OwnerID == userID || doc.IsPublic {
-		return true
-	}
-
-	permission, err := h.repo.Permission.GetAcceptedByDocumentAndUser(docID, userID)
-	if err != nil || permission == nil {
+	allowed, err := h.authorizer.Can(userID, authz.ActionView, &doc)
+	if err != nil || !allowed {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Access to this document is denied"})
 		return false
 	}
-
 	return true
 }
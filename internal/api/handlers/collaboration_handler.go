@@ -5,8 +5,12 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/dione-docs-backend/internal/authz"
 	"github.com/dione-docs-backend/internal/collaboration"
+	"github.com/dione-docs-backend/internal/delta"
+	"github.com/dione-docs-backend/internal/models"
 	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -21,30 +25,202 @@ var upgrader = websocket.Upgrader{
 }
 
 type HubManager struct {
-	hubs    map[uuid.UUID]*collaboration.Hub
-	mu      sync.Mutex
-	docRepo repository.DocumentRepository
+	hubs map[uuid.UUID]*collaboration.Hub
+	refs map[uuid.UUID]int // hub'a bağlı veya bağlanmakta olan istemci sayısı
+	mu   sync.Mutex
+	repo *repository.Repository
 }
 
-func NewHubManager(docRepo repository.DocumentRepository) *HubManager {
+func NewHubManager(repo *repository.Repository) *HubManager {
 	return &HubManager{
-		hubs:    make(map[uuid.UUID]*collaboration.Hub),
-		docRepo: docRepo,
+		hubs: make(map[uuid.UUID]*collaboration.Hub),
+		refs: make(map[uuid.UUID]int),
+		repo: repo,
 	}
 }
 
-func (m *HubManager) GetOrCreateHub(docID uuid.UUID) *collaboration.Hub {
+// acquireHub, belgenin hub'ını döndürür, yoksa oluşturur ve hub'ı bir istemci için ayırır.
+// Her acquireHub çağrısına karşılık releaseHub çağrılmalıdır.
+func (m *HubManager) acquireHub(docID uuid.UUID) *collaboration.Hub {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	hub, ok := m.hubs[docID]
+	if !ok {
+		hub = collaboration.NewHub(docID, m.repo)
+		m.hubs[docID] = hub
+		go hub.Run()
+	}
+	m.refs[docID]++
+	return hub
+}
+
+// releaseHub, acquireHub ile yapılan ayırmayı bırakır. Hub'ı kullanan istemci kalmadıysa hub
+// durdurulur ve listeden çıkarılır; sonraki bağlantı belgeyi veritabanından yeniden yükler.
+func (m *HubManager) releaseHub(docID uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.refs[docID]--
+	if m.refs[docID] > 0 {
+		return
+	}
 	if hub, ok := m.hubs[docID]; ok {
-		return hub
+		hub.Stop()
 	}
+	delete(m.hubs, docID)
+	delete(m.refs, docID)
+}
 
-	hub := collaboration.NewHub(docID, m.docRepo)
-	m.hubs[docID] = hub
-	go hub.Run()
-	return hub
+// NotifyReload, belge için aktif bir hub varsa bağlı istemcilerin belgeyi
+// verilen içerikle yeniden yüklemesini sağlar. Hub yoksa bir şey yapmaz.
+func (m *HubManager) NotifyReload(docID uuid.UUID, content []byte, version int) {
+	m.mu.Lock()
+	hub, ok := m.hubs[docID]
+	m.mu.Unlock()
+
+	if ok {
+		hub.Reload(content, version)
+	}
+}
+
+// activeHub, belge için aktif bir hub varsa onu döndürür.
+func (m *HubManager) activeHub(docID uuid.UUID) (*collaboration.Hub, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hub, ok := m.hubs[docID]
+	return hub, ok
+}
+
+// AnchorComment, yorumu kaydeder. Belge için aktif bir hub varsa çapa, istemcinin gördüğü
+// baseVersion versiyonundan bu yana uygulanan operasyonlara göre kaydırılır ve izlemeye alınır.
+func (m *HubManager) AnchorComment(docID uuid.UUID, comment *models.Comment, baseVersion int, save func(*models.Comment) error) error {
+	if hub, ok := m.activeHub(docID); ok {
+		return hub.AnchorComment(comment, baseVersion, save)
+	}
+	return save(comment)
+}
+
+// UntrackComment, silinen yorum dizisinin çapasını aktif hub'ın izlemesinden çıkarır.
+func (m *HubManager) UntrackComment(docID, commentID uuid.UUID) {
+	if hub, ok := m.activeHub(docID); ok {
+		hub.UntrackComment(commentID)
+	}
+}
+
+// NotifyComment, belgeyi açık tutan istemcilere bir yorum olayını iletir.
+func (m *HubManager) NotifyComment(docID uuid.UUID, event interface{}) {
+	if hub, ok := m.activeHub(docID); ok {
+		hub.Notify(collaboration.OperationTypeComment, event)
+	}
+}
+
+// NotifySuggestion, belgeyi açık tutan istemcilere bir öneri olayını iletir.
+func (m *HubManager) NotifySuggestion(docID uuid.UUID, event collaboration.SuggestionEvent) {
+	if hub, ok := m.activeHub(docID); ok {
+		hub.Notify(collaboration.OperationTypeSuggestion, event)
+	}
+}
+
+// UntrackSuggestion, reddedilen öneriyi aktif hub'ın izlemesinden çıkarır.
+func (m *HubManager) UntrackSuggestion(docID, suggestionID uuid.UUID) {
+	if hub, ok := m.activeHub(docID); ok {
+		hub.UntrackSuggestion(suggestionID)
+	}
+}
+
+// Flush, aktif hub'ın bellekte tuttuğu çapa, öneri ve yazar kayıtlarını kaydeder; böylece
+// veritabanından okunan yorumlar, öneriler ve yazar bilgisi güncel olur.
+func (m *HubManager) Flush(docID uuid.UUID) {
+	if hub, ok := m.activeHub(docID); ok {
+		hub.Flush()
+	}
+}
+
+// Saved, canlı oturumun içeriğinin version numarasıyla kaydedildiğini aktif hub'a bildirir.
+func (m *HubManager) Saved(docID uuid.UUID, version int) {
+	if hub, ok := m.activeHub(docID); ok {
+		hub.Saved(version)
+	}
+}
+
+// RemapContent, belgenin içeriği oldContent'ten newContent'e OT dışında değiştirildiğinde
+// yorum çapalarını ve bekleyen önerileri yeni içeriğe göre yeniden konumlandırır.
+func (m *HubManager) RemapContent(docID uuid.UUID, oldContent, newContent []byte) error {
+	oldDoc, err := delta.ParseDocument(oldContent)
+	if err != nil {
+		return err
+	}
+	newDoc, err := delta.ParseDocument(newContent)
+	if err != nil {
+		return err
+	}
+	change, err := delta.Diff(oldDoc, newDoc)
+	if err != nil {
+		return err
+	}
+
+	if hub, ok := m.activeHub(docID); ok {
+		hub.Rebase(change)
+		return nil
+	}
+
+	threads, err := m.repo.Comment.GetThreads(docID)
+	if err != nil {
+		return err
+	}
+	for _, comment := range threads {
+		start, length := delta.TransformRange(change, comment.AnchorStart, comment.AnchorLength)
+		if start == comment.AnchorStart && length == comment.AnchorLength {
+			continue
+		}
+		if err := m.repo.Comment.UpdateAnchor(comment.ID, start, length); err != nil {
+			return err
+		}
+	}
+
+	pending, err := m.repo.Suggestion.GetByDocumentID(docID, models.SuggestionStatusPending)
+	if err != nil {
+		return err
+	}
+	for _, suggestion := range pending {
+		suggested, err := delta.Parse(suggestion.Change)
+		if err != nil {
+			continue
+		}
+		rebased, err := delta.Transform(change, suggested, true).Marshal()
+		if err != nil {
+			return err
+		}
+		if err := m.repo.Suggestion.UpdateChange(suggestion.ID, rebased); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DisconnectUser, belge için aktif bir hub varsa kullanıcının bağlantılarını kapatır.
+func (m *HubManager) DisconnectUser(docID, userID uuid.UUID) {
+	m.mu.Lock()
+	hub, ok := m.hubs[docID]
+	m.mu.Unlock()
+
+	if ok {
+		hub.DisconnectUser(userID)
+	}
+}
+
+// ActiveDocuments, hub'ı açık olan belgeleri döndürür.
+func (m *HubManager) ActiveDocuments() []uuid.UUID {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	docIDs := make([]uuid.UUID, 0, len(m.hubs))
+	for docID := range m.hubs {
+		docIDs = append(docIDs, docID)
+	}
+	return docIDs
 }
 
 // ServeWs, websocket isteklerini yönetir.
@@ -56,11 +232,24 @@ func (m *HubManager) ServeWs(c *gin.Context) {
 		return
 	}
 
-	hub := m.GetOrCreateHub(docID)
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	// Rol, route'taki RequireAction middleware'i tarafından çözülür.
+	role, _ := c.Get("document_role")
+	documentRole, _ := role.(authz.Role)
+	canEdit := authz.Allowed(documentRole, authz.ActionEdit)
+	canSuggest := authz.Allowed(documentRole, authz.ActionSuggest)
+
+	hub := m.acquireHub(docID)
 
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Println(err)
+		m.releaseHub(docID)
 		return
 	}
 
@@ -69,5 +258,5 @@ func (m *HubManager) ServeWs(c *gin.Context) {
 	// DÜZELTME: Artık client'ı manuel olarak oluşturmuyoruz.
 	// Bunun yerine collaboration paketindeki NewClient fonksiyonunu çağırıyoruz.
 	// Bu fonksiyon, client'ı oluşturup goroutine'lerini kendi içinde başlatacak.
-	collaboration.NewClient(hub, conn, clientID)
+	collaboration.NewClient(hub, conn, clientID, userID, canEdit, canSuggest, func() { m.releaseHub(docID) })
 }
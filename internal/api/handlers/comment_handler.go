@@ -0,0 +1,483 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/dione-docs-backend/internal/authz"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Yorum olaylarının istemcilere iletilen türleri.
+const (
+	CommentEventCreated  = "created"
+	CommentEventReplied  = "replied"
+	CommentEventUpdated  = "updated"
+	CommentEventResolved = "resolved"
+	CommentEventReopened = "reopened"
+	CommentEventDeleted  = "deleted"
+)
+
+type CommentHandler struct {
+	repo       *repository.Repository
+	hubs       *HubManager
+	authorizer *authz.Authorizer
+}
+
+func NewCommentHandler(repo *repository.Repository, hubs *HubManager, authorizer *authz.Authorizer) *CommentHandler {
+	return &CommentHandler{
+		repo:       repo,
+		hubs:       hubs,
+		authorizer: authorizer,
+	}
+}
+
+type CreateCommentRequest struct {
+	Body         string `json:"body" binding:"required,max=10000"`
+	AnchorStart  int    `json:"anchor_start" binding:"min=0"`
+	AnchorLength int    `json:"anchor_length" binding:"min=0"`
+	Quote        string `json:"quote" binding:"max=2000"`
+	Version      *int   `json:"version"` // çapanın ait olduğu OT versiyonu; boşsa güncel belge kabul edilir
+}
+
+type CommentBodyRequest struct {
+	Body string `json:"body" binding:"required,max=10000"`
+}
+
+type CommentResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	DocumentID   uuid.UUID  `json:"document_id"`
+	ParentID     *uuid.UUID `json:"parent_id,omitempty"`
+	AuthorID     uuid.UUID  `json:"author_id"`
+	AuthorName   string     `json:"author_name"`
+	Body         string     `json:"body"`
+	AnchorStart  int        `json:"anchor_start"`
+	AnchorLength int        `json:"anchor_length"`
+	Quote        string     `json:"quote,omitempty"`
+	Resolved     bool       `json:"resolved"`
+	ResolvedAt   *time.Time `json:"resolved_at,omitempty"`
+	ResolvedBy   *uuid.UUID `json:"resolved_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type CommentThreadResponse struct {
+	CommentResponse
+	Replies []CommentResponse `json:"replies"`
+}
+
+// CommentEvent, belgeyi açık tutan editörlere collaboration websocket'i üzerinden iletilir.
+type CommentEvent struct {
+	Action  string          `json:"action"`
+	Comment CommentResponse `json:"comment"`
+}
+
+// commentToResponse, yorumu yanıta dönüştürür. authors, aynı istekte yazar adlarının
+// tekrar tekrar sorgulanmaması için önbellek olarak kullanılır.
+func (h *CommentHandler) commentToResponse(comment *models.Comment, authors map[uuid.UUID]string) CommentResponse {
+	name, ok := authors[comment.AuthorID]
+	if !ok {
+		var user models.User
+		if err := h.repo.User.GetByID(comment.AuthorID, &user); err == nil {
+			name = user.Username
+		}
+		authors[comment.AuthorID] = name
+	}
+
+	return CommentResponse{
+		ID:           comment.ID,
+		DocumentID:   comment.DocumentID,
+		ParentID:     comment.ParentID,
+		AuthorID:     comment.AuthorID,
+		AuthorName:   name,
+		Body:         comment.Body,
+		AnchorStart:  comment.AnchorStart,
+		AnchorLength: comment.AnchorLength,
+		Quote:        comment.Quote,
+		Resolved:     comment.IsResolved(),
+		ResolvedAt:   comment.ResolvedAt,
+		ResolvedBy:   comment.ResolvedBy,
+		CreatedAt:    comment.CreatedAt,
+		UpdatedAt:    comment.UpdatedAt,
+	}
+}
+
+// notify, yorum olayını belgeyi açık tutan editörlere iletir ve yanıtı döndürür.
+func (h *CommentHandler) notify(action string, comment *models.Comment) CommentResponse {
+	response := h.commentToResponse(comment, map[uuid.UUID]string{})
+	h.hubs.NotifyComment(comment.DocumentID, CommentEvent{Action: action, Comment: response})
+	return response
+}
+
+// loadDocument, path'teki belgeyi yükler ve kullanıcının action işlemini yapabildiğini doğrular.
+// Sorun varsa yanıtı yazar ve false döner.
+func (h *CommentHandler) loadDocument(c *gin.Context, action authz.Action) (*models.Document, uuid.UUID, bool) {
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz belge ID'si"})
+		return nil, uuid.Nil, false
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return nil, uuid.Nil, false
+	}
+
+	organizationID, _ := utils.GetOrganizationFromContext(c)
+	var doc models.Document
+	if err := h.repo.Document.InOrganization(organizationID).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return nil, uuid.Nil, false
+	}
+
+	denied := "Bu belgeye yorum yapma izniniz yok"
+	if action == authz.ActionView {
+		denied = "Bu belgenin yorumlarını görüntüleme izniniz yok"
+	}
+	if !authorize(c, h.authorizer, userID, action, &doc, denied) {
+		return nil, uuid.Nil, false
+	}
+	return &doc, userID, true
+}
+
+// loadComment, path'teki yorumu yükler ve belgeye ait olduğunu doğrular.
+// Sorun varsa yanıtı yazar ve nil döner.
+func (h *CommentHandler) loadComment(c *gin.Context, doc *models.Document) *models.Comment {
+	commentID, err := uuid.Parse(c.Param("comment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz yorum ID'si"})
+		return nil
+	}
+
+	var comment models.Comment
+	if err := h.repo.Comment.GetByID(commentID, &comment); err != nil || comment.DocumentID != doc.ID {
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Yorum alınamadı: " + err.Error()})
+			return nil
+		}
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Yorum bulunamadı"})
+		return nil
+	}
+	return &comment
+}
+
+// GetComments godoc
+// @Tags Comments
+// @Summary List comment threads of a document
+// @Description Returns root comments with their replies. Resolved threads are only included when include_resolved=true.
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param include_resolved query bool false "Include resolved threads"
+// @Success 200 {array} CommentThreadResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/comments [get]
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	doc, _, ok := h.loadDocument(c, authz.ActionView)
+	if !ok {
+		return
+	}
+
+	h.hubs.Flush(doc.ID)
+	comments, err := h.repo.Comment.GetByDocumentID(doc.ID, c.Query("include_resolved") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Yorumlar alınamadı: " + err.Error()})
+		return
+	}
+
+	authors := make(map[uuid.UUID]string)
+	threads := make([]CommentThreadResponse, 0)
+	index := make(map[uuid.UUID]int)
+	for i := range comments {
+		if comments[i].IsReply() {
+			continue
+		}
+		index[comments[i].ID] = len(threads)
+		threads = append(threads, CommentThreadResponse{
+			CommentResponse: h.commentToResponse(&comments[i], authors),
+			Replies:         []CommentResponse{},
+		})
+	}
+	for i := range comments {
+		if !comments[i].IsReply() {
+			continue
+		}
+		if position, ok := index[*comments[i].ParentID]; ok {
+			threads[position].Replies = append(threads[position].Replies, h.commentToResponse(&comments[i], authors))
+		}
+	}
+
+	c.JSON(http.StatusOK, threads)
+}
+
+// CreateComment godoc
+// @Tags Comments
+// @Summary Start a comment thread on a text range
+// @Description Anchors a comment to [anchor_start, anchor_start+anchor_length) in UTF-16 code units. If version is given, the anchor is shifted through the edits made since that collaboration version.
+// @Accept json
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param comment body CreateCommentRequest true "Comment"
+// @Success 201 {object} CommentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/comments [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	doc, userID, ok := h.loadDocument(c, authz.ActionComment)
+	if !ok {
+		return
+	}
+
+	var request CreateCommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	comment := &models.Comment{
+		DocumentID:   doc.ID,
+		AuthorID:     userID,
+		Body:         request.Body,
+		AnchorStart:  request.AnchorStart,
+		AnchorLength: request.AnchorLength,
+		Quote:        request.Quote,
+	}
+	baseVersion := math.MaxInt
+	if request.Version != nil {
+		baseVersion = *request.Version
+	}
+	if err := h.hubs.AnchorComment(doc.ID, comment, baseVersion, h.repo.Comment.Create); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Yorum oluşturulamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.notify(CommentEventCreated, comment))
+}
+
+// ReplyToComment godoc
+// @Tags Comments
+// @Summary Reply to a comment thread
+// @Accept json
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param comment_id path string true "Root comment ID"
+// @Param reply body CommentBodyRequest true "Reply"
+// @Success 201 {object} CommentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/comments/{comment_id}/replies [post]
+func (h *CommentHandler) ReplyToComment(c *gin.Context) {
+	doc, userID, ok := h.loadDocument(c, authz.ActionComment)
+	if !ok {
+		return
+	}
+	parent := h.loadComment(c, doc)
+	if parent == nil {
+		return
+	}
+
+	if parent.IsReply() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Yalnızca kök yorumlara yanıt verilebilir"})
+		return
+	}
+	if parent.IsResolved() {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Çözülmüş bir yoruma yanıt verilemez; önce yeniden açın"})
+		return
+	}
+
+	var request CommentBodyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	reply := &models.Comment{
+		DocumentID: doc.ID,
+		ParentID:   &parent.ID,
+		AuthorID:   userID,
+		Body:       request.Body,
+	}
+	if err := h.repo.Comment.Create(reply); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Yanıt oluşturulamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.notify(CommentEventReplied, reply))
+}
+
+// UpdateComment godoc
+// @Tags Comments
+// @Summary Edit a comment
+// @Description Only the author can edit a comment.
+// @Accept json
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param comment_id path string true "Comment ID"
+// @Param comment body CommentBodyRequest true "New body"
+// @Success 200 {object} CommentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/comments/{comment_id} [put]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	doc, userID, ok := h.loadDocument(c, authz.ActionComment)
+	if !ok {
+		return
+	}
+	comment := h.loadComment(c, doc)
+	if comment == nil {
+		return
+	}
+
+	if comment.AuthorID != userID {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Yalnızca kendi yorumlarınızı düzenleyebilirsiniz"})
+		return
+	}
+
+	var request CommentBodyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	if err := h.repo.Comment.UpdateBody(comment.ID, request.Body); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Yorum güncellenemedi: " + err.Error()})
+		return
+	}
+	comment.Body = request.Body
+	comment.UpdatedAt = time.Now()
+
+	c.JSON(http.StatusOK, h.notify(CommentEventUpdated, comment))
+}
+
+// ResolveComment godoc
+// @Tags Comments
+// @Summary Resolve a comment thread
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param comment_id path string true "Root comment ID"
+// @Success 200 {object} CommentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/comments/{comment_id}/resolve [post]
+func (h *CommentHandler) ResolveComment(c *gin.Context) {
+	h.setResolved(c, true)
+}
+
+// ReopenComment godoc
+// @Tags Comments
+// @Summary Reopen a resolved comment thread
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param comment_id path string true "Root comment ID"
+// @Success 200 {object} CommentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/comments/{comment_id}/reopen [post]
+func (h *CommentHandler) ReopenComment(c *gin.Context) {
+	h.setResolved(c, false)
+}
+
+func (h *CommentHandler) setResolved(c *gin.Context, resolved bool) {
+	doc, userID, ok := h.loadDocument(c, authz.ActionComment)
+	if !ok {
+		return
+	}
+	comment := h.loadComment(c, doc)
+	if comment == nil {
+		return
+	}
+
+	if comment.IsReply() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Yalnızca yorum dizileri çözülebilir veya yeniden açılabilir"})
+		return
+	}
+	if comment.IsResolved() == resolved {
+		c.JSON(http.StatusOK, h.commentToResponse(comment, map[uuid.UUID]string{}))
+		return
+	}
+
+	var resolvedBy *uuid.UUID
+	var resolvedAt *time.Time
+	event := CommentEventReopened
+	if resolved {
+		now := time.Now()
+		resolvedBy, resolvedAt = &userID, &now
+		event = CommentEventResolved
+	}
+
+	if err := h.repo.Comment.SetResolved(comment.ID, resolvedBy, resolvedAt); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Yorum durumu güncellenemedi: " + err.Error()})
+		return
+	}
+	comment.ResolvedBy, comment.ResolvedAt = resolvedBy, resolvedAt
+
+	c.JSON(http.StatusOK, h.notify(event, comment))
+}
+
+// DeleteComment godoc
+// @Tags Comments
+// @Summary Delete a comment
+// @Description Authors can delete their own comments; document owners and admins can delete any comment. Deleting a root comment deletes its replies.
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param comment_id path string true "Comment ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/comments/{comment_id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	doc, userID, ok := h.loadDocument(c, authz.ActionComment)
+	if !ok {
+		return
+	}
+	comment := h.loadComment(c, doc)
+	if comment == nil {
+		return
+	}
+
+	if comment.AuthorID != userID &&
+		!authorize(c, h.authorizer, userID, authz.ActionModerate, doc, "Yalnızca kendi yorumlarınızı silebilirsiniz") {
+		return
+	}
+
+	if err := h.repo.Comment.Delete(comment.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Yorum silinemedi: " + err.Error()})
+		return
+	}
+	if !comment.IsReply() {
+		h.hubs.UntrackComment(doc.ID, comment.ID)
+	}
+	h.notify(CommentEventDeleted, comment)
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Yorum silindi."})
+}
@@ -6,8 +6,11 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/dione-docs-backend/internal/authz"
 	"github.com/dione-docs-backend/internal/models"
 	"github.com/dione-docs-backend/internal/repository"
 	"github.com/dione-docs-backend/internal/utils"
@@ -16,26 +19,31 @@ import (
 )
 
 type DocumentHandler struct {
-	repo *repository.Repository
+	repo       *repository.Repository
+	hubs       *HubManager
+	authorizer *authz.Authorizer
 }
 
-func NewDocumentHandler(repo *repository.Repository) *DocumentHandler {
+func NewDocumentHandler(repo *repository.Repository, hubs *HubManager, authorizer *authz.Authorizer) *DocumentHandler {
 	return &DocumentHandler{
-		repo: repo,
+		repo:       repo,
+		hubs:       hubs,
+		authorizer: authorizer,
 	}
 }
 
 type DocumentResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description,omitempty"`
-	OwnerID     uuid.UUID `json:"owner_id"`
-	Version     int       `json:"version"`
-	IsPublic    bool      `json:"is_public"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Content     []byte    `json:"content,omitempty"`
+	ID             uuid.UUID  `json:"id"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description,omitempty"`
+	OwnerID        uuid.UUID  `json:"owner_id"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+	Version        int        `json:"version"`
+	IsPublic       bool       `json:"is_public"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Content        []byte     `json:"content,omitempty"`
 }
 
 type DocumentListResponse struct {
@@ -44,41 +52,61 @@ type DocumentListResponse struct {
 }
 
 type DocumentVersionResponse struct {
-	ID         uuid.UUID `json:"id"`
-	DocumentID uuid.UUID `json:"document_id"`
-	Version    int       `json:"version"`
-	ChangedBy  uuid.UUID `json:"changed_by"`
-	CreatedAt  time.Time `json:"created_at"`
-	Content    []byte    `json:"content,omitempty"`
+	ID                  uuid.UUID  `json:"id"`
+	DocumentID          uuid.UUID  `json:"document_id"`
+	Version             int        `json:"version"`
+	ChangedBy           uuid.UUID  `json:"changed_by"`
+	Action              string     `json:"action"`
+	RestoredFromVersion *int       `json:"restored_from_version,omitempty"`
+	Name                string     `json:"name,omitempty"`
+	Note                string     `json:"note,omitempty"`
+	NamedBy             *uuid.UUID `json:"named_by,omitempty"`
+	NamedAt             *time.Time `json:"named_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	Content             []byte     `json:"content,omitempty"`
+	Current             bool       `json:"current,omitempty"` // belgenin güncel versiyonu
+}
+
+type DocumentVersionListResponse struct {
+	Items    []DocumentVersionResponse `json:"items"`
+	Total    int64                     `json:"total"`
+	Page     int                       `json:"page"`
+	PageSize int                       `json:"page_size"`
+}
+
+type UpdateVersionRequest struct {
+	Name *string `json:"name" binding:"omitempty,max=120"`
+	Note *string `json:"note" binding:"omitempty,max=2000"`
 }
 
 type CreateDocumentRequest struct {
 	Title       string          `json:"title"`
 	Description string          `json:"description"`
 	IsPublic    bool            `json:"is_public"`
-	Content     json.RawMessage `json:"content,omitempty"` // Quill Delta formatında içerik
+	Content     json.RawMessage `json:"content,omitempty" swaggertype:"object"` // Quill Delta formatında içerik
 }
 
 type UpdateDocumentRequest struct {
 	Title       *string         `json:"title"`
 	Description *string         `json:"description"`
-	Content     json.RawMessage `json:"content,omitempty"` // Değişiklik: []byte -> json.RawMessage, omitempty eklendi
+	Content     json.RawMessage `json:"content,omitempty" swaggertype:"object"` // Değişiklik: []byte -> json.RawMessage, omitempty eklendi
 	IsPublic    *bool           `json:"is_public"`
 	Status      *string         `json:"status"`
 }
 
 func documentToResponse(doc *models.Document) DocumentResponse {
 	return DocumentResponse{
-		ID:          doc.ID,
-		Title:       doc.Title,
-		Description: doc.Description,
-		OwnerID:     doc.OwnerID,
-		Version:     doc.Version,
-		IsPublic:    doc.IsPublic,
-		Status:      doc.Status,
-		CreatedAt:   doc.CreatedAt,
-		UpdatedAt:   doc.UpdatedAt,
-		Content:     doc.Content,
+		ID:             doc.ID,
+		Title:          doc.Title,
+		Description:    doc.Description,
+		OwnerID:        doc.OwnerID,
+		OrganizationID: doc.OrganizationID,
+		Version:        doc.Version,
+		IsPublic:       doc.IsPublic,
+		Status:         doc.Status,
+		CreatedAt:      doc.CreatedAt,
+		UpdatedAt:      doc.UpdatedAt,
+		Content:        doc.Content,
 	}
 }
 
@@ -92,12 +120,18 @@ func documentsToResponses(docs []models.Document) []DocumentResponse {
 
 func versionToResponse(version *models.DocumentVersion) DocumentVersionResponse {
 	return DocumentVersionResponse{
-		ID:         version.ID,
-		DocumentID: version.DocumentID,
-		Version:    version.Version,
-		ChangedBy:  version.ChangedBy,
-		CreatedAt:  version.CreatedAt,
-		Content:    version.Content,
+		ID:                  version.ID,
+		DocumentID:          version.DocumentID,
+		Version:             version.Version,
+		ChangedBy:           version.ChangedBy,
+		Action:              string(version.Action),
+		RestoredFromVersion: version.RestoredFromVersion,
+		Name:                version.Name,
+		Note:                version.Note,
+		NamedBy:             version.NamedBy,
+		NamedAt:             version.NamedAt,
+		CreatedAt:           version.CreatedAt,
+		Content:             version.Content,
 	}
 }
 
@@ -109,6 +143,34 @@ func versionsToResponses(versions []models.DocumentVersion) []DocumentVersionRes
 	return responses
 }
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// parsePagination, "page" ve "page_size" sorgu parametrelerini okur;
+// geçersiz değerler için varsayılanları kullanır.
+func parsePagination(c *gin.Context) (page, pageSize int) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err = strconv.Atoi(c.Query("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
+
+// documents, isteğin aktif çalışma alanıyla (X-Organization-ID) sınırlandırılmış belge repository'sini döndürür.
+func (h *DocumentHandler) documents(c *gin.Context) repository.DocumentRepository {
+	organizationID, _ := utils.GetOrganizationFromContext(c)
+	return h.repo.Document.InOrganization(organizationID)
+}
+
 func getBodyBytes(c *gin.Context) []byte {
 	if c.Request.Body != nil {
 		bodyBytes, err := io.ReadAll(c.Request.Body)
@@ -163,14 +225,21 @@ func (h *DocumentHandler) CreateDocument(c *gin.Context) {
 		contentToSave = []byte(`{"ops":[{"insert":"\n"}]}`)
 	}
 
+	organizationID, organizationRole := utils.GetOrganizationFromContext(c)
+	if organizationID != nil && organizationRole == models.OrganizationRoleGuest {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Misafir üyeler organizasyonda belge oluşturamaz"})
+		return
+	}
+
 	doc := &models.Document{
-		Title:       title,
-		Description: request.Description,
-		OwnerID:     userID,
-		Content:     contentToSave,
-		Version:     1,
-		IsPublic:    request.IsPublic,
-		Status:      "draft", // Status backend'de atanıyor, request'ten değil
+		Title:          title,
+		Description:    request.Description,
+		OwnerID:        userID,
+		OrganizationID: organizationID,
+		Content:        contentToSave,
+		Version:        1,
+		IsPublic:       request.IsPublic,
+		Status:         "draft", // Status backend'de atanıyor, request'ten değil
 	}
 
 	if err := h.repo.Document.Create(doc); err != nil {
@@ -209,17 +278,13 @@ func (h *DocumentHandler) GetDocument(c *gin.Context) {
 	}
 
 	var doc models.Document
-	if err := h.repo.Document.GetByID(docID, &doc); err != nil {
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
 		return
 	}
 
-	if doc.OwnerID != userID && !doc.IsPublic {
-		permission, err := h.repo.Permission.GetByDocumentAndUser(docID, userID)
-		if err != nil || permission == nil {
-			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu belgeye erişim izniniz yok"})
-			return
-		}
+	if !authorize(c, h.authorizer, userID, authz.ActionView, &doc, "Bu belgeye erişim izniniz yok; erişim isteği gönderebilirsiniz") {
+		return
 	}
 
 	c.JSON(http.StatusOK, documentToResponse(&doc))
@@ -255,27 +320,12 @@ func (h *DocumentHandler) UpdateDocument(c *gin.Context) {
 	}
 
 	var existingDoc models.Document
-	if err := h.repo.Document.GetByID(docID, &existingDoc); err != nil {
+	if err := h.documents(c).GetByID(docID, &existingDoc); err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
 		return
 	}
 
-	isOwner := existingDoc.OwnerID == userID
-	canEdit := false
-
-	if isOwner {
-		canEdit = true
-	} else {
-		permission, err := h.repo.Permission.GetAcceptedByDocumentAndUser(docID, userID)
-		if err == nil && permission != nil {
-			if permission.AccessType == string(models.AccessTypeEditor) || permission.AccessType == string(models.AccessTypeAdmin) {
-				canEdit = true
-			}
-		}
-	}
-
-	if !canEdit {
-		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu belgeyi düzenleme izniniz yok"})
+	if !authorize(c, h.authorizer, userID, authz.ActionEdit, &existingDoc, "Bu belgeyi düzenleme izniniz yok") {
 		return
 	}
 
@@ -304,18 +354,13 @@ func (h *DocumentHandler) UpdateDocument(c *gin.Context) {
 		}
 	}
 
+	previousContent := existingDoc.Content
 	if contentChanged {
-		version := &models.DocumentVersion{
-			DocumentID: existingDoc.ID,
-			Version:    existingDoc.Version,
-			Content:    existingDoc.Content,
-			ChangedBy:  userID,
-		}
-		if err := h.repo.Document.SaveVersion(version); err != nil {
+		// Canlı oturumun bekleyen yazar kayıtları bu değişiklikten önce sıralanmalıdır.
+		h.hubs.Flush(existingDoc.ID)
+		if err := applyContentChange(h.repo, &existingDoc, updateRequest.Content, versionChange{ChangedBy: userID}); err != nil {
 			log.Printf("Versiyon kaydedilemedi: %v", err)
 		}
-		existingDoc.Version++
-		existingDoc.Content = updateRequest.Content
 	}
 
 	if updateRequest.Title != nil {
@@ -336,6 +381,11 @@ func (h *DocumentHandler) UpdateDocument(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Belge güncellenemedi: " + err.Error()})
 		return
 	}
+	if contentChanged {
+		if err := h.hubs.RemapContent(existingDoc.ID, previousContent, existingDoc.Content); err != nil {
+			log.Printf("UpdateDocument - yorum çapaları ve öneriler güncellenemedi: %v", err)
+		}
+	}
 	log.Printf("UpdateDocument - Belge başarıyla güncellendi: ID %s", existingDoc.ID.String())
 	c.JSON(http.StatusOK, documentToResponse(&existingDoc))
 }
@@ -367,17 +417,13 @@ func (h *DocumentHandler) DeleteDocument(c *gin.Context) {
 	}
 
 	var doc models.Document
-	if err := h.repo.Document.GetByID(docID, &doc); err != nil {
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
 		return
 	}
 
-	if doc.OwnerID != userID {
-		permission, err := h.repo.Permission.GetByDocumentAndUser(docID, userID)
-		if err != nil || permission == nil || permission.AccessType != "admin" {
-			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu belgeyi silme izniniz yok"})
-			return
-		}
+	if !authorize(c, h.authorizer, userID, authz.ActionDelete, &doc, "Bu belgeyi silme izniniz yok") {
+		return
 	}
 
 	if err := h.repo.Document.Delete(&doc); err != nil {
@@ -404,13 +450,15 @@ func (h *DocumentHandler) GetUserDocuments(c *gin.Context) {
 		return
 	}
 
-	ownedDocs, err := h.repo.Document.GetByOwnerID(userID)
+	documents := h.documents(c)
+
+	ownedDocs, err := documents.GetByOwnerID(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Belgeler alınamadı: " + err.Error()})
 		return
 	}
 
-	sharedDocs, err := h.repo.Document.GetSharedWithUser(userID)
+	sharedDocs, err := documents.GetSharedWithUser(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Paylaşılan belgeler alınamadı: " + err.Error()})
 		return
@@ -427,9 +475,13 @@ func (h *DocumentHandler) GetUserDocuments(c *gin.Context) {
 // GetDocumentVersions retrieves the version history of a document
 // @Tags Documents
 // @Summary Get version history of a document
-// @Description Retrieve all versions of a specific document
+// @Description Retrieve a page of versions of a specific document without their content. Use named=true to list only named versions.
+// @Produce  json
 // @Param id path string true "Document ID"
-// @Success 200 {array} DocumentVersionResponse "Document version history"
+// @Param named query bool false "Only named versions"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} DocumentVersionListResponse "Document version history"
 // @Failure 400 {object} ErrorResponse "Invalid document ID"
 // @Failure 401 {object} ErrorResponse "Authentication error"
 // @Failure 403 {object} ErrorResponse "Access denied"
@@ -451,30 +503,279 @@ func (h *DocumentHandler) GetDocumentVersions(c *gin.Context) {
 	}
 
 	var doc models.Document
-	if err := h.repo.Document.GetByID(docID, &doc); err != nil {
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return
+	}
+
+	if !authorize(c, h.authorizer, userID, authz.ActionView, &doc, "Bu belgenin geçmişine erişim izniniz yok") {
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	filter := repository.VersionFilter{
+		NamedOnly: c.Query("named") == "true",
+		Limit:     pageSize,
+		Offset:    (page - 1) * pageSize,
+	}
+
+	versions, total, err := h.repo.Document.GetVersions(docID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Versiyon geçmişi alınamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, DocumentVersionListResponse{
+		Items:    versionsToResponses(versions),
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+// GetDocumentVersion retrieves a single version of a document including its content
+// @Tags Documents
+// @Summary Get a single document version
+// @Description Retrieve one version of a document together with its content. The current version number returns the document's current content with current=true.
+// @Produce  json
+// @Param id path string true "Document ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} DocumentVersionResponse "Document version"
+// @Failure 400 {object} ErrorResponse "Invalid document ID or version"
+// @Failure 401 {object} ErrorResponse "Authentication error"
+// @Failure 403 {object} ErrorResponse "Access denied"
+// @Failure 404 {object} ErrorResponse "Document or version not found"
+// @Router /api/v1/documents/{id}/versions/{version} [get]
+func (h *DocumentHandler) GetDocumentVersion(c *gin.Context) {
+	docIDStr := c.Param("id")
+	docID, err := uuid.Parse(docIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz belge ID'si"})
+		return
+	}
+
+	versionNumber, err := strconv.Atoi(c.Param("version"))
+	if err != nil || versionNumber < 1 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz versiyon numarası"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	var doc models.Document
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return
+	}
+
+	if !authorize(c, h.authorizer, userID, authz.ActionView, &doc, "Bu belgenin geçmişine erişim izniniz yok") {
+		return
+	}
+
+	version, err := h.repo.Document.GetVersion(docID, versionNumber)
+	if versionNumber == doc.Version {
+		// Güncel versiyon yalnızca isimlendirildiyse kayıtlıdır; yoksa belge içeriğinden üretilir.
+		if err != nil {
+			version = headVersion(&doc)
+		}
+		response := versionToResponse(version)
+		response.Current = true
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Versiyon bulunamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, versionToResponse(version))
+}
+
+// UpdateDocumentVersion names or annotates a document version
+// @Tags Documents
+// @Summary Name a document version
+// @Description Set the name and note of a version. Named versions are never pruned; an empty name removes the label. The current version can be named too; its content is stored as a version row first.
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Document ID"
+// @Param version path int true "Version number"
+// @Param label body UpdateVersionRequest true "Version label"
+// @Success 200 {object} DocumentVersionResponse "Version updated successfully"
+// @Failure 400 {object} ErrorResponse "Invalid document ID, version or request format"
+// @Failure 401 {object} ErrorResponse "Authentication error"
+// @Failure 403 {object} ErrorResponse "Access denied"
+// @Failure 404 {object} ErrorResponse "Document or version not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/documents/{id}/versions/{version} [patch]
+func (h *DocumentHandler) UpdateDocumentVersion(c *gin.Context) {
+	docIDStr := c.Param("id")
+	docID, err := uuid.Parse(docIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz belge ID'si"})
+		return
+	}
+
+	versionNumber, err := strconv.Atoi(c.Param("version"))
+	if err != nil || versionNumber < 1 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz versiyon numarası"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	var doc models.Document
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
 		return
 	}
 
-	if doc.OwnerID != userID && !doc.IsPublic {
-		permission, err := h.repo.Permission.GetByDocumentAndUser(docID, userID)
-		if err != nil || permission == nil {
-			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu belgenin geçmişine erişim izniniz yok"})
+	if !authorize(c, h.authorizer, userID, authz.ActionEdit, &doc, "Bu belgenin versiyonlarını düzenleme izniniz yok") {
+		return
+	}
+
+	var request UpdateVersionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	version, err := h.repo.Document.GetVersion(docID, versionNumber)
+	if err != nil && versionNumber == doc.Version {
+		// Güncel versiyonun kaydı henüz yok; isimlendirilebilmesi için içeriği şimdi saklanır.
+		version = headVersion(&doc)
+		err = h.repo.Document.SaveVersion(version)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Versiyon kaydedilemedi: " + err.Error()})
 			return
 		}
 	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Versiyon bulunamadı"})
+		return
+	}
+
+	name := version.Name
+	if request.Name != nil {
+		name = strings.TrimSpace(*request.Name)
+	}
+	note := version.Note
+	if request.Note != nil {
+		note = *request.Note
+	}
 
-	versions, err := h.repo.Document.GetVersions(docID)
+	if err := h.repo.Document.UpdateVersionLabel(version.ID, name, note, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Versiyon güncellenemedi: " + err.Error()})
+		return
+	}
+
+	updated, err := h.repo.Document.GetVersion(docID, versionNumber)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Versiyon geçmişi alınamadı: " + err.Error()})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Versiyon alınamadı: " + err.Error()})
+		return
+	}
+
+	response := versionToResponse(updated)
+	response.Current = versionNumber == doc.Version
+	c.JSON(http.StatusOK, response)
+}
+
+// RestoreDocumentVersion restores a document to a previous version
+// @Tags Documents
+// @Summary Restore a document to a previous version
+// @Description Snapshot the current content and create a new head version whose content equals the selected version. Connected editors are told to reload. Restoring the current version leaves the document unchanged.
+// @Produce  json
+// @Param id path string true "Document ID"
+// @Param version path int true "Version number to restore"
+// @Success 200 {object} DocumentResponse "Document restored successfully"
+// @Failure 400 {object} ErrorResponse "Invalid document ID or version"
+// @Failure 401 {object} ErrorResponse "Authentication error"
+// @Failure 403 {object} ErrorResponse "Access denied"
+// @Failure 404 {object} ErrorResponse "Document or version not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /api/v1/documents/{id}/versions/{version}/restore [post]
+func (h *DocumentHandler) RestoreDocumentVersion(c *gin.Context) {
+	docIDStr := c.Param("id")
+	docID, err := uuid.Parse(docIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz belge ID'si"})
+		return
+	}
+
+	versionNumber, err := strconv.Atoi(c.Param("version"))
+	if err != nil || versionNumber < 1 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz versiyon numarası"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	var doc models.Document
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return
+	}
+
+	if !authorize(c, h.authorizer, userID, authz.ActionEdit, &doc, "Bu belgeyi geri yükleme izniniz yok") {
+		return
+	}
+
+	if versionNumber == doc.Version {
+		c.JSON(http.StatusOK, documentToResponse(&doc))
+		return
+	}
+
+	target, err := h.repo.Document.GetVersion(docID, versionNumber)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Versiyon bulunamadı"})
+		return
+	}
+
+	if bytes.Equal(target.Content, doc.Content) {
+		c.JSON(http.StatusOK, documentToResponse(&doc))
 		return
 	}
+	previousContent := doc.Content
 
-	c.JSON(http.StatusOK, versionsToResponses(versions))
+	change := versionChange{
+		ChangedBy:    userID,
+		Action:       models.VersionActionRestore,
+		RestoredFrom: &target.Version,
+	}
+	h.hubs.Flush(doc.ID)
+	if err := applyContentChange(h.repo, &doc, target.Content, change); err != nil {
+		log.Printf("RestoreDocumentVersion - mevcut versiyon kaydedilemedi: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Mevcut versiyon kaydedilemedi: " + err.Error()})
+		return
+	}
+
+	if err := h.repo.Document.Update(&doc); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Belge geri yüklenemedi: " + err.Error()})
+		return
+	}
+
+	if err := h.hubs.RemapContent(doc.ID, previousContent, doc.Content); err != nil {
+		log.Printf("RestoreDocumentVersion - yorum çapaları ve öneriler güncellenemedi: %v", err)
+	}
+	h.hubs.NotifyReload(doc.ID, doc.Content, doc.Version)
+
+	log.Printf("RestoreDocumentVersion - Belge %s, %d. versiyona %s tarafından geri yüklendi", doc.ID, target.Version, userID)
+	c.JSON(http.StatusOK, documentToResponse(&doc))
 }
 
 type UpdateContentRequest struct {
-	Content json.RawMessage `json:"content"`
+	Content json.RawMessage `json:"content" swaggertype:"object"`
 }
 
 func (h *DocumentHandler) UpdateDocumentContent(c *gin.Context) {
@@ -503,23 +804,17 @@ func (h *DocumentHandler) UpdateDocumentContent(c *gin.Context) {
 	}
 
 	if !bytes.Equal(req.Content, existingDoc.Content) {
-		version := &models.DocumentVersion{
-			DocumentID: existingDoc.ID,
-			Version:    existingDoc.Version,
-			Content:    existingDoc.Content,
-			ChangedBy:  uuid.Nil, // Sistemi temsil eden Nil UUID kullanılabilir
-		}
-		if err := h.repo.Document.SaveVersion(version); err != nil {
+		// Sistemi temsil eden Nil UUID kullanılıyor
+		if err := applyContentChange(h.repo, &existingDoc, req.Content, versionChange{ChangedBy: uuid.Nil, FromHub: true}); err != nil {
 			log.Printf("Versiyon kaydedilemedi: %v", err)
 		}
-		existingDoc.Version++
-		existingDoc.Content = req.Content
 	}
 
 	if err := h.repo.Document.Update(&existingDoc); err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Belge içeriği güncellenemedi: " + err.Error()})
 		return
 	}
+	h.hubs.Saved(existingDoc.ID, existingDoc.Version)
 
 	c.JSON(http.StatusOK, gin.H{"message": "İçerik başarıyla güncellendi"})
 }
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"sync"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Bu dosyadaki sahte repository'ler, handler testleri için verileri bellekte tutar. Gömülü
+// arayüzler yalnızca derleme içindir; testin kullanmadığı bir metot çağrılırsa test panic ile biter.
+
+// fakeDocuments, belgeleri bellekte tutar. InOrganization, gerçek depo gibi organizasyon
+// verilmişse başka çalışma alanlarındaki belgeleri bulamaz.
+type fakeDocuments struct {
+	repository.DocumentRepository
+	store          *fakeDocumentStore
+	scoped         bool
+	organizationID *uuid.UUID
+}
+
+type fakeDocumentStore struct {
+	mu       sync.Mutex
+	docs     map[uuid.UUID]models.Document
+	versions []models.DocumentVersion
+}
+
+func newFakeDocuments(docs ...*models.Document) *fakeDocuments {
+	store := &fakeDocumentStore{docs: make(map[uuid.UUID]models.Document)}
+	for _, doc := range docs {
+		store.docs[doc.ID] = *doc
+	}
+	return &fakeDocuments{store: store}
+}
+
+func (f *fakeDocuments) InOrganization(organizationID *uuid.UUID) repository.DocumentRepository {
+	return &fakeDocuments{store: f.store, scoped: true, organizationID: organizationID}
+}
+
+func (f *fakeDocuments) GetByID(id any, doc *models.Document) error {
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+
+	var docID uuid.UUID
+	switch v := id.(type) {
+	case uuid.UUID:
+		docID = v
+	case string:
+		docID, _ = uuid.Parse(v)
+	}
+	stored, ok := f.store.docs[docID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if f.scoped && f.organizationID != nil &&
+		(stored.OrganizationID == nil || *stored.OrganizationID != *f.organizationID) {
+		return gorm.ErrRecordNotFound
+	}
+	*doc = stored
+	return nil
+}
+
+func (f *fakeDocuments) Update(doc *models.Document) error {
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+
+	f.store.docs[doc.ID] = *doc
+	return nil
+}
+
+func (f *fakeDocuments) SaveVersion(version *models.DocumentVersion) error {
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+
+	f.store.versions = append(f.store.versions, *version)
+	return nil
+}
+
+// fakePermissions, kullanıcıların belgelerdeki etkin erişim türünü bir haritadan döndürür.
+type fakePermissions struct {
+	repository.PermissionRepository
+	access map[uuid.UUID]string
+}
+
+func (f *fakePermissions) GetEffectiveAccessType(documentID, userID uuid.UUID) (string, error) {
+	return f.access[userID], nil
+}
+
+type fakeUsers struct {
+	repository.UserRepository
+	users map[uuid.UUID]models.User
+}
+
+func (f *fakeUsers) GetByID(id any, user *models.User) error {
+	userID, _ := id.(uuid.UUID)
+	stored, ok := f.users[userID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	*user = stored
+	return nil
+}
+
+type fakeComments struct {
+	repository.CommentRepository
+}
+
+func (f *fakeComments) GetThreads(documentID uuid.UUID) ([]models.Comment, error) {
+	return nil, nil
+}
+
+type fakeSuggestions struct {
+	repository.SuggestionRepository
+}
+
+func (f *fakeSuggestions) GetByDocumentID(documentID uuid.UUID, status models.SuggestionStatus) ([]models.Suggestion, error) {
+	return nil, nil
+}
+
+// fakeShareLinks, paylaşım bağlantılarını token'larına göre döndürür.
+type fakeShareLinks struct {
+	repository.ShareLinkRepository
+	links map[string]models.ShareLink
+}
+
+func (f *fakeShareLinks) GetByToken(token string) (*models.ShareLink, error) {
+	link, ok := f.links[token]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &link, nil
+}
+
+// fakeAttributions, operasyon kaydını ve yazar indeksini bellekte tutar.
+type fakeAttributions struct {
+	mu           sync.Mutex
+	operations   []models.DocumentOperation
+	attributions map[uuid.UUID]models.DocumentAttribution
+}
+
+func newFakeAttributions() *fakeAttributions {
+	return &fakeAttributions{attributions: make(map[uuid.UUID]models.DocumentAttribution)}
+}
+
+func (f *fakeAttributions) GetByDocumentID(documentID uuid.UUID) (*models.DocumentAttribution, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	attribution, ok := f.attributions[documentID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &attribution, nil
+}
+
+func (f *fakeAttributions) Save(attribution *models.DocumentAttribution) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.attributions[attribution.DocumentID] = *attribution
+	return nil
+}
+
+func (f *fakeAttributions) RecordOperations(baseline *models.DocumentOperation, operations []models.DocumentOperation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(operations) == 0 {
+		return nil
+	}
+	if baseline != nil && f.count(baseline.DocumentID) == 0 {
+		f.append(*baseline)
+	}
+	for _, operation := range operations {
+		f.append(operation)
+	}
+	return nil
+}
+
+func (f *fakeAttributions) ForEachOperation(documentID uuid.UUID, afterID int64, fn func(operation *models.DocumentOperation) error) error {
+	f.mu.Lock()
+	operations := append([]models.DocumentOperation(nil), f.operations...)
+	f.mu.Unlock()
+
+	for i := range operations {
+		if operations[i].DocumentID != documentID || operations[i].ID <= afterID {
+			continue
+		}
+		if err := fn(&operations[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// operationCount, belgenin kayıtlı operasyon sayısını döndürür.
+func (f *fakeAttributions) operationCount(documentID uuid.UUID) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.count(documentID)
+}
+
+func (f *fakeAttributions) count(documentID uuid.UUID) int {
+	n := 0
+	for _, operation := range f.operations {
+		if operation.DocumentID == documentID {
+			n++
+		}
+	}
+	return n
+}
+
+func (f *fakeAttributions) append(operation models.DocumentOperation) {
+	operation.ID = int64(len(f.operations) + 1)
+	f.operations = append(f.operations, operation)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/dione-docs-backend/internal/signing"
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+	keys *signing.KeySet
+}
+
+func NewJWKSHandler(keys *signing.KeySet) *JWKSHandler {
+	return &JWKSHandler{
+		keys: keys,
+	}
+}
+
+// GetJWKS godoc
+// @Summary Token signing keys
+// @Description Returns the public keys that access tokens are signed with as a JSON Web Key Set. Tokens carry the key ID in their "kid" header; keys that are being rotated out stay listed until tokens signed with them expire.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} signing.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	// Doğrulayan servisler anahtarları önbelleğe alabilir; yeni bir anahtar imzalamada kullanılmadan
+	// önce yayımlandığından kısa bir süre yeterlidir.
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.keys.JWKS())
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/services"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type MFAHandler struct {
+	repo *repository.Repository
+	mfa  *services.MFAService
+}
+
+func NewMFAHandler(repo *repository.Repository, mfa *services.MFAService) *MFAHandler {
+	return &MFAHandler{
+		repo: repo,
+		mfa:  mfa,
+	}
+}
+
+// MFACodeRequest, doğrulama uygulamasındaki TOTP kodunu veya bir kurtarma kodunu taşır.
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MFAStatusResponse struct {
+	Enabled                bool  `json:"enabled"`
+	RecoveryCodesRemaining int64 `json:"recovery_codes_remaining"`
+}
+
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// RecoveryCodesResponse, kurtarma kodlarını döndürür. Kodlar yalnızca bu yanıtta gösterilir.
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// GetMFAStatus godoc
+// @Summary Get two-factor status
+// @Description Returns whether two-factor authentication is enabled for the current user and how many recovery codes are left.
+// @Tags MFA
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} MFAStatusResponse
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /api/v1/auth/mfa [get]
+func (h *MFAHandler) GetMFAStatus(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+
+	enabled, err := h.mfa.IsEnabled(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve two-factor status"})
+		return
+	}
+	response := MFAStatusResponse{Enabled: enabled}
+	if enabled {
+		if response.RecoveryCodesRemaining, err = h.mfa.RemainingRecoveryCodes(userID); err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to retrieve two-factor status"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// EnrollMFA godoc
+// @Summary Start two-factor enrollment
+// @Description Generates a new TOTP secret and otpauth URI for the authenticator app. Two-factor authentication is enabled only after the first code is confirmed.
+// @Tags MFA
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} MFAEnrollmentResponse
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 409 {object} ErrorResponse "Two-factor authentication is already enabled"
+// @Router /api/v1/auth/mfa/enroll [post]
+func (h *MFAHandler) EnrollMFA(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return
+	}
+	var user models.User
+	if err := h.repo.User.GetByID(userID, &user); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	enrollment, err := h.mfa.Enroll(&user)
+	if errors.Is(err, services.ErrMFAAlreadyEnabled) {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Two-factor authentication is already enabled"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to start two-factor enrollment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, MFAEnrollmentResponse{Secret: enrollment.Secret, OTPAuthURI: enrollment.URI})
+}
+
+// ConfirmMFA godoc
+// @Summary Confirm two-factor enrollment
+// @Description Enables two-factor authentication with a code from the authenticator app and returns one-time recovery codes.
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "TOTP code"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} ErrorResponse "Invalid request data, invalid code or enrollment not started"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 409 {object} ErrorResponse "Two-factor authentication is already enabled"
+// @Router /api/v1/auth/mfa/confirm [post]
+func (h *MFAHandler) ConfirmMFA(c *gin.Context) {
+	userID, req, ok := h.bindCode(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfa.Confirm(userID, req.Code)
+	switch {
+	case errors.Is(err, services.ErrMFANotEnrolled):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Two-factor enrollment has not been started"})
+		return
+	case errors.Is(err, services.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Two-factor authentication is already enabled"})
+		return
+	case errors.Is(err, services.ErrInvalidMFACode):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid two-factor code"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to enable two-factor authentication"})
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableMFA godoc
+// @Summary Disable two-factor authentication
+// @Description Disables two-factor authentication after verifying a TOTP or recovery code. Remaining recovery codes are deleted.
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "TOTP or recovery code"
+// @Success 204 "Two-factor authentication disabled"
+// @Failure 400 {object} ErrorResponse "Invalid request data, invalid code or not enabled"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /api/v1/auth/mfa/disable [post]
+func (h *MFAHandler) DisableMFA(c *gin.Context) {
+	userID, req, ok := h.bindCode(c)
+	if !ok {
+		return
+	}
+
+	if err := h.mfa.Disable(userID, req.Code); err != nil {
+		h.respondVerifyError(c, err, "Failed to disable two-factor authentication")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replaces all recovery codes after verifying a TOTP or recovery code. Earlier codes stop working.
+// @Tags MFA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MFACodeRequest true "TOTP or recovery code"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} ErrorResponse "Invalid request data, invalid code or not enabled"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /api/v1/auth/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, req, ok := h.bindCode(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.mfa.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		h.respondVerifyError(c, err, "Failed to regenerate recovery codes")
+		return
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *MFAHandler) bindCode(c *gin.Context) (userID uuid.UUID, req MFACodeRequest, ok bool) {
+	id, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return userID, req, false
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request data"})
+		return userID, req, false
+	}
+	return id, req, true
+}
+
+func (h *MFAHandler) respondVerifyError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrMFANotEnabled):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Two-factor authentication is not enabled"})
+	case errors.Is(err, services.ErrInvalidMFACode):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid two-factor code"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: fallback})
+	}
+}
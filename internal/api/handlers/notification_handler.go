@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	repo *repository.Repository
+}
+
+func NewNotificationHandler(repo *repository.Repository) *NotificationHandler {
+	return &NotificationHandler{
+		repo: repo,
+	}
+}
+
+type NotificationResponse struct {
+	ID         uuid.UUID               `json:"id"`
+	Type       models.NotificationType `json:"type"`
+	DocumentID *uuid.UUID              `json:"document_id,omitempty"`
+	ActorID    *uuid.UUID              `json:"actor_id,omitempty"`
+	Message    string                  `json:"message"`
+	Read       bool                    `json:"read"`
+	ReadAt     *time.Time              `json:"read_at,omitempty"`
+	CreatedAt  time.Time               `json:"created_at"`
+}
+
+type NotificationListResponse struct {
+	Items    []NotificationResponse `json:"items"`
+	Total    int64                  `json:"total"`
+	Page     int                    `json:"page"`
+	PageSize int                    `json:"page_size"`
+}
+
+func notificationToResponse(notification *models.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:         notification.ID,
+		Type:       notification.Type,
+		DocumentID: notification.DocumentID,
+		ActorID:    notification.ActorID,
+		Message:    notification.Message,
+		Read:       notification.ReadAt != nil,
+		ReadAt:     notification.ReadAt,
+		CreatedAt:  notification.CreatedAt,
+	}
+}
+
+// GetNotifications godoc
+// @Tags Notifications
+// @Summary List notifications of the current user
+// @Description Returns notifications newest first. Use unread=true to list only unread ones.
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} NotificationListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	page, pageSize := parsePagination(c)
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, total, err := h.repo.Notification.GetByUserID(userID, unreadOnly, pageSize, (page-1)*pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Bildirimler alınamadı: " + err.Error()})
+		return
+	}
+
+	items := make([]NotificationResponse, len(notifications))
+	for i := range notifications {
+		items[i] = notificationToResponse(&notifications[i])
+	}
+	c.JSON(http.StatusOK, NotificationListResponse{
+		Items:    items,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+// MarkNotificationRead godoc
+// @Tags Notifications
+// @Summary Mark a notification as read
+// @Produce json
+// @Param notification_id path string true "Notification ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/notifications/{notification_id}/read [post]
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	notificationID, err := uuid.Parse(c.Param("notification_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz bildirim ID'si"})
+		return
+	}
+
+	updated, err := h.repo.Notification.MarkRead(userID, notificationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Bildirim güncellenemedi: " + err.Error()})
+		return
+	}
+	if updated == 0 {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Okunmamış bildirim bulunamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Bildirim okundu olarak işaretlendi."})
+}
+
+// MarkAllNotificationsRead godoc
+// @Tags Notifications
+// @Summary Mark all notifications as read
+// @Produce json
+// @Success 200 {object} MessageResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	if err := h.repo.Notification.MarkAllRead(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Bildirimler güncellenemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Tüm bildirimler okundu olarak işaretlendi."})
+}
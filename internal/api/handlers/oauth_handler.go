@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dione-docs-backend/internal/config"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/oauth"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	oauthCookieName   = "oauth_google"
+	oauthCookiePath   = "/api/v1/auth/google"
+	oauthCookieMaxAge = 600 // saniye; kullanıcının sağlayıcıda giriş yapması için tanınan süre
+)
+
+var (
+	errOAuthEmailNotVerified = errors.New("email_not_verified")
+	usernameInvalidChars     = regexp.MustCompile(`[^a-z0-9._-]+`)
+)
+
+type OAuthHandler struct {
+	repo     *repository.Repository
+	cfg      *config.Config
+	sessions *services.SessionService
+	mfa      *services.MFAService
+	google   *oauth.Provider
+}
+
+func NewOAuthHandler(repo *repository.Repository, cfg *config.Config, sessions *services.SessionService, mfa *services.MFAService) *OAuthHandler {
+	return &OAuthHandler{
+		repo:     repo,
+		cfg:      cfg,
+		sessions: sessions,
+		mfa:      mfa,
+		google:   oauth.NewGoogleProvider(cfg),
+	}
+}
+
+// GoogleLogin godoc
+// @Summary Start Google sign-in
+// @Description Redirects the browser to Google with a fresh state and PKCE challenge. The state and code verifier are kept in a short-lived HttpOnly cookie.
+// @Tags Auth
+// @Success 302 "Redirect to Google"
+// @Failure 404 {object} ErrorResponse "Google sign-in is not configured"
+// @Router /api/v1/auth/google/login [get]
+func (h *OAuthHandler) GoogleLogin(c *gin.Context) {
+	if !h.google.Enabled() {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Google sign-in is not configured"})
+		return
+	}
+
+	state, err := oauth.NewState()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to start Google sign-in"})
+		return
+	}
+	verifier, err := oauth.NewVerifier()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to start Google sign-in"})
+		return
+	}
+
+	h.setOAuthCookie(c, state+"."+verifier, oauthCookieMaxAge)
+	c.Redirect(http.StatusFound, h.google.AuthCodeURL(state, verifier))
+}
+
+// GoogleCallback godoc
+// @Summary Google sign-in callback
+// @Description Completes Google sign-in: verifies the state, exchanges the code using PKCE, then signs in the user linked to the Google account. A Google account with a verified email is linked to the existing user with that email, otherwise a new user is created. The browser is redirected to APP_URL/auth/callback with the tokens, an MFA challenge, or an error in the URL fragment.
+// @Tags Auth
+// @Param code query string false "Authorization code"
+// @Param state query string true "State"
+// @Success 302 "Redirect to the frontend"
+// @Router /api/v1/auth/google/callback [get]
+func (h *OAuthHandler) GoogleCallback(c *gin.Context) {
+	cookie, _ := c.Cookie(oauthCookieName)
+	h.setOAuthCookie(c, "", -1)
+
+	if !h.google.Enabled() {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Google sign-in is not configured"})
+		return
+	}
+	if providerErr := c.Query("error"); providerErr != "" {
+		h.redirectWithError(c, providerErr)
+		return
+	}
+
+	state, verifier, found := strings.Cut(cookie, ".")
+	queryState := c.Query("state")
+	if !found || queryState == "" || subtle.ConstantTimeCompare([]byte(state), []byte(queryState)) != 1 {
+		h.redirectWithError(c, "invalid_state")
+		return
+	}
+	code := c.Query("code")
+	if code == "" {
+		h.redirectWithError(c, "missing_code")
+		return
+	}
+
+	ctx := c.Request.Context()
+	accessToken, err := h.google.Exchange(ctx, code, verifier)
+	if err != nil {
+		log.Printf("Google sign-in: %v", err)
+		h.redirectWithError(c, "exchange_failed")
+		return
+	}
+	info, err := h.google.UserInfo(ctx, accessToken)
+	if err != nil {
+		log.Printf("Google sign-in: %v", err)
+		h.redirectWithError(c, "exchange_failed")
+		return
+	}
+
+	user, err := h.resolveUser(info)
+	if errors.Is(err, errOAuthEmailNotVerified) {
+		h.redirectWithError(c, errOAuthEmailNotVerified.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Google sign-in for %s failed: %v", info.Email, err)
+		h.redirectWithError(c, "server_error")
+		return
+	}
+	if user.IsDisabled() {
+		h.redirectWithError(c, "account_disabled")
+		return
+	}
+
+	// İki adımlı doğrulaması açık kullanıcılar girişi /auth/login/mfa ile tamamlar.
+	mfaEnabled, err := h.mfa.IsEnabled(user.ID)
+	if err != nil {
+		h.redirectWithError(c, "server_error")
+		return
+	}
+	if mfaEnabled {
+		token, expiresIn, err := h.mfa.IssueChallenge(user.ID)
+		if err != nil {
+			h.redirectWithError(c, "server_error")
+			return
+		}
+		fragment := url.Values{
+			"mfa_required": {"true"},
+			"mfa_token":    {token},
+			"expires_in":   {strconv.Itoa(expiresIn)},
+		}
+		c.Redirect(http.StatusFound, h.cfg.AppURL+"/auth/callback#"+fragment.Encode())
+		return
+	}
+
+	pair, err := h.sessions.Start(user.ID, sessionMetadata(c, ""))
+	if err != nil {
+		h.redirectWithError(c, "server_error")
+		return
+	}
+
+	fragment := url.Values{
+		"token":         {pair.AccessToken},
+		"refresh_token": {pair.RefreshToken},
+		"expires_in":    {strconv.Itoa(pair.ExpiresIn)},
+		"session_id":    {pair.SessionID.String()},
+	}
+	c.Redirect(http.StatusFound, h.cfg.AppURL+"/auth/callback#"+fragment.Encode())
+}
+
+// resolveUser, Google hesabına bağlı kullanıcıyı bulur. Hesap henüz bağlı değilse doğrulanmış
+// e-posta adresine sahip mevcut kullanıcıya bağlanır; böyle bir kullanıcı yoksa yeni kullanıcı oluşturulur.
+func (h *OAuthHandler) resolveUser(info *oauth.UserInfo) (*models.User, error) {
+	identity, err := h.repo.Identity.GetByProviderSubject(h.google.Name(), info.Subject)
+	if err == nil {
+		var user models.User
+		if err := h.repo.User.GetByID(identity.UserID, &user); err != nil {
+			return nil, err
+		}
+		return &user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Doğrulanmamış bir e-posta başkasının hesabını ele geçirmek için kullanılabileceğinden bağlanmaz.
+	if info.Email == "" || !info.EmailVerified {
+		return nil, errOAuthEmailNotVerified
+	}
+
+	newIdentity := &models.UserIdentity{
+		Provider: h.google.Name(),
+		Subject:  info.Subject,
+		Email:    info.Email,
+	}
+
+	user, err := h.repo.User.GetByEmail(info.Email)
+	if err == nil {
+		newIdentity.UserID = user.ID
+		if err := h.repo.Identity.Create(newIdentity); err != nil {
+			return nil, err
+		}
+		// Google adresin sahibini doğruladığı için hesabın e-postası da doğrulanmış sayılır.
+		if !user.EmailVerified {
+			if err := h.repo.User.MarkEmailVerified(user.ID); err != nil {
+				return nil, err
+			}
+			user.EmailVerified = true
+		}
+		log.Printf("Linked Google account %s to user %s", info.Subject, user.ID)
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	username, err := h.availableUsername(info.Email)
+	if err != nil {
+		return nil, err
+	}
+	// Şifresi olmayan kullanıcılar yalnızca bağlı kimlikleriyle giriş yapabilir.
+	user = &models.User{
+		ID:            uuid.New(),
+		Username:      username,
+		FullName:      info.Name,
+		Email:         info.Email,
+		EmailVerified: true,
+		ProfileImage:  info.Picture,
+	}
+	if err := h.repo.Identity.CreateWithUser(user, newIdentity); err != nil {
+		return nil, err
+	}
+
+	if converted, err := h.repo.EmailInvite.ConvertForUser(user); err != nil {
+		log.Printf("Could not convert email invitations for %s: %v", user.Email, err)
+	} else if converted > 0 {
+		log.Printf("Converted %d email invitations for %s", converted, user.Email)
+	}
+	return user, nil
+}
+
+// availableUsername, e-posta adresinin yerel kısmından kullanılmayan bir kullanıcı adı türetir.
+func (h *OAuthHandler) availableUsername(email string) (string, error) {
+	local, _, _ := strings.Cut(strings.ToLower(email), "@")
+	base := strings.Trim(usernameInvalidChars.ReplaceAllString(local, ""), "._-")
+	if base == "" {
+		base = "user"
+	}
+
+	candidate := base
+	for attempt := 0; attempt < 5; attempt++ {
+		_, err := h.repo.User.GetByUsername(candidate)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		id := uuid.New()
+		candidate = base + "-" + hex.EncodeToString(id[:3])
+	}
+	return base + "-" + strings.ReplaceAll(uuid.NewString(), "-", ""), nil
+}
+
+func (h *OAuthHandler) setOAuthCookie(c *gin.Context, value string, maxAge int) {
+	secure := c.Request.TLS != nil || strings.HasPrefix(h.cfg.GoogleRedirectURL, "https://")
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthCookieName, value, maxAge, oauthCookiePath, "", secure, true)
+}
+
+func (h *OAuthHandler) redirectWithError(c *gin.Context, code string) {
+	c.Redirect(http.StatusFound, h.cfg.AppURL+"/auth/callback#"+url.Values{"error": {code}}.Encode())
+}
@@ -0,0 +1,709 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OrganizationHandler struct {
+	repo *repository.Repository
+}
+
+func NewOrganizationHandler(repo *repository.Repository) *OrganizationHandler {
+	return &OrganizationHandler{
+		repo: repo,
+	}
+}
+
+type OrganizationRequest struct {
+	Name            string   `json:"name" binding:"required,max=100"`
+	DefaultAccess   string   `json:"default_access"` // "", "viewer", "commenter" veya "editor"
+	RestrictSharing bool     `json:"restrict_sharing"`
+	AllowedDomains  []string `json:"allowed_domains"`
+}
+
+type AddOrganizationMemberRequest struct {
+	UserEmail string `json:"user_email" binding:"required,email"`
+	Role      string `json:"role"` // "owner", "admin", "member" (varsayılan) veya "guest"
+}
+
+type UpdateOrganizationMemberRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type OrganizationResponse struct {
+	ID              uuid.UUID               `json:"id"`
+	Name            string                  `json:"name"`
+	DefaultAccess   string                  `json:"default_access"`
+	RestrictSharing bool                    `json:"restrict_sharing"`
+	AllowedDomains  []string                `json:"allowed_domains"`
+	Role            models.OrganizationRole `json:"role,omitempty"` // isteği yapan kullanıcının rolü
+	CreatedAt       time.Time               `json:"created_at"`
+	UpdatedAt       time.Time               `json:"updated_at"`
+}
+
+type TransferMemberDocumentsResponse struct {
+	Transferred int `json:"transferred"`
+}
+
+type OrganizationMemberResponse struct {
+	UserID    uuid.UUID               `json:"user_id"`
+	Username  string                  `json:"username"`
+	Email     string                  `json:"email"`
+	Role      models.OrganizationRole `json:"role"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+func organizationToResponse(organization *models.Organization, role models.OrganizationRole) OrganizationResponse {
+	domains := organization.Domains()
+	if domains == nil {
+		domains = []string{}
+	}
+	return OrganizationResponse{
+		ID:              organization.ID,
+		Name:            organization.Name,
+		DefaultAccess:   organization.DefaultAccess,
+		RestrictSharing: organization.RestrictSharing,
+		AllowedDomains:  domains,
+		Role:            role,
+		CreatedAt:       organization.CreatedAt,
+		UpdatedAt:       organization.UpdatedAt,
+	}
+}
+
+func validOrganizationRole(role string) bool {
+	switch models.OrganizationRole(role) {
+	case models.OrganizationRoleOwner, models.OrganizationRoleAdmin, models.OrganizationRoleMember, models.OrganizationRoleGuest:
+		return true
+	}
+	return false
+}
+
+func validDefaultAccess(accessType string) bool {
+	switch models.AccessType(accessType) {
+	case "", models.AccessTypeViewer, models.AccessTypeCommenter, models.AccessTypeEditor:
+		return true
+	}
+	return false
+}
+
+// applyOrganizationRequest, istekteki ayarları organizasyona uygular. Geçersizse false döner.
+func applyOrganizationRequest(organization *models.Organization, request *OrganizationRequest) bool {
+	if !validDefaultAccess(request.DefaultAccess) {
+		return false
+	}
+	domains := make([]string, 0, len(request.AllowedDomains))
+	for _, domain := range request.AllowedDomains {
+		domain = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(domain, "@")))
+		if domain == "" || strings.ContainsAny(domain, ", @") {
+			return false
+		}
+		domains = append(domains, domain)
+	}
+
+	organization.Name = request.Name
+	organization.DefaultAccess = request.DefaultAccess
+	organization.RestrictSharing = request.RestrictSharing
+	organization.AllowedDomains = strings.Join(domains, ",")
+	return true
+}
+
+// sharingAllowed, belgenin organizasyonu paylaşımı kısıtlıyorsa kullanıcının organizasyon
+// üyesi veya izin verilen bir alan adından olduğunu doğrular. user nil ise (anonim erişim)
+// kısıtlı organizasyonlarda false döner.
+func sharingAllowed(repo *repository.Repository, doc *models.Document, user *models.User) (bool, error) {
+	if doc.OrganizationID == nil {
+		return true, nil
+	}
+
+	var organization models.Organization
+	if err := repo.Organization.GetByID(*doc.OrganizationID, &organization); err != nil {
+		return false, err
+	}
+	if !organization.RestrictSharing {
+		return true, nil
+	}
+	if user == nil {
+		return false, nil
+	}
+
+	if _, err := repo.Organization.GetMember(organization.ID, user.ID); err == nil {
+		return true, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	return organization.AllowsEmail(user.Email), nil
+}
+
+// loadOrganization, path'teki organizasyonu ve isteği yapan kullanıcının üyeliğini yükler.
+// requireManager true ise kullanıcının owner veya admin olması gerekir.
+// Sorun varsa yanıtı yazar ve false döner.
+func (h *OrganizationHandler) loadOrganization(c *gin.Context, requireManager bool) (*models.Organization, *models.OrganizationMember, bool) {
+	organizationID, err := uuid.Parse(c.Param("org_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz organizasyon ID'si"})
+		return nil, nil, false
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return nil, nil, false
+	}
+
+	var organization models.Organization
+	if err := h.repo.Organization.GetByID(organizationID, &organization); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Organizasyon bulunamadı"})
+		return nil, nil, false
+	}
+
+	member, err := h.repo.Organization.GetMember(organizationID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Organizasyon bulunamadı"})
+		return nil, nil, false
+	}
+
+	if requireManager && !member.Role.IsManager() {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu işlem için organizasyon yöneticisi olmalısınız"})
+		return nil, nil, false
+	}
+	return &organization, member, true
+}
+
+// canAssignRole, actor'ün target üyesini (nil ise yeni üye) role rolüne getirip getiremeyeceğini döndürür.
+// Owner ve admin rolleri yalnızca owner'lar tarafından verilip geri alınabilir.
+func canAssignRole(actor *models.OrganizationMember, target *models.OrganizationMember, role models.OrganizationRole) bool {
+	if actor.Role == models.OrganizationRoleOwner {
+		return true
+	}
+	if role.IsManager() {
+		return false
+	}
+	return target == nil || !target.Role.IsManager()
+}
+
+// isLastOwner, üyenin organizasyonun tek owner'ı olup olmadığını döndürür.
+func (h *OrganizationHandler) isLastOwner(member *models.OrganizationMember) (bool, error) {
+	if member.Role != models.OrganizationRoleOwner {
+		return false, nil
+	}
+	owners, err := h.repo.Organization.CountOwners(member.OrganizationID)
+	if err != nil {
+		return false, err
+	}
+	return owners <= 1, nil
+}
+
+// CreateOrganization godoc
+// @Tags Organizations
+// @Summary Create an organization
+// @Description Creates an organization (workspace). The creator becomes its owner.
+// @Accept json
+// @Produce json
+// @Param organization body OrganizationRequest true "Organization settings"
+// @Success 201 {object} OrganizationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/organizations [post]
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	var request OrganizationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	organization := &models.Organization{CreatedBy: userID}
+	if !applyOrganizationRequest(organization, &request) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz varsayılan erişim tipi veya alan adı"})
+		return
+	}
+
+	if err := h.repo.Organization.Create(organization); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Organizasyon oluşturulamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, organizationToResponse(organization, models.OrganizationRoleOwner))
+}
+
+// GetUserOrganizations godoc
+// @Tags Organizations
+// @Summary List organizations of the current user
+// @Produce json
+// @Success 200 {array} OrganizationResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/organizations [get]
+func (h *OrganizationHandler) GetUserOrganizations(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	organizations, err := h.repo.Organization.GetByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Organizasyonlar alınamadı: " + err.Error()})
+		return
+	}
+
+	responses := make([]OrganizationResponse, 0, len(organizations))
+	for i := range organizations {
+		var role models.OrganizationRole
+		if member, err := h.repo.Organization.GetMember(organizations[i].ID, userID); err == nil {
+			role = member.Role
+		}
+		responses = append(responses, organizationToResponse(&organizations[i], role))
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetOrganization godoc
+// @Tags Organizations
+// @Summary Get an organization
+// @Produce json
+// @Param org_id path string true "Organization ID"
+// @Success 200 {object} OrganizationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/organizations/{org_id} [get]
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	organization, member, ok := h.loadOrganization(c, false)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, organizationToResponse(organization, member.Role))
+}
+
+// UpdateOrganization godoc
+// @Tags Organizations
+// @Summary Update organization settings
+// @Description Updates the name, default member access and sharing restrictions of the organization
+// @Accept json
+// @Produce json
+// @Param org_id path string true "Organization ID"
+// @Param organization body OrganizationRequest true "Organization settings"
+// @Success 200 {object} OrganizationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/organizations/{org_id} [put]
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	organization, member, ok := h.loadOrganization(c, true)
+	if !ok {
+		return
+	}
+
+	var request OrganizationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	if !applyOrganizationRequest(organization, &request) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz varsayılan erişim tipi veya alan adı"})
+		return
+	}
+
+	if err := h.repo.Organization.Update(organization); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Organizasyon güncellenemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, organizationToResponse(organization, member.Role))
+}
+
+// DeleteOrganization godoc
+// @Tags Organizations
+// @Summary Delete an organization
+// @Description Deletes an organization that no longer has documents. Only owners can delete it.
+// @Produce json
+// @Param org_id path string true "Organization ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/organizations/{org_id} [delete]
+func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
+	organization, member, ok := h.loadOrganization(c, true)
+	if !ok {
+		return
+	}
+
+	if member.Role != models.OrganizationRoleOwner {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Organizasyonu yalnızca sahipleri silebilir"})
+		return
+	}
+
+	documents, err := h.repo.Organization.CountDocuments(organization.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Organizasyon belgeleri sayılamadı: " + err.Error()})
+		return
+	}
+	if documents > 0 {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Belgeleri olan bir organizasyon silinemez"})
+		return
+	}
+
+	if err := h.repo.Organization.Delete(organization.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Organizasyon silinemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Organizasyon silindi."})
+}
+
+// GetOrganizationMembers godoc
+// @Tags Organizations
+// @Summary List organization members
+// @Produce json
+// @Param org_id path string true "Organization ID"
+// @Success 200 {array} OrganizationMemberResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/organizations/{org_id}/members [get]
+func (h *OrganizationHandler) GetOrganizationMembers(c *gin.Context) {
+	organization, _, ok := h.loadOrganization(c, false)
+	if !ok {
+		return
+	}
+
+	members, err := h.repo.Organization.GetMembers(organization.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Organizasyon üyeleri alınamadı: " + err.Error()})
+		return
+	}
+
+	responses := make([]OrganizationMemberResponse, 0, len(members))
+	for _, member := range members {
+		response := OrganizationMemberResponse{
+			UserID:    member.UserID,
+			Role:      member.Role,
+			CreatedAt: member.CreatedAt,
+		}
+		var user models.User
+		if err := h.repo.User.GetByID(member.UserID, &user); err == nil {
+			response.Username = user.Username
+			response.Email = user.Email
+		}
+		responses = append(responses, response)
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// AddOrganizationMember godoc
+// @Tags Organizations
+// @Summary Add a member to an organization
+// @Description Adds a user with the given role. Only owners can add owners or admins.
+// @Accept json
+// @Produce json
+// @Param org_id path string true "Organization ID"
+// @Param member body AddOrganizationMemberRequest true "Member data (role: 'owner', 'admin', 'member' or 'guest')"
+// @Success 201 {object} OrganizationMemberResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/organizations/{org_id}/members [post]
+func (h *OrganizationHandler) AddOrganizationMember(c *gin.Context) {
+	organization, actor, ok := h.loadOrganization(c, true)
+	if !ok {
+		return
+	}
+
+	var request AddOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+	if request.Role == "" {
+		request.Role = string(models.OrganizationRoleMember)
+	}
+	if !validOrganizationRole(request.Role) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz rol. 'owner', 'admin', 'member' veya 'guest' olmalıdır"})
+		return
+	}
+	role := models.OrganizationRole(request.Role)
+	if !canAssignRole(actor, nil, role) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu rolü yalnızca organizasyon sahipleri verebilir"})
+		return
+	}
+
+	user, err := h.repo.User.GetByEmail(request.UserEmail)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Eklenecek kullanıcı bulunamadı"})
+		return
+	}
+
+	if _, err := h.repo.Organization.GetMember(organization.ID, user.ID); err == nil {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Kullanıcı zaten organizasyonun üyesi"})
+		return
+	}
+
+	member := &models.OrganizationMember{
+		OrganizationID: organization.ID,
+		UserID:         user.ID,
+		Role:           role,
+	}
+	if err := h.repo.Organization.AddMember(member); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Üye eklenemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, OrganizationMemberResponse{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt,
+	})
+}
+
+// UpdateOrganizationMember godoc
+// @Tags Organizations
+// @Summary Change the role of an organization member
+// @Accept json
+// @Produce json
+// @Param org_id path string true "Organization ID"
+// @Param user_id path string true "User ID"
+// @Param member body UpdateOrganizationMemberRequest true "New role"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/organizations/{org_id}/members/{user_id} [put]
+func (h *OrganizationHandler) UpdateOrganizationMember(c *gin.Context) {
+	organization, actor, ok := h.loadOrganization(c, true)
+	if !ok {
+		return
+	}
+
+	targetID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz kullanıcı ID'si"})
+		return
+	}
+
+	var request UpdateOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+	if !validOrganizationRole(request.Role) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz rol. 'owner', 'admin', 'member' veya 'guest' olmalıdır"})
+		return
+	}
+	role := models.OrganizationRole(request.Role)
+
+	target, err := h.repo.Organization.GetMember(organization.ID, targetID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Organizasyon üyesi bulunamadı"})
+		return
+	}
+
+	if !canAssignRole(actor, target, role) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Yöneticilerin rollerini yalnızca organizasyon sahipleri değiştirebilir"})
+		return
+	}
+
+	if role != models.OrganizationRoleOwner {
+		lastOwner, err := h.isLastOwner(target)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Üye rolü güncellenemedi: " + err.Error()})
+			return
+		}
+		if lastOwner {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Organizasyonun son sahibinin rolü değiştirilemez"})
+			return
+		}
+	}
+
+	if err := h.repo.Organization.UpdateMemberRole(organization.ID, targetID, role); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Üye rolü güncellenemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Üye rolü güncellendi."})
+}
+
+// RemoveOrganizationMember godoc
+// @Tags Organizations
+// @Summary Remove a member from an organization
+// @Description Managers can remove members; any member can leave. The last owner cannot be removed.
+// @Produce json
+// @Param org_id path string true "Organization ID"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/organizations/{org_id}/members/{user_id} [delete]
+func (h *OrganizationHandler) RemoveOrganizationMember(c *gin.Context) {
+	organization, actor, ok := h.loadOrganization(c, false)
+	if !ok {
+		return
+	}
+
+	targetID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz kullanıcı ID'si"})
+		return
+	}
+
+	target, err := h.repo.Organization.GetMember(organization.ID, targetID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Organizasyon üyesi bulunamadı"})
+		return
+	}
+
+	if targetID != actor.UserID {
+		if !actor.Role.IsManager() {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu işlem için organizasyon yöneticisi olmalısınız"})
+			return
+		}
+		if !canAssignRole(actor, target, models.OrganizationRoleGuest) {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Yöneticileri yalnızca organizasyon sahipleri çıkarabilir"})
+			return
+		}
+	}
+
+	lastOwner, err := h.isLastOwner(target)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Üye çıkarılamadı: " + err.Error()})
+		return
+	}
+	if lastOwner {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Organizasyonun son sahibi çıkarılamaz"})
+		return
+	}
+
+	if err := h.repo.Organization.RemoveMember(organization.ID, targetID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Üye çıkarılamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Üye organizasyondan çıkarıldı."})
+}
+
+// TransferMemberDocuments godoc
+// @Tags Organizations
+// @Summary Transfer all documents of a departing member
+// @Description Managers can hand every organization document owned by a user to another non-guest member without the acceptance step. The previous owner keeps admin access and pending transfers of these documents are cancelled.
+// @Accept json
+// @Produce json
+// @Param org_id path string true "Organization ID"
+// @Param user_id path string true "Departing user ID"
+// @Param request body TransferOwnershipRequest true "Recipient"
+// @Success 200 {object} TransferMemberDocumentsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/organizations/{org_id}/members/{user_id}/transfer-documents [post]
+func (h *OrganizationHandler) TransferMemberDocuments(c *gin.Context) {
+	organization, actor, ok := h.loadOrganization(c, true)
+	if !ok {
+		return
+	}
+
+	fromID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz kullanıcı ID'si"})
+		return
+	}
+
+	// Üyeliği sona ermiş kullanıcıların belgeleri de devredilebilir; üye ise yönetici kuralları geçerlidir.
+	if from, err := h.repo.Organization.GetMember(organization.ID, fromID); err == nil {
+		if !canAssignRole(actor, from, models.OrganizationRoleGuest) {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Yöneticilerin belgelerini yalnızca organizasyon sahipleri devredebilir"})
+			return
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Üyelik kontrol edilemedi: " + err.Error()})
+		return
+	}
+
+	var request TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	recipient, err := h.repo.User.GetByEmail(request.UserEmail)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Devredilecek kullanıcı bulunamadı"})
+		return
+	}
+	if recipient.ID == fromID {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Belgeler aynı kullanıcıya devredilemez"})
+		return
+	}
+	member, err := h.repo.Organization.GetMember(organization.ID, recipient.ID)
+	if err != nil || member.Role == models.OrganizationRoleGuest {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Belgeler yalnızca misafir olmayan bir organizasyon üyesine devredilebilir"})
+		return
+	}
+
+	docs, err := h.repo.Document.InOrganization(&organization.ID).GetByOwnerID(fromID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Belgeler alınamadı: " + err.Error()})
+		return
+	}
+	documentIDs := make([]uuid.UUID, 0, len(docs))
+	for _, doc := range docs {
+		documentIDs = append(documentIDs, doc.ID)
+	}
+
+	transferred, err := h.repo.Transfer.TransferDocuments(documentIDs, fromID, recipient.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Belgeler devredilemedi: " + err.Error()})
+		return
+	}
+
+	for i := range docs {
+		if err := h.repo.Notification.Create(&models.Notification{
+			UserID:     recipient.ID,
+			Type:       models.NotificationTypeOwnershipReceived,
+			DocumentID: &docs[i].ID,
+			ActorID:    &actor.UserID,
+			Message:    fmt.Sprintf("\"%s\" belgesinin sahipliği size devredildi.", docs[i].Title),
+		}); err != nil {
+			log.Printf("TransferMemberDocuments - bildirim kaydedilemedi (belge %s): %v", docs[i].ID, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, TransferMemberDocumentsResponse{Transferred: transferred})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dione-docs-backend/internal/authz"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestDocumentEndpointsHideOtherOrganizations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	owner, home, other := uuid.New(), uuid.New(), uuid.New()
+	doc := &models.Document{
+		ID:             uuid.New(),
+		OwnerID:        owner,
+		OrganizationID: &home,
+		Version:        1,
+		Content:        []byte(`{"ops":[{"insert":"Secret\n"}]}`),
+	}
+	link := models.ShareLink{ID: uuid.New(), DocumentID: doc.ID, Token: "token", AccessType: string(models.AccessTypeViewer), CreatedBy: owner}
+	repo := &repository.Repository{
+		Document:    newFakeDocuments(doc),
+		Permission:  &fakePermissions{},
+		ShareLink:   &fakeShareLinks{links: map[string]models.ShareLink{link.Token: link}},
+		Attribution: newFakeAttributions(),
+		User:        &fakeUsers{users: map[uuid.UUID]models.User{owner: {ID: owner, Username: "owner"}}},
+	}
+	authorizer := authz.NewAuthorizer(repo.Permission)
+	hubs := NewHubManager(repo)
+	permissionHandler := NewPermissionHandler(repo, authorizer, nil, nil)
+	shareLinkHandler := NewShareLinkHandler(repo, authorizer, nil)
+	blameHandler := NewBlameHandler(repo, hubs, services.NewBlameService(repo.Attribution), authorizer)
+	chatHandler := NewChatHandler(repo, nil, nil, authorizer)
+
+	// Belgenin sahibi, isteği başka bir organizasyonun çalışma alanından yapar.
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", owner.String())
+		c.Set("organization_id", other)
+		c.Set("organization_role", models.OrganizationRoleOwner)
+		c.Next()
+	})
+	router.POST("/documents/:id/permissions/share", permissionHandler.ShareDocument)
+	router.POST("/documents/:id/links", shareLinkHandler.CreateShareLink)
+	router.POST("/links/:token/redeem", shareLinkHandler.RedeemShareLink)
+	router.GET("/documents/:id/blame", blameHandler.GetDocumentBlame)
+	router.GET("/documents/:id/messages", chatHandler.GetMessages)
+
+	tests := []struct {
+		name, method, path, body string
+	}{
+		{"share", http.MethodPost, "/documents/" + doc.ID.String() + "/permissions/share", `{"user_email":"bob@example.com","access_type":"viewer"}`},
+		{"create link", http.MethodPost, "/documents/" + doc.ID.String() + "/links", `{"access_type":"viewer"}`},
+		{"redeem link", http.MethodPost, "/links/" + link.Token + "/redeem", ""},
+		{"blame", http.MethodGet, "/documents/" + doc.ID.String() + "/blame", ""},
+		{"chat", http.MethodGet, "/documents/" + doc.ID.String() + "/messages", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			request.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(recorder, request)
+			if recorder.Code != http.StatusNotFound {
+				t.Fatalf("status %d: %s, want 404", recorder.Code, recorder.Body)
+			}
+		})
+	}
+}
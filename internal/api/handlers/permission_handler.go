@@ -2,10 +2,15 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"time"
 
+	"github.com/dione-docs-backend/internal/authz"
+	"github.com/dione-docs-backend/internal/config"
+	"github.com/dione-docs-backend/internal/mail"
 	"github.com/dione-docs-backend/internal/models"
 	"github.com/dione-docs-backend/internal/repository"
 	"github.com/dione-docs-backend/internal/utils"
@@ -16,8 +21,9 @@ import (
 
 // ShareDocumentRequest UserEmail ve AccessType alanlarını içerir.
 type ShareDocumentRequest struct {
-	UserEmail  string `json:"user_email" binding:"required,email"`
-	AccessType string `json:"access_type" binding:"required"` // "viewer" veya "editor" olmalı
+	UserEmail  string     `json:"user_email" binding:"required,email"`
+	AccessType string     `json:"access_type" binding:"required"` // "viewer", "commenter" veya "editor" olmalı
+	ExpiresAt  *time.Time `json:"expires_at"`                     // Boş ise izin süresizdir
 }
 
 // RemoveAccessRequest UserEmail alanını içerir.
@@ -33,11 +39,25 @@ type PermissionResponse struct {
 	UserID     uuid.UUID               `json:"user_id"`
 	UserEmail  string                  `json:"user_email"`
 	AccessType string                  `json:"access_type"`
-	Status     models.PermissionStatus `json:"status"`              // Eklendi
-	SharedBy   string                  `json:"shared_by,omitempty"` // Daveti gönderenin e-postası (opsiyonel)
+	Status     models.PermissionStatus `json:"status"`               // Eklendi
+	SharedBy   string                  `json:"shared_by,omitempty"`  // Daveti gönderenin e-postası (opsiyonel)
+	ExpiresAt  *time.Time              `json:"expires_at,omitempty"` // İznin sona ereceği zaman (opsiyonel)
 	// DocumentTitle string            `json:"document_title,omitempty"` // Davetiyeler listelenirken gerekebilir
 }
 
+// EmailInvitationResponse, hesabı olmayan bir adrese gönderilmiş daveti döndürür.
+type EmailInvitationResponse struct {
+	ID         uuid.UUID                    `json:"id"`
+	DocumentID uuid.UUID                    `json:"document_id"`
+	Email      string                       `json:"email"`
+	AccessType string                       `json:"access_type"`
+	Status     models.EmailInvitationStatus `json:"status"`
+	InvitedBy  string                       `json:"invited_by,omitempty"` // daveti gönderenin e-postası
+	ExpiresAt  *time.Time                   `json:"expires_at,omitempty"`
+	EmailSent  bool                         `json:"email_sent"`
+	CreatedAt  time.Time                    `json:"created_at"`
+}
+
 // InvitationDetailResponse, davetiyeleri listelerken kullanılabilir.
 type InvitationDetailResponse struct {
 	InvitationID  uuid.UUID               `json:"invitation_id"` // Permission ID
@@ -61,23 +81,43 @@ type MessageResponse struct {
 //	 Error string `json:"error"`
 // }
 
+// sameExpiry, iki bitiş zamanının aynı olup olmadığını döndürür (ikisi de nil olabilir).
+func sameExpiry(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
 type PermissionHandler struct {
-	repo *repository.Repository
+	repo       *repository.Repository
+	authorizer *authz.Authorizer
+	mailer     mail.Sender
+	config     *config.Config
 }
 
-func NewPermissionHandler(repo *repository.Repository) *PermissionHandler {
+func NewPermissionHandler(repo *repository.Repository, authorizer *authz.Authorizer, mailer mail.Sender, cfg *config.Config) *PermissionHandler {
 	return &PermissionHandler{
-		repo: repo,
+		repo:       repo,
+		authorizer: authorizer,
+		mailer:     mailer,
+		config:     cfg,
 	}
 }
 
+// documents, isteğin aktif çalışma alanıyla (X-Organization-ID) sınırlandırılmış belge repository'sini döndürür.
+func (h *PermissionHandler) documents(c *gin.Context) repository.DocumentRepository {
+	organizationID, _ := utils.GetOrganizationFromContext(c)
+	return h.repo.Document.InOrganization(organizationID)
+}
+
 // @Tags Permissions
 // @Summary Share a document with a user (send invitation)
-// @Description Share a document with a user by providing the access type (viewer, editor). This creates a pending invitation.
+// @Description Share a document with a user by providing the access type (viewer, commenter, editor) and an optional expires_at. This creates a pending invitation. If no account uses the email, an invitation email is sent instead (EmailInvitationResponse); it becomes a pending invitation when the address registers.
 // @Accept json
 // @Produce json
 // @Param id path string true "Document ID"
-// @Param ShareDocumentRequest body ShareDocumentRequest true "Share document request (access_type: 'viewer' or 'editor')"
+// @Param ShareDocumentRequest body ShareDocumentRequest true "Share document request (access_type: 'viewer', 'commenter' or 'editor')"
 // @Success 201 {object} PermissionResponse "Invitation sent successfully"
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
@@ -100,20 +140,14 @@ func (h *PermissionHandler) ShareDocument(c *gin.Context) {
 	}
 
 	var doc models.Document
-	if err := h.repo.Document.GetByID(docID, &doc); err != nil {
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
 		return
 	}
 
 	// Yetkilendirme: Sadece doküman sahibi veya doküman üzerinde "admin" yetkisi olanlar paylaşabilir
-	if doc.OwnerID != sharerUserID {
-		// Burada GetByDocumentAndUser yerine GetAcceptedByDocumentAndUser kullanmak daha doğru olabilir
-		// çünkü admin yetkisinin kabul edilmiş bir izin olması gerekir.
-		permission, err := h.repo.Permission.GetAcceptedByDocumentAndUser(docID, sharerUserID)
-		if err != nil || permission == nil || permission.AccessType != "admin" {
-			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu belgeyi paylaşma izniniz yok"})
-			return
-		}
+	if !authorize(c, h.authorizer, sharerUserID, authz.ActionShare, &doc, "Bu belgeyi paylaşma izniniz yok") {
+		return
 	}
 
 	var shareRequest ShareDocumentRequest
@@ -122,14 +156,25 @@ func (h *PermissionHandler) ShareDocument(c *gin.Context) {
 		return
 	}
 
-	if shareRequest.AccessType != string(models.AccessTypeViewer) && shareRequest.AccessType != string(models.AccessTypeEditor) {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz erişim tipi. 'viewer' veya 'editor' olmalıdır"})
+	if shareRequest.AccessType != string(models.AccessTypeViewer) && shareRequest.AccessType != string(models.AccessTypeCommenter) &&
+		shareRequest.AccessType != string(models.AccessTypeEditor) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz erişim tipi. 'viewer', 'commenter' veya 'editor' olmalıdır"})
+		return
+	}
+
+	if shareRequest.ExpiresAt != nil && !shareRequest.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Son kullanma tarihi gelecekte olmalıdır"})
 		return
 	}
 
 	targetUser, err := h.repo.User.GetByEmail(shareRequest.UserEmail)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Hesabı olmayan adreslere e-posta daveti gönderilir; kayıt olduklarında davetiyeye dönüşür.
+		h.inviteByEmail(c, &doc, sharerUserID, &shareRequest)
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Davet edilecek kullanıcı bulunamadı"})
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Davet edilecek kullanıcı alınamadı: " + err.Error()})
 		return
 	}
 
@@ -138,16 +183,35 @@ func (h *PermissionHandler) ShareDocument(c *gin.Context) {
 		return
 	}
 
+	allowed, err := sharingAllowed(h.repo, &doc, targetUser)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Paylaşım kısıtlamaları kontrol edilemedi: " + err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Organizasyon, belgenin bu kullanıcıyla paylaşılmasına izin vermiyor"})
+		return
+	}
+
 	// Mevcut izni/davetiyeyi kontrol et
 	existingPermission, err := h.repo.Permission.GetByDocumentAndUserWithAnyStatus(docID, targetUser.ID)
 	if err == nil && existingPermission != nil {
 		// Eğer zaten kabul edilmiş bir izin varsa ve yetki tipi farklıysa güncelle (opsiyonel, isteğe bağlı)
 		if existingPermission.Status == models.PermissionStatusAccepted {
-			if existingPermission.AccessType != shareRequest.AccessType {
+			if existingPermission.AccessType != shareRequest.AccessType || !sameExpiry(existingPermission.ExpiresAt, shareRequest.ExpiresAt) {
 				if err := h.repo.Permission.UpdateAccessType(existingPermission.ID, shareRequest.AccessType); err != nil {
 					c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Mevcut erişim izni güncellenemedi: " + err.Error()})
 					return
 				}
+				if err := h.repo.Permission.UpdateExpiresAt(existingPermission.ID, shareRequest.ExpiresAt); err != nil {
+					c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Mevcut erişim izninin süresi güncellenemedi: " + err.Error()})
+					return
+				}
+				// Doğrudan paylaşılan izin, bağlantı iptal edildiğinde silinmemelidir.
+				if err := h.repo.Permission.UpdateShareLink(existingPermission.ID, nil); err != nil {
+					c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Mevcut erişim izni güncellenemedi: " + err.Error()})
+					return
+				}
 				c.JSON(http.StatusOK, MessageResponse{Message: "Kullanıcının erişim izni güncellendi."})
 				return
 			}
@@ -156,6 +220,16 @@ func (h *PermissionHandler) ShareDocument(c *gin.Context) {
 		}
 		// Eğer bekleyen bir davetiye varsa ve yetki tipi farklıysa güncelle
 		if existingPermission.Status == models.PermissionStatusPending {
+			if !sameExpiry(existingPermission.ExpiresAt, shareRequest.ExpiresAt) {
+				if err := h.repo.Permission.UpdateExpiresAt(existingPermission.ID, shareRequest.ExpiresAt); err != nil {
+					c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Bekleyen davetiyenin süresi güncellenemedi: " + err.Error()})
+					return
+				}
+				if existingPermission.AccessType == shareRequest.AccessType {
+					c.JSON(http.StatusOK, MessageResponse{Message: "Bekleyen davetiyenin süresi güncellendi."})
+					return
+				}
+			}
 			if existingPermission.AccessType != shareRequest.AccessType {
 				// GORM'da Update ile birden fazla alanı güncellemek için map veya struct kullanmak daha iyi.
 				// Şimdilik ayrı metodlar varsayımıyla devam ediyorum, UpdateAccessType sadece access_type'ı güncelliyor.
@@ -183,6 +257,7 @@ func (h *PermissionHandler) ShareDocument(c *gin.Context) {
 		AccessType: shareRequest.AccessType,
 		Status:     models.PermissionStatusPending, // Durumu 'pending' olarak ayarla
 		SharedBy:   sharerUserID,                   // Daveti göndereni kaydet
+		ExpiresAt:  shareRequest.ExpiresAt,
 	}
 
 	if err := h.repo.Permission.Create(newPermission); err != nil {
@@ -200,6 +275,7 @@ func (h *PermissionHandler) ShareDocument(c *gin.Context) {
 		UserEmail:  targetUser.Email,
 		AccessType: newPermission.AccessType,
 		Status:     newPermission.Status,
+		ExpiresAt:  newPermission.ExpiresAt,
 	}
 
 	// sharerUser alınırken bir hata oluşmadıysa e-postasını ekle
@@ -216,7 +292,7 @@ func (h *PermissionHandler) ShareDocument(c *gin.Context) {
 // RemoveAccess bir kullanıcının dokümana olan kabul edilmiş erişimini veya bekleyen davetiyesini kaldırır/reddeder.
 // @Tags Permissions
 // @Summary Remove a user's access to a document or reject/cancel an invitation
-// @Description Remove a specific user's accepted access to a document, or reject/cancel a pending invitation.
+// @Description Remove a specific user's accepted access to a document, or reject/cancel a pending invitation. For emails without an account, the pending email invitation is revoked.
 // @Accept json
 // @Produce json
 // @Param id path string true "Document ID"
@@ -243,7 +319,7 @@ func (h *PermissionHandler) RemoveAccess(c *gin.Context) {
 	}
 
 	var doc models.Document
-	if err := h.repo.Document.GetByID(docID, &doc); err != nil {
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
 		return
 	}
@@ -256,7 +332,7 @@ func (h *PermissionHandler) RemoveAccess(c *gin.Context) {
 
 	targetUser, err := h.repo.User.GetByEmail(removeRequest.UserEmail)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Erişimi kaldırılacak kullanıcı bulunamadı"})
+		h.revokeEmailInvitation(c, &doc, removerUserID, removeRequest.UserEmail)
 		return
 	}
 
@@ -269,19 +345,16 @@ func (h *PermissionHandler) RemoveAccess(c *gin.Context) {
 
 	// Yetkilendirme: Sadece doküman sahibi veya doküman üzerinde "admin" yetkisi olanlar erişimi kaldırabilir.
 	// VEYA, eğer bekleyen bir davetiyeyse ve daveti gönderen kişi işlemi yapıyorsa.
-	isOwner := doc.OwnerID == removerUserID
-	isAdmin := false
-	if !isOwner {
-		removerPermission, _ := h.repo.Permission.GetAcceptedByDocumentAndUser(docID, removerUserID)
-		if removerPermission != nil && removerPermission.AccessType == "admin" {
-			isAdmin = true
-		}
+	canShare, err := h.authorizer.Can(removerUserID, authz.ActionShare, &doc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Yetki kontrolü sırasında bir hata oluştu."})
+		return
 	}
 	isSelfCancellingPending := permissionToRemove.Status == models.PermissionStatusPending && permissionToRemove.SharedBy == removerUserID && targetUser.ID != removerUserID
 	// Kendi bekleyen davetiyesini de hedef kullanıcı reddedebilir (bu Accept/Reject endpoint'lerinde ele alınacak)
 	// isTargetUserRejectingOwnPending := permissionToRemove.Status == models.PermissionStatusPending && permissionToRemove.UserID == removerUserID
 
-	if !isOwner && !isAdmin && !isSelfCancellingPending {
+	if !canShare && !isSelfCancellingPending {
 		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu belgenin erişimini kaldırma izniniz yok"})
 		return
 	}
@@ -326,18 +399,14 @@ func (h *PermissionHandler) GetDocumentPermissions(c *gin.Context) {
 	}
 
 	var doc models.Document
-	if err := h.repo.Document.GetByID(docID, &doc); err != nil {
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
 		return
 	}
 
 	// Yetkilendirme: Sadece doküman sahibi veya doküman üzerinde "admin" yetkisi olanlar izinleri görebilir.
-	if doc.OwnerID != requestingUserID {
-		permission, err := h.repo.Permission.GetAcceptedByDocumentAndUser(docID, requestingUserID)
-		if err != nil || permission == nil || permission.AccessType != "admin" {
-			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu belgenin izinlerini görüntüleme yetkiniz yok"})
-			return
-		}
+	if !authorize(c, h.authorizer, requestingUserID, authz.ActionShare, &doc, "Bu belgenin izinlerini görüntüleme yetkiniz yok") {
+		return
 	}
 
 	// GetByDocument artık sadece kabul edilmişleri getirmeli (repository'de güncellendi varsayımı)
@@ -370,6 +439,7 @@ func (h *PermissionHandler) GetDocumentPermissions(c *gin.Context) {
 			AccessType: perm.AccessType,
 			Status:     perm.Status,
 			SharedBy:   sharerEmail,
+			ExpiresAt:  perm.ExpiresAt,
 		})
 	}
 
@@ -393,7 +463,8 @@ func (h *PermissionHandler) GetPendingInvitations(c *gin.Context) {
 		return
 	}
 
-	invitations, err := h.repo.Permission.GetPendingInvitationsByUserID(userID)
+	organizationID, _ := utils.GetOrganizationFromContext(c)
+	invitations, err := h.repo.Permission.InOrganization(organizationID).GetPendingInvitationsByUserID(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Bekleyen davetiyeler alınamadı: " + err.Error()})
 		return
@@ -403,7 +474,7 @@ func (h *PermissionHandler) GetPendingInvitations(c *gin.Context) {
 	for _, inv := range invitations {
 		doc := models.Document{}
 		docTitle := "Bilinmeyen Belge"
-		if err := h.repo.Document.GetByID(inv.DocumentID, &doc); err == nil {
+		if err := h.documents(c).GetByID(inv.DocumentID, &doc); err == nil {
 			docTitle = doc.Title
 		}
 
@@ -467,6 +538,11 @@ func (h *PermissionHandler) AcceptInvitation(c *gin.Context) {
 		return
 	}
 
+	if invitation.IsExpired(time.Now()) {
+		c.JSON(http.StatusGone, ErrorResponse{Error: "Bu davetiyenin süresi dolmuş."})
+		return
+	}
+
 	if err := h.repo.Permission.UpdateStatus(invitationID, models.PermissionStatusAccepted); err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Davetiye kabul edilemedi: " + err.Error()})
 		return
@@ -533,19 +609,18 @@ type GetUserDocumentPermissionResponse struct {
 	AccessType string `json:"access_type"`
 }
 
-// GetDocumentPermission godoc
-// @Summary      Get user's permission for a document
-// @Description  Retrieves the access type for the authenticated user on a specific document.
-// @Tags         permissions
-// @Accept       json
-// @Produce      json
-// @Param        permissionRequest body GetDocumentPermissionRequest true "Document ID"
-// @Success      200  {object}  GetDocumentPermissionResponse
-// @Failure      400  {object}  ErrorResponse "Invalid request format or invalid UUID"
-// @Failure      401  {object}  ErrorResponse "Unauthorized"
-// @Failure      404  {object}  ErrorResponse "Permission not found for this document"
-// @Failure      500  {object}  ErrorResponse "Internal server error"
-// @Router       /api/v1/permissions/document [post] // Örnek bir rota, kendi rotanızla güncelleyin
+// GetUserDocumentPermission godoc
+// @Tags Permissions
+// @Summary Get the current user's role on a document
+// @Description Returns the highest role the authenticated user has on the document through ownership, direct, team or organization access.
+// @Produce json
+// @Param id path string true "Document ID"
+// @Success 200 {object} GetUserDocumentPermissionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/role [get]
 func (h *PermissionHandler) GetUserDocumentPermission(c *gin.Context) {
 	userID, err := utils.GetUserIDFromContext(c)
 	if err != nil {
@@ -565,27 +640,190 @@ func (h *PermissionHandler) GetUserDocumentPermission(c *gin.Context) {
 		return
 	}
 
-	permission, err := h.repo.Permission.GetByDocumentAndUser(documentUUID, userID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			var doc models.Document
-			if h.repo.Document.GetByID(documentUUID, &doc) == nil {
-				if doc.OwnerID == userID {
-					c.JSON(http.StatusOK, GetUserDocumentPermissionResponse{
-						AccessType: "owner",
-					})
-					return
-				}
-			}
+	var doc models.Document
+	if err := h.documents(c).GetByID(documentUUID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Bu doküman için yetkiniz bulunmamaktadır."})
+		return
+	}
 
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Bu doküman için yetkiniz bulunmamaktadır."})
-			return
-		}
+	// Sahiplik, doğrudan, takım ve organizasyon izinlerinden en yetkili olanı döner.
+	role, err := h.authorizer.Role(userID, &doc)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Yetki kontrolü sırasında bir hata oluştu."})
 		return
 	}
 
+	if role == authz.RoleNone {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Bu doküman için yetkiniz bulunmamaktadır."})
+		return
+	}
+
 	c.JSON(http.StatusOK, GetUserDocumentPermissionResponse{
-		AccessType: permission.AccessType,
+		AccessType: string(role),
 	})
 }
+
+// inviteByEmail, hesabı olmayan bir adrese e-posta daveti oluşturur veya bekleyen daveti günceller
+// ve davet e-postasını gönderir. E-posta gönderilemese de davet saklanır; adres kayıt olduğunda
+// bekleyen bir davetiyeye dönüşür.
+func (h *PermissionHandler) inviteByEmail(c *gin.Context, doc *models.Document, inviterID uuid.UUID, request *ShareDocumentRequest) {
+	email := models.NormalizeEmail(request.UserEmail)
+
+	allowed, err := sharingAllowed(h.repo, doc, &models.User{Email: email})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Paylaşım kısıtlamaları kontrol edilemedi: " + err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Organizasyon, belgenin bu kullanıcıyla paylaşılmasına izin vermiyor"})
+		return
+	}
+
+	status := http.StatusCreated
+	invitation, err := h.repo.EmailInvite.GetPending(doc.ID, email)
+	switch {
+	case err == nil:
+		invitation.AccessType = request.AccessType
+		invitation.ExpiresAt = request.ExpiresAt
+		invitation.InvitedBy = inviterID
+		if err := h.repo.EmailInvite.Update(invitation); err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "E-posta daveti güncellenemedi: " + err.Error()})
+			return
+		}
+		status = http.StatusOK
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		invitation = &models.EmailInvitation{
+			DocumentID: doc.ID,
+			Email:      email,
+			AccessType: request.AccessType,
+			Status:     models.EmailInvitationStatusPending,
+			InvitedBy:  inviterID,
+			ExpiresAt:  request.ExpiresAt,
+		}
+		if err := h.repo.EmailInvite.Create(invitation); err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "E-posta daveti oluşturulamadı: " + err.Error()})
+			return
+		}
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "E-posta davetleri kontrol edilemedi: " + err.Error()})
+		return
+	}
+
+	var inviter models.User
+	if err := h.repo.User.GetByID(inviterID, &inviter); err != nil {
+		log.Printf("inviteByEmail: Daveti gönderen kullanıcı bilgisi alınamadı (ID: %s), hata: %v", inviterID, err)
+	}
+
+	response := h.emailInvitationToResponse(invitation, inviter.Email)
+	if err := h.mailer.Send(invitationMessage(h.config.AppURL, invitation, doc, &inviter)); err != nil {
+		log.Printf("inviteByEmail: Davet e-postası gönderilemedi (%s): %v", email, err)
+	} else {
+		response.EmailSent = true
+	}
+	c.JSON(status, response)
+}
+
+// invitationMessage, hesabı olmayan bir adrese gönderilecek davet e-postasını oluşturur.
+func invitationMessage(appURL string, invitation *models.EmailInvitation, doc *models.Document, inviter *models.User) mail.Message {
+	who := inviter.Username
+	if who == "" {
+		who = "Bir kullanıcı"
+	}
+	return mail.Message{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("%s sizi \"%s\" belgesine davet etti", who, doc.Title),
+		Body: fmt.Sprintf("Merhaba,\n\n%s sizi Dione Docs'ta \"%s\" belgesine %s olarak davet etti.\n\n"+
+			"Daveti görmek için bu e-posta adresiyle kayıt olun:\n%s/register?email=%s\n",
+			who, doc.Title, invitation.AccessType, appURL, url.QueryEscape(invitation.Email)),
+	}
+}
+
+func (h *PermissionHandler) emailInvitationToResponse(invitation *models.EmailInvitation, inviterEmail string) EmailInvitationResponse {
+	return EmailInvitationResponse{
+		ID:         invitation.ID,
+		DocumentID: invitation.DocumentID,
+		Email:      invitation.Email,
+		AccessType: invitation.AccessType,
+		Status:     invitation.Status,
+		InvitedBy:  inviterEmail,
+		ExpiresAt:  invitation.ExpiresAt,
+		CreatedAt:  invitation.CreatedAt,
+	}
+}
+
+// revokeEmailInvitation, hesabı olmayan bir adrese gönderilmiş bekleyen daveti geri alır.
+// Belgeyi paylaşabilenler veya daveti gönderen kullanıcı geri alabilir.
+func (h *PermissionHandler) revokeEmailInvitation(c *gin.Context, doc *models.Document, userID uuid.UUID, email string) {
+	invitation, err := h.repo.EmailInvite.GetPending(doc.ID, email)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Erişimi kaldırılacak kullanıcı bulunamadı"})
+		return
+	}
+
+	if invitation.InvitedBy != userID &&
+		!authorize(c, h.authorizer, userID, authz.ActionShare, doc, "Bu belgenin erişimini kaldırma izniniz yok") {
+		return
+	}
+
+	if _, err := h.repo.EmailInvite.DeletePending(doc.ID, email); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "E-posta daveti geri alınamadı: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: "E-posta daveti geri alındı"})
+}
+
+// @Tags Permissions
+// @Summary Get pending email invitations of a document
+// @Description Lists invitations sent to email addresses that do not have an account yet.
+// @Produce json
+// @Param id path string true "Document ID"
+// @Success 200 {array} EmailInvitationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /documents/{id}/permissions/email-invitations [get]
+func (h *PermissionHandler) GetEmailInvitations(c *gin.Context) {
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz belge ID'si"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	var doc models.Document
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return
+	}
+
+	if !authorize(c, h.authorizer, userID, authz.ActionShare, &doc, "Bu belgenin izinlerini görüntüleme yetkiniz yok") {
+		return
+	}
+
+	invitations, err := h.repo.EmailInvite.GetPendingByDocument(doc.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "E-posta davetleri alınamadı: " + err.Error()})
+		return
+	}
+
+	inviters := make(map[uuid.UUID]string)
+	responses := make([]EmailInvitationResponse, 0, len(invitations))
+	for i := range invitations {
+		inviterID := invitations[i].InvitedBy
+		if _, ok := inviters[inviterID]; !ok {
+			var inviter models.User
+			if err := h.repo.User.GetByID(inviterID, &inviter); err == nil {
+				inviters[inviterID] = inviter.Email
+			}
+		}
+		responses = append(responses, h.emailInvitationToResponse(&invitations[i], inviters[inviterID]))
+	}
+	c.JSON(http.StatusOK, responses)
+}
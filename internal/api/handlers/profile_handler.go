@@ -0,0 +1,518 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dione-docs-backend/internal/avatar"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/services"
+	"github.com/dione-docs-backend/internal/storage"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// maxAvatarUploadSize, yüklenebilecek en büyük avatar dosyasıdır.
+const maxAvatarUploadSize = 5 << 20
+
+type ProfileHandler struct {
+	repo     *repository.Repository
+	accounts *services.AccountService
+	avatars  *services.AvatarService
+	privacy  *services.PrivacyService
+	mfa      *services.MFAService
+}
+
+func NewProfileHandler(repo *repository.Repository, accounts *services.AccountService, avatars *services.AvatarService, privacy *services.PrivacyService, mfa *services.MFAService) *ProfileHandler {
+	return &ProfileHandler{
+		repo:     repo,
+		accounts: accounts,
+		avatars:  avatars,
+		privacy:  privacy,
+		mfa:      mfa,
+	}
+}
+
+// UpdateProfileRequest, yalnızca gönderilen alanları günceller.
+type UpdateProfileRequest struct {
+	FullName *string `json:"full_name" binding:"omitempty,max=100"`
+	Username *string `json:"username" binding:"omitempty,min=3,max=50"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+}
+
+type ChangeEmailRequest struct {
+	NewEmail        string `json:"new_email" binding:"required,email"`
+	CurrentPassword string `json:"current_password"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// DeleteAccountRequest, hesabı silmek için gereken onayı ve sahip olunan belgelerin ne
+// olacağını belirtir. Documents "transfer" ise belgeler transfer_to adresli kullanıcıya devredilir,
+// "delete" ise kalıcı olarak silinir.
+type DeleteAccountRequest struct {
+	Confirmation    string `json:"confirmation" binding:"required"` // hesabın e-posta adresi
+	CurrentPassword string `json:"current_password"`
+	MFACode         string `json:"mfa_code"`
+	Documents       string `json:"documents" binding:"required,oneof=transfer delete"`
+	TransferTo      string `json:"transfer_to" binding:"omitempty,email"`
+}
+
+// UpdateProfile godoc
+// @Summary Update profile
+// @Description Updates the current user's full name and/or username. Omitted fields are left unchanged.
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateProfileRequest true "Profile fields"
+// @Success 200 {object} UserResponse
+// @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 409 {object} ErrorResponse "Username already in use"
+// @Router /api/v1/me [patch]
+func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request data"})
+		return
+	}
+
+	fields := make(map[string]interface{})
+	if req.FullName != nil {
+		user.FullName = strings.TrimSpace(*req.FullName)
+		fields["full_name"] = user.FullName
+	}
+	if req.Username != nil {
+		username := strings.TrimSpace(*req.Username)
+		if username == "" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Username cannot be empty"})
+			return
+		}
+		if username != user.Username {
+			existing, err := h.repo.User.GetByUsername(username)
+			if err == nil && existing.ID != user.ID {
+				c.JSON(http.StatusConflict, ErrorResponse{Error: "Username already in use"})
+				return
+			}
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update profile"})
+				return
+			}
+			user.Username = username
+			fields["username"] = username
+		}
+	}
+
+	if len(fields) > 0 {
+		if err := h.repo.User.UpdateFields(user.ID, fields); err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update profile"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// UploadAvatar godoc
+// @Summary Upload avatar
+// @Description Uploads a profile picture (PNG, JPEG or GIF, max 5 MB). The image is cropped to a square and resized to 32, 64, 128 and 256 pixels.
+// @Tags Profile
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param avatar formData file true "Image file"
+// @Success 200 {object} UserResponse
+// @Failure 400 {object} ErrorResponse "Missing or unsupported image"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 413 {object} ErrorResponse "Image too large"
+// @Router /api/v1/me/avatar [post]
+func (h *ProfileHandler) UploadAvatar(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Avatar file is required"})
+		return
+	}
+	if fileHeader.Size > maxAvatarUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "Image too large"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Could not read avatar file"})
+		return
+	}
+	defer file.Close()
+
+	err = h.avatars.Upload(c.Request.Context(), user, io.LimitReader(file, maxAvatarUploadSize))
+	if errors.Is(err, avatar.ErrUnsupportedImage) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Unsupported or invalid image"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to save avatar"})
+		return
+	}
+
+	c.JSON(http.StatusOK, newUserResponse(user))
+}
+
+// DeleteAvatar godoc
+// @Summary Remove avatar
+// @Description Removes the current user's profile picture.
+// @Tags Profile
+// @Security BearerAuth
+// @Success 204 "Avatar removed"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Router /api/v1/me/avatar [delete]
+func (h *ProfileHandler) DeleteAvatar(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.avatars.Delete(c.Request.Context(), user); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to remove avatar"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetAvatar godoc
+// @Summary Get a user's avatar
+// @Description Returns a user's uploaded avatar as PNG in one of the standard sizes (32, 64, 128, 256).
+// @Tags Profile
+// @Produce png
+// @Param user_id path string true "User ID"
+// @Param size path int true "Size in pixels"
+// @Success 200 {file} binary
+// @Failure 404 {object} ErrorResponse "Avatar not found"
+// @Router /api/v1/users/{user_id}/avatar/{size} [get]
+func (h *ProfileHandler) GetAvatar(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Avatar not found"})
+		return
+	}
+	size, err := strconv.Atoi(c.Param("size"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Avatar not found"})
+		return
+	}
+
+	reader, err := h.avatars.Open(c.Request.Context(), userID, size)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Avatar not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to read avatar"})
+		return
+	}
+	defer reader.Close()
+
+	// URL'ler avatar sürümünü içerdiği için içerik değişmez ve uzun süre önbelleklenebilir.
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.DataFromReader(http.StatusOK, -1, "image/png", reader, nil)
+}
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Changes the current user's password after checking the current one. Users who signed up with Google can set a password without a current one. All other sessions are signed out.
+// @Tags Profile
+// @Accept json
+// @Security BearerAuth
+// @Param request body ChangePasswordRequest true "Current and new password"
+// @Success 204 "Password changed"
+// @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Current password is incorrect"
+// @Router /api/v1/me/password [post]
+func (h *ProfileHandler) ChangePassword(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request data"})
+		return
+	}
+
+	var keepSession *uuid.UUID
+	if sessionID, err := utils.GetSessionIDFromContext(c); err == nil {
+		keepSession = &sessionID
+	}
+
+	err := h.accounts.ChangePassword(user, req.CurrentPassword, req.NewPassword, keepSession)
+	if errors.Is(err, services.ErrInvalidPassword) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Current password is incorrect"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to change password"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ChangeEmail godoc
+// @Summary Change email address
+// @Description Starts an email change. A confirmation link is sent to the new address and the change takes effect once it is confirmed; the old address is notified.
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangeEmailRequest true "New email and current password"
+// @Success 202 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Current password is incorrect"
+// @Failure 409 {object} ErrorResponse "Email already in use"
+// @Router /api/v1/me/email [post]
+func (h *ProfileHandler) ChangeEmail(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request data"})
+		return
+	}
+	if user.PasswordHash != "" && bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)) != nil {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Current password is incorrect"})
+		return
+	}
+	if strings.EqualFold(req.NewEmail, user.Email) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "New email is the same as the current one"})
+		return
+	}
+
+	err := h.accounts.RequestEmailChange(user, req.NewEmail)
+	if errors.Is(err, services.ErrEmailInUse) {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Email already in use"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to start email change"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, MessageResponse{Message: "Confirmation link sent to the new email address"})
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirm email change
+// @Description Completes an email change with the token sent to the new address. The new address becomes the verified account email.
+// @Tags Profile
+// @Accept json
+// @Param request body ConfirmEmailChangeRequest true "Confirmation token"
+// @Success 204 "Email changed"
+// @Failure 400 {object} ErrorResponse "Invalid request data or invalid/expired token"
+// @Failure 409 {object} ErrorResponse "Email already in use"
+// @Router /api/v1/auth/email/change/confirm [post]
+func (h *ProfileHandler) ConfirmEmailChange(c *gin.Context) {
+	var req ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request data"})
+		return
+	}
+
+	err := h.accounts.ConfirmEmailChange(req.Token)
+	switch {
+	case errors.Is(err, services.ErrInvalidAccountToken):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid or expired token"})
+		return
+	case errors.Is(err, services.ErrEmailInUse):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Email already in use"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to change email"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteAccount godoc
+// @Summary Delete account
+// @Description Permanently deletes the current user's account. The request must repeat the account email as confirmation and include the current password (if one is set) and a two-factor code (if enabled). Owned documents are either transferred to another user or permanently deleted. Chat messages are kept but anonymized; permissions, memberships, sessions and access tokens are removed.
+// @Tags Profile
+// @Accept json
+// @Security BearerAuth
+// @Param request body DeleteAccountRequest true "Confirmation and document handling"
+// @Success 204 "Account deleted"
+// @Failure 400 {object} ErrorResponse "Invalid request data or transfer recipient"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Incorrect password or two-factor code"
+// @Failure 404 {object} ErrorResponse "Transfer recipient not found"
+// @Failure 409 {object} ErrorResponse "User is the only owner of an organization"
+// @Router /api/v1/me [delete]
+func (h *ProfileHandler) DeleteAccount(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request data"})
+		return
+	}
+	if !strings.EqualFold(strings.TrimSpace(req.Confirmation), user.Email) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Confirmation does not match the account email"})
+		return
+	}
+
+	var recipient *models.User
+	if req.Documents == "transfer" {
+		if req.TransferTo == "" {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "transfer_to is required to transfer documents"})
+			return
+		}
+		var err error
+		if recipient, err = h.repo.User.GetByEmail(req.TransferTo); err != nil {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Transfer recipient not found"})
+			return
+		}
+	}
+
+	if user.PasswordHash != "" && bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.CurrentPassword)) != nil {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Current password is incorrect"})
+		return
+	}
+	mfaEnabled, err := h.mfa.IsEnabled(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to check two-factor settings"})
+		return
+	}
+	if mfaEnabled {
+		err := h.mfa.Verify(user.ID, req.MFACode)
+		if errors.Is(err, services.ErrInvalidMFACode) {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Invalid two-factor code"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify two-factor code"})
+			return
+		}
+	}
+
+	err = h.privacy.DeleteAccount(c.Request.Context(), user, recipient)
+	switch {
+	case errors.Is(err, services.ErrSoleOrganizationOwner):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "You are the only owner of an organization with other members; transfer ownership or delete it first"})
+		return
+	case errors.Is(err, services.ErrInvalidTransferRecipient):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Documents can only be transferred to another user who is a non-guest member of their organizations"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete account"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ExportAccountData godoc
+// @Summary Export account data
+// @Description Downloads a zip archive with everything stored about the current user: profile, owned documents, permissions, chat messages, comments, suggestions, notifications, sessions, access tokens, linked identities, memberships, access requests, ownership transfers and uploaded avatars. Secrets such as password and token hashes are not included.
+// @Tags Profile
+// @Produce application/zip
+// @Security BearerAuth
+// @Success 200 {file} binary
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Export failed"
+// @Router /api/v1/me/data-export [get]
+func (h *ProfileHandler) ExportAccountData(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	// Arşiv önce bellekte oluşturulur; böylece bir hata yarım bir dosya yerine hata yanıtı döndürür.
+	var archive bytes.Buffer
+	if err := h.privacy.Export(c.Request.Context(), user, &archive); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to export account data"})
+		return
+	}
+
+	filename := fmt.Sprintf("dione-docs-export-%s.zip", time.Now().Format("20060102"))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", archive.Bytes())
+}
+
+func (h *ProfileHandler) currentUser(c *gin.Context) (*models.User, bool) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Unauthorized"})
+		return nil, false
+	}
+	var user models.User
+	if err := h.repo.User.GetByID(userID, &user); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		return nil, false
+	}
+	return &user, true
+}
+
+// newUserResponse, kullanıcının profil bilgilerini ve avatar URL'lerini döndürür.
+func newUserResponse(user *models.User) UserResponse {
+	response := UserResponse{
+		UserId:        user.ID.String(),
+		Username:      user.Username,
+		Email:         user.Email,
+		FullName:      user.DisplayName(),
+		EmailVerified: user.EmailVerified,
+		PendingEmail:  user.PendingEmail,
+		IsSiteAdmin:   user.IsSiteAdmin,
+	}
+
+	switch {
+	case services.IsStoredAvatar(user.ProfileImage):
+		response.AvatarURLs = make(map[string]string, len(avatar.Sizes))
+		version := services.AvatarVersion(user.ProfileImage)
+		for _, size := range avatar.Sizes {
+			response.AvatarURLs[strconv.Itoa(size)] = "/api/v1/users/" + user.ID.String() + "/avatar/" + strconv.Itoa(size) + "?v=" + version
+		}
+	case user.ProfileImage != "":
+		// Harici profil resimleri (ör. Google) tek boyutta gelir.
+		response.AvatarURLs = make(map[string]string, len(avatar.Sizes))
+		for _, size := range avatar.Sizes {
+			response.AvatarURLs[strconv.Itoa(size)] = user.ProfileImage
+		}
+	}
+	return response
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dione-docs-backend/internal/authz"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/services"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RetentionHandler struct {
+	repo       *repository.Repository
+	retention  *services.RetentionService
+	authorizer *authz.Authorizer
+}
+
+func NewRetentionHandler(repo *repository.Repository, retention *services.RetentionService, authorizer *authz.Authorizer) *RetentionHandler {
+	return &RetentionHandler{
+		repo:       repo,
+		retention:  retention,
+		authorizer: authorizer,
+	}
+}
+
+type RetentionPolicyResponse struct {
+	DocumentID   uuid.UUID `json:"document_id"`
+	KeepAllHours int       `json:"keep_all_hours"`
+	HourlyDays   int       `json:"hourly_days"`
+	Disabled     bool      `json:"disabled"`
+	Overridden   bool      `json:"overridden"` // false ise sunucu varsayılanları kullanılıyor
+}
+
+type UpdateRetentionPolicyRequest struct {
+	KeepAllHours int  `json:"keep_all_hours" binding:"min=0"`
+	HourlyDays   int  `json:"hourly_days" binding:"min=0"`
+	Disabled     bool `json:"disabled"`
+}
+
+type RetentionReportResponse struct {
+	Policy     RetentionPolicyResponse   `json:"policy"`
+	Total      int                       `json:"total"`
+	KeepCount  int                       `json:"keep_count"`
+	PruneCount int                       `json:"prune_count"`
+	Prune      []DocumentVersionResponse `json:"prune"`
+}
+
+func retentionPolicyToResponse(documentID uuid.UUID, policy services.RetentionPolicy, overridden bool) RetentionPolicyResponse {
+	return RetentionPolicyResponse{
+		DocumentID:   documentID,
+		KeepAllHours: int(policy.KeepAll / time.Hour),
+		HourlyDays:   int(policy.HourlyFor / (24 * time.Hour)),
+		Disabled:     policy.Disabled,
+		Overridden:   overridden,
+	}
+}
+
+// authorizeManage, kullanıcının belgenin sahibi veya admin'i olduğunu doğrular.
+// Yetki yoksa yanıtı yazar ve false döner.
+func (h *RetentionHandler) authorizeManage(c *gin.Context) (uuid.UUID, bool) {
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz belge ID'si"})
+		return uuid.Nil, false
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return uuid.Nil, false
+	}
+
+	organizationID, _ := utils.GetOrganizationFromContext(c)
+	var doc models.Document
+	if err := h.repo.Document.InOrganization(organizationID).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return uuid.Nil, false
+	}
+
+	if !authorize(c, h.authorizer, userID, authz.ActionManageRetention, &doc, "Bu belgenin saklama politikasını yönetme izniniz yok") {
+		return uuid.Nil, false
+	}
+	return docID, true
+}
+
+// GetRetentionPolicy godoc
+// @Tags Versions
+// @Summary Get the version retention policy of a document
+// @Description Returns the effective retention policy (document override or server defaults)
+// @Produce json
+// @Param id path string true "Document ID"
+// @Success 200 {object} RetentionPolicyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/retention [get]
+func (h *RetentionHandler) GetRetentionPolicy(c *gin.Context) {
+	docID, ok := h.authorizeManage(c)
+	if !ok {
+		return
+	}
+
+	policy, overridden, err := h.retention.PolicyFor(docID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Saklama politikası alınamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, retentionPolicyToResponse(docID, policy, overridden))
+}
+
+// UpdateRetentionPolicy godoc
+// @Tags Versions
+// @Summary Override the version retention policy of a document
+// @Description Sets a per-document retention policy. Named versions are always kept regardless of the policy.
+// @Accept json
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param policy body UpdateRetentionPolicyRequest true "Retention policy"
+// @Success 200 {object} RetentionPolicyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/retention [put]
+func (h *RetentionHandler) UpdateRetentionPolicy(c *gin.Context) {
+	docID, ok := h.authorizeManage(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := utils.GetUserIDFromContext(c)
+
+	var request UpdateRetentionPolicyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	if request.HourlyDays*24 < request.KeepAllHours {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Saatlik saklama süresi, tümünü saklama süresinden kısa olamaz"})
+		return
+	}
+
+	policy := &models.VersionRetentionPolicy{
+		DocumentID:   docID,
+		KeepAllHours: request.KeepAllHours,
+		HourlyDays:   request.HourlyDays,
+		Disabled:     request.Disabled,
+		UpdatedBy:    userID,
+	}
+	if err := h.repo.Retention.Save(policy); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Saklama politikası kaydedilemedi: " + err.Error()})
+		return
+	}
+
+	effective, overridden, err := h.retention.PolicyFor(docID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Saklama politikası alınamadı: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, retentionPolicyToResponse(docID, effective, overridden))
+}
+
+// DeleteRetentionPolicy godoc
+// @Tags Versions
+// @Summary Remove the document retention override
+// @Description Reverts the document to the server default retention policy
+// @Produce json
+// @Param id path string true "Document ID"
+// @Success 200 {object} RetentionPolicyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/retention [delete]
+func (h *RetentionHandler) DeleteRetentionPolicy(c *gin.Context) {
+	docID, ok := h.authorizeManage(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Retention.DeleteByDocumentID(docID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Saklama politikası silinemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, retentionPolicyToResponse(docID, h.retention.Defaults(), false))
+}
+
+// GetRetentionReport godoc
+// @Tags Versions
+// @Summary Dry-run the retention policy of a document
+// @Description Lists the versions that would be pruned if the retention policy ran now. Nothing is deleted.
+// @Produce json
+// @Param id path string true "Document ID"
+// @Success 200 {object} RetentionReportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/retention/report [get]
+func (h *RetentionHandler) GetRetentionReport(c *gin.Context) {
+	docID, ok := h.authorizeManage(c)
+	if !ok {
+		return
+	}
+
+	plan, err := h.retention.Plan(docID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Saklama raporu oluşturulamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, RetentionReportResponse{
+		Policy:     retentionPolicyToResponse(docID, plan.Policy, plan.Overridden),
+		Total:      len(plan.Keep) + len(plan.Prune),
+		KeepCount:  len(plan.Keep),
+		PruneCount: len(plan.Prune),
+		Prune:      versionsToResponses(plan.Prune),
+	})
+}
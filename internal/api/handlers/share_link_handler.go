@@ -0,0 +1,532 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dione-docs-backend/internal/authz"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/signing"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// shareLinkPasswordHeader, anonim görüntülemede bağlantı parolasının gönderildiği başlıktır.
+	shareLinkPasswordHeader = "X-Share-Password"
+	// shareLinkSessionHeader, anonim görüntüleme oturumu token'ının döndürüldüğü ve geri gönderildiği başlıktır.
+	// Aynı ziyaretçinin bağlantıyı tekrar açması kullanım sayılmaz.
+	shareLinkSessionHeader = "X-Share-Session"
+	shareLinkSessionTTL    = 24 * time.Hour
+)
+
+type ShareLinkHandler struct {
+	repo       *repository.Repository
+	authorizer *authz.Authorizer
+	keys       *signing.KeySet
+}
+
+func NewShareLinkHandler(repo *repository.Repository, authorizer *authz.Authorizer, keys *signing.KeySet) *ShareLinkHandler {
+	return &ShareLinkHandler{
+		repo:       repo,
+		authorizer: authorizer,
+		keys:       keys,
+	}
+}
+
+// documents, isteğin aktif çalışma alanıyla (X-Organization-ID) sınırlandırılmış belge repository'sini döndürür.
+func (h *ShareLinkHandler) documents(c *gin.Context) repository.DocumentRepository {
+	organizationID, _ := utils.GetOrganizationFromContext(c)
+	return h.repo.Document.InOrganization(organizationID)
+}
+
+type CreateShareLinkRequest struct {
+	AccessType string     `json:"access_type" binding:"required"` // "viewer", "commenter" veya "editor"
+	ExpiresAt  *time.Time `json:"expires_at"`
+	Password   string     `json:"password"`
+	MaxUses    int        `json:"max_uses" binding:"min=0"` // 0 ise sınırsız
+}
+
+type RedeemShareLinkRequest struct {
+	Password string `json:"password"`
+}
+
+type ShareLinkResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	DocumentID  uuid.UUID  `json:"document_id"`
+	Token       string     `json:"token"`
+	AccessType  string     `json:"access_type"`
+	HasPassword bool       `json:"has_password"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	MaxUses     int        `json:"max_uses"`
+	UseCount    int        `json:"use_count"`
+	Active      bool       `json:"active"`
+	CreatedBy   uuid.UUID  `json:"created_by"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type RedeemShareLinkResponse struct {
+	DocumentID uuid.UUID `json:"document_id"`
+	AccessType string    `json:"access_type"`
+}
+
+func shareLinkToResponse(link *models.ShareLink) ShareLinkResponse {
+	return ShareLinkResponse{
+		ID:          link.ID,
+		DocumentID:  link.DocumentID,
+		Token:       link.Token,
+		AccessType:  link.AccessType,
+		HasPassword: link.HasPassword(),
+		ExpiresAt:   link.ExpiresAt,
+		MaxUses:     link.MaxUses,
+		UseCount:    link.UseCount,
+		Active:      link.IsActive(time.Now()),
+		CreatedBy:   link.CreatedBy,
+		RevokedAt:   link.RevokedAt,
+		CreatedAt:   link.CreatedAt,
+	}
+}
+
+func generateShareToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// authorizeManage, kullanıcının belgenin sahibi veya admin'i olduğunu doğrular.
+// Yetki yoksa yanıtı yazar ve false döner.
+func (h *ShareLinkHandler) authorizeManage(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz belge ID'si"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	var doc models.Document
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if !authorize(c, h.authorizer, userID, authz.ActionShare, &doc, "Bu belgenin paylaşım bağlantılarını yönetme izniniz yok") {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return docID, userID, true
+}
+
+// activeLink, token'a ait bağlantıyı bulur ve kullanılabilir olduğunu, parola
+// gerekiyorsa doğru olduğunu doğrular. Sorun varsa yanıtı yazar ve nil döner.
+func (h *ShareLinkHandler) activeLink(c *gin.Context, password string) *models.ShareLink {
+	link := h.findLink(c)
+	if link == nil || !h.checkLink(c, link, password, false) {
+		return nil
+	}
+	return link
+}
+
+func (h *ShareLinkHandler) findLink(c *gin.Context) *models.ShareLink {
+	link, err := h.repo.ShareLink.GetByToken(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Paylaşım bağlantısı bulunamadı"})
+		return nil
+	}
+	return link
+}
+
+// checkLink, bağlantının kullanılabilir olduğunu ve parola gerekiyorsa doğru olduğunu doğrular.
+// counted true ise istek bu bağlantı için zaten sayılmış bir ziyarete aittir; kullanım hakkının
+// sonradan tükenmiş olması erişimi engellemez. Sorun varsa yanıtı yazar ve false döner.
+func (h *ShareLinkHandler) checkLink(c *gin.Context, link *models.ShareLink, password string, counted bool) bool {
+	now := time.Now()
+	if !link.IsOpen(now) || (!counted && !link.IsActive(now)) {
+		c.JSON(http.StatusGone, ErrorResponse{Error: "Paylaşım bağlantısı artık geçerli değil"})
+		return false
+	}
+
+	if link.HasPassword() {
+		if password == "" {
+			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Bu bağlantı parola ile korunuyor"})
+			return false
+		}
+		if bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)) != nil {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bağlantı parolası hatalı"})
+			return false
+		}
+	}
+	return true
+}
+
+// viewCounted, istekteki görüntüleme oturumu token'ının bu bağlantı için verilmiş olduğunu döndürür.
+func (h *ShareLinkHandler) viewCounted(c *gin.Context, link *models.ShareLink) bool {
+	session := c.GetHeader(shareLinkSessionHeader)
+	if session == "" {
+		return false
+	}
+	linkID, err := utils.ParseShareViewToken(h.keys, session)
+	return err == nil && linkID == link.ID.String()
+}
+
+// recordUse, bağlantının kullanım sayısını artırır. Bu arada kullanım hakkı
+// tükenmişse yanıtı yazar ve false döner.
+func (h *ShareLinkHandler) recordUse(c *gin.Context, link *models.ShareLink) bool {
+	ok, err := h.repo.ShareLink.RecordUse(link.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Bağlantı kullanımı kaydedilemedi: " + err.Error()})
+		return false
+	}
+	if !ok {
+		c.JSON(http.StatusGone, ErrorResponse{Error: "Paylaşım bağlantısı artık geçerli değil"})
+		return false
+	}
+	return true
+}
+
+// CreateShareLink godoc
+// @Tags Permissions
+// @Summary Create a share link for a document
+// @Description Creates a link that grants viewer, commenter or editor access to anyone who has it. Viewer links can also be opened anonymously.
+// @Accept json
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param link body CreateShareLinkRequest true "Share link options"
+// @Success 201 {object} ShareLinkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/links [post]
+func (h *ShareLinkHandler) CreateShareLink(c *gin.Context) {
+	docID, userID, ok := h.authorizeManage(c)
+	if !ok {
+		return
+	}
+
+	var request CreateShareLinkRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	switch models.AccessType(request.AccessType) {
+	case models.AccessTypeViewer, models.AccessTypeCommenter, models.AccessTypeEditor:
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz erişim tipi. 'viewer', 'commenter' veya 'editor' olmalıdır"})
+		return
+	}
+
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Son kullanma tarihi gelecekte olmalıdır"})
+		return
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Bağlantı oluşturulamadı: " + err.Error()})
+		return
+	}
+
+	link := &models.ShareLink{
+		DocumentID: docID,
+		Token:      token,
+		AccessType: request.AccessType,
+		ExpiresAt:  request.ExpiresAt,
+		MaxUses:    request.MaxUses,
+		CreatedBy:  userID,
+	}
+	if request.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Bağlantı parolası işlenemedi"})
+			return
+		}
+		link.PasswordHash = string(hash)
+	}
+
+	if err := h.repo.ShareLink.Create(link); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Bağlantı oluşturulamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, shareLinkToResponse(link))
+}
+
+// GetShareLinks godoc
+// @Tags Permissions
+// @Summary List share links of a document
+// @Description Lists all share links of the document, including revoked and expired ones
+// @Produce json
+// @Param id path string true "Document ID"
+// @Success 200 {array} ShareLinkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/links [get]
+func (h *ShareLinkHandler) GetShareLinks(c *gin.Context) {
+	docID, _, ok := h.authorizeManage(c)
+	if !ok {
+		return
+	}
+
+	links, err := h.repo.ShareLink.GetByDocumentID(docID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Bağlantılar alınamadı: " + err.Error()})
+		return
+	}
+
+	responses := make([]ShareLinkResponse, len(links))
+	for i := range links {
+		responses[i] = shareLinkToResponse(&links[i])
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// RevokeShareLink godoc
+// @Tags Permissions
+// @Summary Revoke a share link
+// @Description Revokes the link so it can no longer be used and removes the access granted through it.
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param link_id path string true "Share link ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/links/{link_id} [delete]
+func (h *ShareLinkHandler) RevokeShareLink(c *gin.Context) {
+	docID, _, ok := h.authorizeManage(c)
+	if !ok {
+		return
+	}
+
+	linkID, err := uuid.Parse(c.Param("link_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz bağlantı ID'si"})
+		return
+	}
+
+	revoked, err := h.repo.ShareLink.Revoke(docID, linkID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Bağlantı iptal edilemedi: " + err.Error()})
+		return
+	}
+	if revoked == 0 {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Aktif paylaşım bağlantısı bulunamadı"})
+		return
+	}
+	if err := h.repo.Permission.DeleteByShareLink(linkID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Bağlantıyla verilen izinler kaldırılamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Paylaşım bağlantısı iptal edildi."})
+}
+
+// RedeemShareLink godoc
+// @Tags Permissions
+// @Summary Redeem a share link
+// @Description Grants the current user the link's access type on the document. Existing higher access is kept. Granted access expires with the link and is removed when the link is revoked.
+// @Accept json
+// @Produce json
+// @Param token path string true "Share link token"
+// @Param request body RedeemShareLinkRequest false "Link password, if the link is protected"
+// @Success 200 {object} RedeemShareLinkResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/links/{token}/redeem [post]
+func (h *ShareLinkHandler) RedeemShareLink(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	var request RedeemShareLinkRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+			return
+		}
+	}
+
+	link := h.activeLink(c, request.Password)
+	if link == nil {
+		return
+	}
+
+	var doc models.Document
+	if err := h.documents(c).GetByID(link.DocumentID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return
+	}
+	if doc.OwnerID == userID {
+		c.JSON(http.StatusOK, RedeemShareLinkResponse{DocumentID: doc.ID, AccessType: "owner"})
+		return
+	}
+
+	var user models.User
+	if err := h.repo.User.GetByID(userID, &user); err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kullanıcı bulunamadı"})
+		return
+	}
+	allowed, err := sharingAllowed(h.repo, &doc, &user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Paylaşım kısıtlamaları kontrol edilemedi: " + err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Organizasyon, bu belgeye bağlantıyla erişiminize izin vermiyor"})
+		return
+	}
+
+	accepted, err := h.repo.Permission.GetAcceptedByDocumentAndUser(doc.ID, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Yetki kontrolü sırasında bir hata oluştu."})
+		return
+	}
+	if accepted != nil && models.AccessRank(accepted.AccessType) >= models.AccessRank(link.AccessType) {
+		c.JSON(http.StatusOK, RedeemShareLinkResponse{DocumentID: doc.ID, AccessType: accepted.AccessType})
+		return
+	}
+
+	if !h.recordUse(c, link) {
+		return
+	}
+
+	if err := h.grantAccess(doc.ID, userID, link, accepted); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Erişim izni verilemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, RedeemShareLinkResponse{DocumentID: doc.ID, AccessType: link.AccessType})
+}
+
+// grantAccess, kullanıcıya bağlantının erişim tipinde kabul edilmiş bir izin verir. İzin
+// bağlantıyla birlikte sona erer ve bağlantı iptal edildiğinde silinir. Geçerli bir izin
+// yükseltilirken daha erken bir bitiş zamanı varsa korunur; bekleyen davetiye veya süresi
+// dolmuş izin varsa o kayıt kabul edilir.
+func (h *ShareLinkHandler) grantAccess(docID, userID uuid.UUID, link *models.ShareLink, accepted *models.Permission) error {
+	if accepted != nil {
+		expiresAt := link.ExpiresAt
+		if accepted.ExpiresAt != nil && (expiresAt == nil || accepted.ExpiresAt.Before(*expiresAt)) {
+			expiresAt = accepted.ExpiresAt
+		}
+		return h.linkPermission(accepted.ID, link, expiresAt)
+	}
+
+	existing, err := h.repo.Permission.GetByDocumentAndUserWithAnyStatus(docID, userID)
+	if err == nil && (existing.Status == models.PermissionStatusPending || existing.Status == models.PermissionStatusAccepted) {
+		// Süresi dolmuş ancak süpürücünün henüz işaretlemediği izin de burada yakalanır.
+		if err := h.repo.Permission.UpdateStatus(existing.ID, models.PermissionStatusAccepted); err != nil {
+			return err
+		}
+		return h.linkPermission(existing.ID, link, link.ExpiresAt)
+	}
+
+	return h.repo.Permission.Create(&models.Permission{
+		DocumentID:  docID,
+		UserID:      userID,
+		AccessType:  link.AccessType,
+		Status:      models.PermissionStatusAccepted,
+		SharedBy:    link.CreatedBy,
+		ExpiresAt:   link.ExpiresAt,
+		ShareLinkID: &link.ID,
+	})
+}
+
+// linkPermission, kabul edilmiş izne bağlantının erişim tipini ve verilen bitiş zamanını
+// yazar ve izni bağlantıya bağlar.
+func (h *ShareLinkHandler) linkPermission(permissionID uuid.UUID, link *models.ShareLink, expiresAt *time.Time) error {
+	if err := h.repo.Permission.UpdateExpiresAt(permissionID, expiresAt); err != nil {
+		return err
+	}
+	if err := h.repo.Permission.UpdateShareLink(permissionID, &link.ID); err != nil {
+		return err
+	}
+	return h.repo.Permission.UpdateAccessType(permissionID, link.AccessType)
+}
+
+// ViewSharedDocument godoc
+// @Tags Permissions
+// @Summary Open a document anonymously through a viewer link
+// @Description Returns the document for viewer links without authentication. Password-protected links require the X-Share-Password header. The first view counts as one use of the link and returns an X-Share-Session token. Sending it back on later views of the same link does not count another use, even after max_uses is reached.
+// @Produce json
+// @Param token path string true "Share link token"
+// @Param X-Share-Password header string false "Link password"
+// @Param X-Share-Session header string false "View session token returned by an earlier view"
+// @Success 200 {object} DocumentResponse
+// @Header 200 {string} X-Share-Session "View session token for later views of this link"
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/links/{token} [get]
+func (h *ShareLinkHandler) ViewSharedDocument(c *gin.Context) {
+	link := h.findLink(c)
+	if link == nil {
+		return
+	}
+	counted := h.viewCounted(c, link)
+	if !h.checkLink(c, link, c.GetHeader(shareLinkPasswordHeader), counted) {
+		return
+	}
+
+	if link.AccessType != string(models.AccessTypeViewer) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu bağlantı oturum açmayı gerektiriyor"})
+		return
+	}
+
+	var doc models.Document
+	if err := h.documents(c).GetByID(link.DocumentID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return
+	}
+
+	allowed, err := sharingAllowed(h.repo, &doc, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Paylaşım kısıtlamaları kontrol edilemedi: " + err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu bağlantı oturum açmayı gerektiriyor"})
+		return
+	}
+
+	if !counted {
+		if !h.recordUse(c, link) {
+			return
+		}
+		session, err := utils.GenerateShareViewToken(h.keys, link.ID.String(), shareLinkSessionTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Görüntüleme oturumu oluşturulamadı: " + err.Error()})
+			return
+		}
+		c.Header(shareLinkSessionHeader, session)
+	}
+
+	c.JSON(http.StatusOK, documentToResponse(&doc))
+}
@@ -0,0 +1,414 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dione-docs-backend/internal/authz"
+	"github.com/dione-docs-backend/internal/collaboration"
+	"github.com/dione-docs-backend/internal/delta"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Toplu öneri işlemleri.
+const (
+	SuggestionBulkAccept = "accept"
+	SuggestionBulkReject = "reject"
+)
+
+// errSuggestionNotPending, öneri işlenirken başka bir istekle kabul edildiğinde veya reddedildiğinde döner.
+var errSuggestionNotPending = errors.New("öneri artık beklemede değil")
+
+type SuggestionHandler struct {
+	repo       *repository.Repository
+	hubs       *HubManager
+	authorizer *authz.Authorizer
+}
+
+func NewSuggestionHandler(repo *repository.Repository, hubs *HubManager, authorizer *authz.Authorizer) *SuggestionHandler {
+	return &SuggestionHandler{
+		repo:       repo,
+		hubs:       hubs,
+		authorizer: authorizer,
+	}
+}
+
+type SuggestionResponse struct {
+	ID         uuid.UUID               `json:"id"`
+	DocumentID uuid.UUID               `json:"document_id"`
+	AuthorID   uuid.UUID               `json:"author_id"`
+	AuthorName string                  `json:"author_name"`
+	Change     json.RawMessage         `json:"change" swaggertype:"object"`
+	Status     models.SuggestionStatus `json:"status"`
+	ResolvedBy *uuid.UUID              `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time              `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time               `json:"created_at"`
+	UpdatedAt  time.Time               `json:"updated_at"`
+}
+
+type BulkSuggestionRequest struct {
+	Action        string      `json:"action" binding:"required,oneof=accept reject"`
+	SuggestionIDs []uuid.UUID `json:"suggestion_ids"` // boşsa bekleyen tüm öneriler işlenir
+}
+
+type BulkSuggestionResponse struct {
+	Processed []SuggestionResponse `json:"processed"`
+	Failed    []uuid.UUID          `json:"failed"`
+}
+
+// suggestionToResponse, öneriyi yanıta dönüştürür. authors, aynı istekte yazar adlarının
+// tekrar tekrar sorgulanmaması için önbellek olarak kullanılır.
+func (h *SuggestionHandler) suggestionToResponse(suggestion *models.Suggestion, authors map[uuid.UUID]string) SuggestionResponse {
+	name, ok := authors[suggestion.AuthorID]
+	if !ok {
+		var user models.User
+		if err := h.repo.User.GetByID(suggestion.AuthorID, &user); err == nil {
+			name = user.Username
+		}
+		authors[suggestion.AuthorID] = name
+	}
+
+	return SuggestionResponse{
+		ID:         suggestion.ID,
+		DocumentID: suggestion.DocumentID,
+		AuthorID:   suggestion.AuthorID,
+		AuthorName: name,
+		Change:     suggestion.Change,
+		Status:     suggestion.Status,
+		ResolvedBy: suggestion.ResolvedBy,
+		ResolvedAt: suggestion.ResolvedAt,
+		CreatedAt:  suggestion.CreatedAt,
+		UpdatedAt:  suggestion.UpdatedAt,
+	}
+}
+
+// loadDocument, path'teki belgeyi yükler ve kullanıcının action işlemini yapabildiğini doğrular.
+// Sorun varsa yanıtı yazar ve false döner.
+func (h *SuggestionHandler) loadDocument(c *gin.Context, action authz.Action, denied string) (*models.Document, uuid.UUID, bool) {
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz belge ID'si"})
+		return nil, uuid.Nil, false
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return nil, uuid.Nil, false
+	}
+
+	organizationID, _ := utils.GetOrganizationFromContext(c)
+	var doc models.Document
+	if err := h.repo.Document.InOrganization(organizationID).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return nil, uuid.Nil, false
+	}
+
+	if !authorize(c, h.authorizer, userID, action, &doc, denied) {
+		return nil, uuid.Nil, false
+	}
+	return &doc, userID, true
+}
+
+// loadSuggestion, path'teki öneriyi yükler ve belgeye ait olduğunu doğrular.
+// Sorun varsa yanıtı yazar ve nil döner.
+func (h *SuggestionHandler) loadSuggestion(c *gin.Context, doc *models.Document) *models.Suggestion {
+	suggestionID, err := uuid.Parse(c.Param("suggestion_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz öneri ID'si"})
+		return nil
+	}
+
+	var suggestion models.Suggestion
+	if err := h.repo.Suggestion.GetByID(suggestionID, &suggestion); err != nil || suggestion.DocumentID != doc.ID {
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Öneri alınamadı: " + err.Error()})
+			return nil
+		}
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Öneri bulunamadı"})
+		return nil
+	}
+	return &suggestion
+}
+
+// accept, bekleyen öneriyi kabul edilmiş olarak işaretler, değişikliği kayıtlı belge içeriğine
+// uygular ve öneri sahibine atfedilen bir versiyon kaydeder. Belge açıksa bağlı istemciler yeni
+// içeriği yeniden yükler ve kabul olayını alır. Uygulama başarısız olursa öneri yeniden beklemeye alınır.
+func (h *SuggestionHandler) accept(doc *models.Document, suggestion *models.Suggestion, userID uuid.UUID) error {
+	// Hub'ın bellekte yeniden temellendirdiği değişikliği kullan.
+	h.hubs.Flush(doc.ID)
+	if err := h.repo.Suggestion.GetByID(suggestion.ID, suggestion); err != nil {
+		return err
+	}
+
+	resolved, err := h.repo.Suggestion.Resolve(suggestion.ID, models.SuggestionStatusAccepted, userID)
+	if err != nil {
+		return err
+	}
+	if !resolved {
+		return errSuggestionNotPending
+	}
+	now := time.Now()
+	suggestion.Status, suggestion.ResolvedBy, suggestion.ResolvedAt = models.SuggestionStatusAccepted, &userID, &now
+
+	previousContent := doc.Content
+	if err := h.applyToContent(doc, suggestion); err != nil {
+		if reopenErr := h.repo.Suggestion.Reopen(suggestion.ID); reopenErr != nil {
+			log.Printf("Öneri %s yeniden beklemeye alınamadı: %v", suggestion.ID, reopenErr)
+		}
+		suggestion.Status, suggestion.ResolvedBy, suggestion.ResolvedAt = models.SuggestionStatusPending, nil, nil
+		return err
+	}
+
+	h.hubs.UntrackSuggestion(doc.ID, suggestion.ID)
+	if err := h.hubs.RemapContent(doc.ID, previousContent, doc.Content); err != nil {
+		log.Printf("Öneri %s sonrası yorum çapaları ve öneriler güncellenemedi: %v", suggestion.ID, err)
+	}
+	h.hubs.NotifyReload(doc.ID, doc.Content, doc.Version)
+	h.hubs.NotifySuggestion(doc.ID, collaboration.NewSuggestionEvent(collaboration.SuggestionEventAccepted, suggestion))
+	return nil
+}
+
+// applyToContent, öneriyi kayıtlı içeriğe uygular ve belgeyi kaydeder. Versiyon, değişikliğin
+// yazarı olarak öneri sahibine atfedilir.
+func (h *SuggestionHandler) applyToContent(doc *models.Document, suggestion *models.Suggestion) error {
+	current, err := delta.ParseDocument(doc.Content)
+	if err != nil {
+		return err
+	}
+	change, err := delta.Parse(suggestion.Change)
+	if err != nil {
+		return err
+	}
+	content, err := delta.Compose(current, change).Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := applyContentChange(h.repo, doc, content, versionChange{
+		ChangedBy: suggestion.AuthorID,
+		Action:    models.VersionActionSuggestion,
+	}); err != nil {
+		log.Printf("Öneri %s için versiyon kaydedilemedi: %v", suggestion.ID, err)
+	}
+	return h.repo.Document.Update(doc)
+}
+
+// reject, bekleyen öneriyi reddedilmiş olarak işaretler ve hub'ın izlemesinden çıkarır.
+func (h *SuggestionHandler) reject(doc *models.Document, suggestion *models.Suggestion, userID uuid.UUID) error {
+	resolved, err := h.repo.Suggestion.Resolve(suggestion.ID, models.SuggestionStatusRejected, userID)
+	if err != nil {
+		return err
+	}
+	if !resolved {
+		return errSuggestionNotPending
+	}
+	now := time.Now()
+	suggestion.Status, suggestion.ResolvedBy, suggestion.ResolvedAt = models.SuggestionStatusRejected, &userID, &now
+
+	h.hubs.UntrackSuggestion(doc.ID, suggestion.ID)
+	h.hubs.NotifySuggestion(doc.ID, collaboration.NewSuggestionEvent(collaboration.SuggestionEventRejected, suggestion))
+	return nil
+}
+
+// GetSuggestions godoc
+// @Tags Suggestions
+// @Summary List suggestions of a document
+// @Description Returns suggestions in creation order. Pending suggestions are rebased onto the current document, so their change can be rendered as tracked changes directly.
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param status query string false "pending (default), accepted, rejected or all"
+// @Success 200 {array} SuggestionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/suggestions [get]
+func (h *SuggestionHandler) GetSuggestions(c *gin.Context) {
+	doc, _, ok := h.loadDocument(c, authz.ActionView, "Bu belgenin önerilerini görüntüleme izniniz yok")
+	if !ok {
+		return
+	}
+
+	var status models.SuggestionStatus
+	switch query := c.DefaultQuery("status", string(models.SuggestionStatusPending)); query {
+	case "all":
+	case string(models.SuggestionStatusPending), string(models.SuggestionStatusAccepted), string(models.SuggestionStatusRejected):
+		status = models.SuggestionStatus(query)
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz öneri durumu"})
+		return
+	}
+
+	h.hubs.Flush(doc.ID)
+	suggestions, err := h.repo.Suggestion.GetByDocumentID(doc.ID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Öneriler alınamadı: " + err.Error()})
+		return
+	}
+
+	authors := make(map[uuid.UUID]string)
+	response := make([]SuggestionResponse, 0, len(suggestions))
+	for i := range suggestions {
+		response = append(response, h.suggestionToResponse(&suggestions[i], authors))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// AcceptSuggestion godoc
+// @Tags Suggestions
+// @Summary Accept a suggestion
+// @Description Applies the suggestion to the stored document content and records a new version attributed to the suggestion author. Clients that have the document open are told to reload it.
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param suggestion_id path string true "Suggestion ID"
+// @Success 200 {object} SuggestionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/suggestions/{suggestion_id}/accept [post]
+func (h *SuggestionHandler) AcceptSuggestion(c *gin.Context) {
+	doc, userID, ok := h.loadDocument(c, authz.ActionEdit, "Bu belgedeki önerileri kabul etme izniniz yok")
+	if !ok {
+		return
+	}
+	suggestion := h.loadSuggestion(c, doc)
+	if suggestion == nil {
+		return
+	}
+
+	if err := h.accept(doc, suggestion, userID); err != nil {
+		if errors.Is(err, errSuggestionNotPending) {
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "Öneri zaten kabul edilmiş veya reddedilmiş"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Öneri uygulanamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.suggestionToResponse(suggestion, map[uuid.UUID]string{}))
+}
+
+// RejectSuggestion godoc
+// @Tags Suggestions
+// @Summary Reject a suggestion
+// @Description Editors can reject any suggestion; authors can withdraw their own.
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param suggestion_id path string true "Suggestion ID"
+// @Success 200 {object} SuggestionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/suggestions/{suggestion_id}/reject [post]
+func (h *SuggestionHandler) RejectSuggestion(c *gin.Context) {
+	doc, userID, ok := h.loadDocument(c, authz.ActionView, "Bu belgenin önerilerini görüntüleme izniniz yok")
+	if !ok {
+		return
+	}
+	suggestion := h.loadSuggestion(c, doc)
+	if suggestion == nil {
+		return
+	}
+
+	if suggestion.AuthorID != userID &&
+		!authorize(c, h.authorizer, userID, authz.ActionEdit, doc, "Yalnızca kendi önerilerinizi geri çekebilirsiniz") {
+		return
+	}
+
+	if err := h.reject(doc, suggestion, userID); err != nil {
+		if errors.Is(err, errSuggestionNotPending) {
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "Öneri zaten kabul edilmiş veya reddedilmiş"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Öneri reddedilemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.suggestionToResponse(suggestion, map[uuid.UUID]string{}))
+}
+
+// BulkResolveSuggestions godoc
+// @Tags Suggestions
+// @Summary Accept or reject suggestions in bulk
+// @Description Processes the given suggestions in creation order; if suggestion_ids is empty, all pending suggestions are processed. Suggestions that are no longer pending or cannot be applied are reported in failed.
+// @Accept json
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param request body BulkSuggestionRequest true "Bulk action"
+// @Success 200 {object} BulkSuggestionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/suggestions/bulk [post]
+func (h *SuggestionHandler) BulkResolveSuggestions(c *gin.Context) {
+	doc, userID, ok := h.loadDocument(c, authz.ActionEdit, "Bu belgedeki önerileri yönetme izniniz yok")
+	if !ok {
+		return
+	}
+
+	var request BulkSuggestionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	pending, err := h.repo.Suggestion.GetByDocumentID(doc.ID, models.SuggestionStatusPending)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Öneriler alınamadı: " + err.Error()})
+		return
+	}
+
+	response := BulkSuggestionResponse{Processed: []SuggestionResponse{}, Failed: []uuid.UUID{}}
+	selected := make(map[uuid.UUID]bool, len(request.SuggestionIDs))
+	for _, id := range request.SuggestionIDs {
+		selected[id] = true
+	}
+
+	authors := make(map[uuid.UUID]string)
+	for i := range pending {
+		suggestion := &pending[i]
+		if len(selected) > 0 {
+			if !selected[suggestion.ID] {
+				continue
+			}
+			delete(selected, suggestion.ID)
+		}
+
+		if request.Action == SuggestionBulkAccept {
+			// accept, önceki kabullerle yeniden temellendirilmiş güncel değişikliği okur.
+			err = h.accept(doc, suggestion, userID)
+		} else {
+			err = h.reject(doc, suggestion, userID)
+		}
+		if err != nil {
+			log.Printf("BulkResolveSuggestions - öneri %s işlenemedi: %v", suggestion.ID, err)
+			response.Failed = append(response.Failed, suggestion.ID)
+			continue
+		}
+		response.Processed = append(response.Processed, h.suggestionToResponse(suggestion, authors))
+	}
+	// Bekleyenler arasında bulunmayan ID'ler (başka belgeye ait, zaten çözülmüş vb.) işlenemez.
+	for id := range selected {
+		response.Failed = append(response.Failed, id)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
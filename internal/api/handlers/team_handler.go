@@ -0,0 +1,721 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dione-docs-backend/internal/authz"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TeamHandler struct {
+	repo       *repository.Repository
+	authorizer *authz.Authorizer
+}
+
+func NewTeamHandler(repo *repository.Repository, authorizer *authz.Authorizer) *TeamHandler {
+	return &TeamHandler{
+		repo:       repo,
+		authorizer: authorizer,
+	}
+}
+
+// documents, isteğin aktif çalışma alanıyla (X-Organization-ID) sınırlandırılmış belge repository'sini döndürür.
+func (h *TeamHandler) documents(c *gin.Context) repository.DocumentRepository {
+	organizationID, _ := utils.GetOrganizationFromContext(c)
+	return h.repo.Document.InOrganization(organizationID)
+}
+
+type TeamRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+}
+
+type AddTeamMemberRequest struct {
+	UserEmail string `json:"user_email" binding:"required,email"`
+	Role      string `json:"role"` // "admin" veya "member" (varsayılan)
+}
+
+type UpdateTeamMemberRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type ShareWithTeamRequest struct {
+	TeamID     uuid.UUID `json:"team_id" binding:"required"`
+	AccessType string    `json:"access_type" binding:"required"` // "viewer", "commenter" veya "editor" olmalı
+}
+
+type TeamMemberResponse struct {
+	UserID    uuid.UUID       `json:"user_id"`
+	Username  string          `json:"username"`
+	Email     string          `json:"email"`
+	Role      models.TeamRole `json:"role"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type TeamResponse struct {
+	ID          uuid.UUID            `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	OwnerID     uuid.UUID            `json:"owner_id"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+	Members     []TeamMemberResponse `json:"members,omitempty"`
+}
+
+type TeamPermissionResponse struct {
+	TeamID     uuid.UUID `json:"team_id"`
+	TeamName   string    `json:"team_name"`
+	DocumentID uuid.UUID `json:"document_id"`
+	AccessType string    `json:"access_type"`
+	SharedBy   uuid.UUID `json:"shared_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func teamToResponse(team *models.Team) TeamResponse {
+	return TeamResponse{
+		ID:          team.ID,
+		Name:        team.Name,
+		Description: team.Description,
+		OwnerID:     team.OwnerID,
+		CreatedAt:   team.CreatedAt,
+		UpdatedAt:   team.UpdatedAt,
+	}
+}
+
+func validTeamRole(role string) bool {
+	return role == string(models.TeamRoleAdmin) || role == string(models.TeamRoleMember)
+}
+
+// loadTeam, path'teki takımı ve isteği yapan kullanıcının üyeliğini yükler. requireAdmin
+// true ise kullanıcının takım admin'i olması gerekir. Sorun varsa yanıtı yazar ve false döner.
+func (h *TeamHandler) loadTeam(c *gin.Context, requireAdmin bool) (*models.Team, *models.TeamMember, bool) {
+	teamID, err := uuid.Parse(c.Param("team_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz takım ID'si"})
+		return nil, nil, false
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return nil, nil, false
+	}
+
+	var team models.Team
+	if err := h.repo.Team.GetByID(teamID, &team); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Takım bulunamadı"})
+		return nil, nil, false
+	}
+
+	member, err := h.repo.Team.GetMember(teamID, userID)
+	if err != nil {
+		// Üye olmayanlar için takımın varlığı da gizlenir.
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Takım bulunamadı"})
+		return nil, nil, false
+	}
+
+	if requireAdmin && member.Role != models.TeamRoleAdmin {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu işlem için takım yöneticisi olmalısınız"})
+		return nil, nil, false
+	}
+	return &team, member, true
+}
+
+func (h *TeamHandler) membersToResponses(members []models.TeamMember) []TeamMemberResponse {
+	responses := make([]TeamMemberResponse, 0, len(members))
+	for _, member := range members {
+		response := TeamMemberResponse{
+			UserID:    member.UserID,
+			Role:      member.Role,
+			CreatedAt: member.CreatedAt,
+		}
+		var user models.User
+		if err := h.repo.User.GetByID(member.UserID, &user); err == nil {
+			response.Username = user.Username
+			response.Email = user.Email
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+// CreateTeam godoc
+// @Tags Teams
+// @Summary Create a team
+// @Description Creates a team. The creator becomes its owner and an admin member.
+// @Accept json
+// @Produce json
+// @Param team body TeamRequest true "Team data"
+// @Success 201 {object} TeamResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/teams [post]
+func (h *TeamHandler) CreateTeam(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	var request TeamRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	team := &models.Team{
+		Name:        request.Name,
+		Description: request.Description,
+		OwnerID:     userID,
+	}
+	if err := h.repo.Team.Create(team); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Takım oluşturulamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, teamToResponse(team))
+}
+
+// GetUserTeams godoc
+// @Tags Teams
+// @Summary List teams of the current user
+// @Produce json
+// @Success 200 {array} TeamResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/teams [get]
+func (h *TeamHandler) GetUserTeams(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	teams, err := h.repo.Team.GetByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Takımlar alınamadı: " + err.Error()})
+		return
+	}
+
+	responses := make([]TeamResponse, len(teams))
+	for i := range teams {
+		responses[i] = teamToResponse(&teams[i])
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// GetTeam godoc
+// @Tags Teams
+// @Summary Get a team with its members
+// @Produce json
+// @Param team_id path string true "Team ID"
+// @Success 200 {object} TeamResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/teams/{team_id} [get]
+func (h *TeamHandler) GetTeam(c *gin.Context) {
+	team, _, ok := h.loadTeam(c, false)
+	if !ok {
+		return
+	}
+
+	members, err := h.repo.Team.GetMembers(team.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Takım üyeleri alınamadı: " + err.Error()})
+		return
+	}
+
+	response := teamToResponse(team)
+	response.Members = h.membersToResponses(members)
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateTeam godoc
+// @Tags Teams
+// @Summary Update a team
+// @Accept json
+// @Produce json
+// @Param team_id path string true "Team ID"
+// @Param team body TeamRequest true "Team data"
+// @Success 200 {object} TeamResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/teams/{team_id} [put]
+func (h *TeamHandler) UpdateTeam(c *gin.Context) {
+	team, _, ok := h.loadTeam(c, true)
+	if !ok {
+		return
+	}
+
+	var request TeamRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	team.Name = request.Name
+	team.Description = request.Description
+	if err := h.repo.Team.Update(team); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Takım güncellenemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, teamToResponse(team))
+}
+
+// DeleteTeam godoc
+// @Tags Teams
+// @Summary Delete a team
+// @Description Deletes the team, its memberships and all document shares made to it. Only the team owner can delete it.
+// @Produce json
+// @Param team_id path string true "Team ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/teams/{team_id} [delete]
+func (h *TeamHandler) DeleteTeam(c *gin.Context) {
+	team, member, ok := h.loadTeam(c, true)
+	if !ok {
+		return
+	}
+
+	if team.OwnerID != member.UserID {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Takımı yalnızca sahibi silebilir"})
+		return
+	}
+
+	if err := h.repo.Team.Delete(team.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Takım silinemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Takım silindi."})
+}
+
+// AddTeamMember godoc
+// @Tags Teams
+// @Summary Add a member to a team
+// @Description Adds a user to the team. Fails with 403 if the team has access to a document whose organization restricts sharing and the user is not allowed to receive it.
+// @Accept json
+// @Produce json
+// @Param team_id path string true "Team ID"
+// @Param member body AddTeamMemberRequest true "Member data (role: 'admin' or 'member')"
+// @Success 201 {object} TeamMemberResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/teams/{team_id}/members [post]
+func (h *TeamHandler) AddTeamMember(c *gin.Context) {
+	team, member, ok := h.loadTeam(c, true)
+	if !ok {
+		return
+	}
+
+	var request AddTeamMemberRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+	if request.Role == "" {
+		request.Role = string(models.TeamRoleMember)
+	}
+	if !validTeamRole(request.Role) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz rol. 'admin' veya 'member' olmalıdır"})
+		return
+	}
+
+	user, err := h.repo.User.GetByEmail(request.UserEmail)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Eklenecek kullanıcı bulunamadı"})
+		return
+	}
+
+	if _, err := h.repo.Team.GetMember(team.ID, user.ID); err == nil {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Kullanıcı zaten takımın üyesi"})
+		return
+	}
+
+	if !h.memberSharingAllowed(c, team.ID, user) {
+		return
+	}
+
+	newMember := &models.TeamMember{
+		TeamID:  team.ID,
+		UserID:  user.ID,
+		Role:    models.TeamRole(request.Role),
+		AddedBy: member.UserID,
+	}
+	if err := h.repo.Team.AddMember(newMember); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Üye eklenemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, TeamMemberResponse{
+		UserID:    user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      newMember.Role,
+		CreatedAt: newMember.CreatedAt,
+	})
+}
+
+// UpdateTeamMember godoc
+// @Tags Teams
+// @Summary Change the role of a team member
+// @Accept json
+// @Produce json
+// @Param team_id path string true "Team ID"
+// @Param user_id path string true "User ID"
+// @Param member body UpdateTeamMemberRequest true "New role ('admin' or 'member')"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/teams/{team_id}/members/{user_id} [put]
+func (h *TeamHandler) UpdateTeamMember(c *gin.Context) {
+	team, _, ok := h.loadTeam(c, true)
+	if !ok {
+		return
+	}
+
+	targetID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz kullanıcı ID'si"})
+		return
+	}
+
+	var request UpdateTeamMemberRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+	if !validTeamRole(request.Role) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz rol. 'admin' veya 'member' olmalıdır"})
+		return
+	}
+
+	if targetID == team.OwnerID {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Takım sahibinin rolü değiştirilemez"})
+		return
+	}
+
+	if _, err := h.repo.Team.GetMember(team.ID, targetID); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Takım üyesi bulunamadı"})
+		return
+	}
+
+	if err := h.repo.Team.UpdateMemberRole(team.ID, targetID, models.TeamRole(request.Role)); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Üye rolü güncellenemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Üye rolü güncellendi."})
+}
+
+// RemoveTeamMember godoc
+// @Tags Teams
+// @Summary Remove a member from a team
+// @Description Team admins can remove members; any member can remove themselves. The owner cannot be removed.
+// @Produce json
+// @Param team_id path string true "Team ID"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/teams/{team_id}/members/{user_id} [delete]
+func (h *TeamHandler) RemoveTeamMember(c *gin.Context) {
+	team, member, ok := h.loadTeam(c, false)
+	if !ok {
+		return
+	}
+
+	targetID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz kullanıcı ID'si"})
+		return
+	}
+
+	if targetID != member.UserID && member.Role != models.TeamRoleAdmin {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Bu işlem için takım yöneticisi olmalısınız"})
+		return
+	}
+
+	if targetID == team.OwnerID {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Takım sahibi takımdan çıkarılamaz"})
+		return
+	}
+
+	if _, err := h.repo.Team.GetMember(team.ID, targetID); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Takım üyesi bulunamadı"})
+		return
+	}
+
+	if err := h.repo.Team.RemoveMember(team.ID, targetID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Üye çıkarılamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Üye takımdan çıkarıldı."})
+}
+
+// authorizeDocumentShare, kullanıcının belgenin sahibi veya admin'i olduğunu doğrular.
+// Yetki yoksa yanıtı yazar ve false döner.
+func (h *TeamHandler) authorizeDocumentShare(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz belge ID'si"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	var doc models.Document
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if !authorize(c, h.authorizer, userID, authz.ActionShare, &doc, "Bu belgeyi paylaşma izniniz yok") {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return docID, userID, true
+}
+
+// teamSharingAllowed, belgenin organizasyonu paylaşımı kısıtlıyorsa takımın tüm üyelerinin
+// paylaşıma uygun olduğunu doğrular. Uygun değilse yanıtı yazar ve false döner.
+func (h *TeamHandler) teamSharingAllowed(c *gin.Context, docID, teamID uuid.UUID) bool {
+	var doc models.Document
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return false
+	}
+	if doc.OrganizationID == nil {
+		return true
+	}
+
+	members, err := h.repo.Team.GetMembers(teamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Takım üyeleri alınamadı: " + err.Error()})
+		return false
+	}
+	for _, member := range members {
+		var user models.User
+		if err := h.repo.User.GetByID(member.UserID, &user); err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Takım üyesi alınamadı: " + err.Error()})
+			return false
+		}
+		allowed, err := sharingAllowed(h.repo, &doc, &user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Paylaşım kısıtlamaları kontrol edilemedi: " + err.Error()})
+			return false
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Organizasyon, belgenin bu takımın tüm üyeleriyle paylaşılmasına izin vermiyor"})
+			return false
+		}
+	}
+	return true
+}
+
+// memberSharingAllowed, takımla paylaşılmış ve organizasyonu paylaşımı kısıtlayan her belgenin
+// yeni üyeyle de paylaşılabileceğini doğrular. Uygun değilse yanıtı yazar ve false döner.
+func (h *TeamHandler) memberSharingAllowed(c *gin.Context, teamID uuid.UUID, user *models.User) bool {
+	docs, err := h.repo.Team.GetRestrictedDocuments(teamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Takımın belgeleri alınamadı: " + err.Error()})
+		return false
+	}
+	for i := range docs {
+		allowed, err := sharingAllowed(h.repo, &docs[i], user)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Paylaşım kısıtlamaları kontrol edilemedi: " + err.Error()})
+			return false
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Takım, organizasyonu dış paylaşıma izin vermeyen belgelere erişebiliyor; bu kullanıcı takıma eklenemez"})
+			return false
+		}
+	}
+	return true
+}
+
+// ShareDocumentWithTeam godoc
+// @Tags Permissions
+// @Summary Share a document with a team
+// @Description Grants every member of the team the given access type. Sharing again with the same team updates the access type. The sharer must be a member of the team.
+// @Accept json
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param share body ShareWithTeamRequest true "Team share request (access_type: 'viewer', 'commenter' or 'editor')"
+// @Success 200 {object} TeamPermissionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/permissions/teams [post]
+func (h *TeamHandler) ShareDocumentWithTeam(c *gin.Context) {
+	docID, userID, ok := h.authorizeDocumentShare(c)
+	if !ok {
+		return
+	}
+
+	var request ShareWithTeamRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	switch models.AccessType(request.AccessType) {
+	case models.AccessTypeViewer, models.AccessTypeCommenter, models.AccessTypeEditor:
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz erişim tipi. 'viewer', 'commenter' veya 'editor' olmalıdır"})
+		return
+	}
+
+	var team models.Team
+	if err := h.repo.Team.GetByID(request.TeamID, &team); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Takım bulunamadı"})
+		return
+	}
+	if _, err := h.repo.Team.GetMember(team.ID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "Takım bulunamadı"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Takım üyeliği kontrol edilemedi: " + err.Error()})
+		return
+	}
+
+	if !h.teamSharingAllowed(c, docID, team.ID) {
+		return
+	}
+
+	permission := &models.TeamPermission{
+		DocumentID: docID,
+		TeamID:     team.ID,
+		AccessType: request.AccessType,
+		SharedBy:   userID,
+	}
+	if err := h.repo.Team.SaveDocumentPermission(permission); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Belge takımla paylaşılamadı: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, TeamPermissionResponse{
+		TeamID:     team.ID,
+		TeamName:   team.Name,
+		DocumentID: docID,
+		AccessType: permission.AccessType,
+		SharedBy:   permission.SharedBy,
+		CreatedAt:  permission.CreatedAt,
+	})
+}
+
+// GetDocumentTeamPermissions godoc
+// @Tags Permissions
+// @Summary List teams a document is shared with
+// @Produce json
+// @Param id path string true "Document ID"
+// @Success 200 {array} TeamPermissionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/permissions/teams [get]
+func (h *TeamHandler) GetDocumentTeamPermissions(c *gin.Context) {
+	docID, _, ok := h.authorizeDocumentShare(c)
+	if !ok {
+		return
+	}
+
+	permissions, err := h.repo.Team.GetDocumentPermissions(docID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Takım izinleri alınamadı: " + err.Error()})
+		return
+	}
+
+	responses := make([]TeamPermissionResponse, 0, len(permissions))
+	for _, permission := range permissions {
+		response := TeamPermissionResponse{
+			TeamID:     permission.TeamID,
+			DocumentID: permission.DocumentID,
+			AccessType: permission.AccessType,
+			SharedBy:   permission.SharedBy,
+			CreatedAt:  permission.CreatedAt,
+		}
+		var team models.Team
+		if err := h.repo.Team.GetByID(permission.TeamID, &team); err == nil {
+			response.TeamName = team.Name
+		}
+		responses = append(responses, response)
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// RemoveDocumentTeamPermission godoc
+// @Tags Permissions
+// @Summary Stop sharing a document with a team
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param team_id path string true "Team ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/permissions/teams/{team_id} [delete]
+func (h *TeamHandler) RemoveDocumentTeamPermission(c *gin.Context) {
+	docID, _, ok := h.authorizeDocumentShare(c)
+	if !ok {
+		return
+	}
+
+	teamID, err := uuid.Parse(c.Param("team_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz takım ID'si"})
+		return
+	}
+
+	deleted, err := h.repo.Team.DeleteDocumentPermission(docID, teamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Takım izni kaldırılamadı: " + err.Error()})
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bu takımla paylaşılmamış"})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Takım izni kaldırıldı."})
+}
@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dione-docs-backend/internal/authz"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TransferHandler struct {
+	repo       *repository.Repository
+	authorizer *authz.Authorizer
+}
+
+func NewTransferHandler(repo *repository.Repository, authorizer *authz.Authorizer) *TransferHandler {
+	return &TransferHandler{
+		repo:       repo,
+		authorizer: authorizer,
+	}
+}
+
+// documents, isteğin aktif çalışma alanıyla (X-Organization-ID) sınırlandırılmış belge repository'sini döndürür.
+func (h *TransferHandler) documents(c *gin.Context) repository.DocumentRepository {
+	organizationID, _ := utils.GetOrganizationFromContext(c)
+	return h.repo.Document.InOrganization(organizationID)
+}
+
+type TransferOwnershipRequest struct {
+	UserEmail string `json:"user_email" binding:"required,email"`
+}
+
+type OwnershipTransferResponse struct {
+	ID            uuid.UUID             `json:"id"`
+	DocumentID    uuid.UUID             `json:"document_id"`
+	DocumentTitle string                `json:"document_title"`
+	FromUserID    uuid.UUID             `json:"from_user_id"`
+	FromEmail     string                `json:"from_email"`
+	ToUserID      uuid.UUID             `json:"to_user_id"`
+	ToEmail       string                `json:"to_email"`
+	Status        models.TransferStatus `json:"status"`
+	RespondedAt   *time.Time            `json:"responded_at,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+}
+
+func (h *TransferHandler) transferToResponse(c *gin.Context, transfer *models.OwnershipTransfer) OwnershipTransferResponse {
+	response := OwnershipTransferResponse{
+		ID:          transfer.ID,
+		DocumentID:  transfer.DocumentID,
+		FromUserID:  transfer.FromUserID,
+		ToUserID:    transfer.ToUserID,
+		Status:      transfer.Status,
+		RespondedAt: transfer.RespondedAt,
+		CreatedAt:   transfer.CreatedAt,
+	}
+
+	var doc models.Document
+	if err := h.documents(c).GetByID(transfer.DocumentID, &doc); err == nil {
+		response.DocumentTitle = doc.Title
+	}
+	var user models.User
+	if err := h.repo.User.GetByID(transfer.FromUserID, &user); err == nil {
+		response.FromEmail = user.Email
+	}
+	user = models.User{}
+	if err := h.repo.User.GetByID(transfer.ToUserID, &user); err == nil {
+		response.ToEmail = user.Email
+	}
+	return response
+}
+
+// notify, devirle ilgili bir bildirimi kullanıcıya iletir. Bildirim kaydedilemezse yalnızca loglanır.
+func (h *TransferHandler) notify(userID uuid.UUID, notificationType models.NotificationType, doc *models.Document, actorID uuid.UUID, message string) {
+	if err := h.repo.Notification.Create(&models.Notification{
+		UserID:     userID,
+		Type:       notificationType,
+		DocumentID: &doc.ID,
+		ActorID:    &actorID,
+		Message:    message,
+	}); err != nil {
+		log.Printf("Devir bildirimi kaydedilemedi (belge %s, kullanıcı %s): %v", doc.ID, userID, err)
+	}
+}
+
+// loadTransfer, path'teki devir isteğini ve belgesini yükler; isteği yapan kullanıcının alıcı
+// olduğunu ve belgenin aktif çalışma alanında bulunduğunu doğrular. Sorun varsa yanıtı yazar ve false döner.
+func (h *TransferHandler) loadTransfer(c *gin.Context) (*models.OwnershipTransfer, *models.Document, uuid.UUID, bool) {
+	transferID, err := uuid.Parse(c.Param("transfer_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz devir isteği ID'si"})
+		return nil, nil, uuid.Nil, false
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return nil, nil, uuid.Nil, false
+	}
+
+	var transfer models.OwnershipTransfer
+	if err := h.repo.Transfer.GetByID(transferID, &transfer); err != nil || transfer.ToUserID != userID {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Devir isteği bulunamadı"})
+		return nil, nil, uuid.Nil, false
+	}
+
+	var doc models.Document
+	if err := h.documents(c).GetByID(transfer.DocumentID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Devir isteği bulunamadı"})
+		return nil, nil, uuid.Nil, false
+	}
+
+	if transfer.Status != models.TransferStatusPending {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Bu devir isteği zaten yanıtlanmış veya iptal edilmiş"})
+		return nil, nil, uuid.Nil, false
+	}
+	return &transfer, &doc, userID, true
+}
+
+// TransferOwnership godoc
+// @Tags Ownership
+// @Summary Request an ownership transfer
+// @Description The owner offers ownership of the document to an existing collaborator. Ownership changes only after the recipient accepts; the previous owner then becomes an admin of the document.
+// @Accept json
+// @Produce json
+// @Param id path string true "Document ID"
+// @Param request body TransferOwnershipRequest true "Recipient"
+// @Success 201 {object} OwnershipTransferResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/transfer [post]
+func (h *TransferHandler) TransferOwnership(c *gin.Context) {
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz belge ID'si"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	var doc models.Document
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return
+	}
+
+	if !authorize(c, h.authorizer, userID, authz.ActionTransfer, &doc, "Yalnızca belge sahibi sahipliği devredebilir") {
+		return
+	}
+
+	var request TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz istek formatı: " + err.Error()})
+		return
+	}
+
+	recipient, err := h.repo.User.GetByEmail(request.UserEmail)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Devredilecek kullanıcı bulunamadı"})
+		return
+	}
+	if recipient.ID == userID {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Belge zaten size ait"})
+		return
+	}
+
+	accessType, err := h.repo.Permission.GetEffectiveAccessType(doc.ID, recipient.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Kullanıcının erişimi kontrol edilemedi: " + err.Error()})
+		return
+	}
+	if accessType == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Sahiplik yalnızca belgeye erişimi olan bir kullanıcıya devredilebilir"})
+		return
+	}
+
+	if _, err := h.repo.Transfer.GetPendingByDocument(doc.ID); err == nil {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Bu belge için zaten bekleyen bir devir isteği var"})
+		return
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Devir istekleri kontrol edilemedi: " + err.Error()})
+		return
+	}
+
+	transfer := &models.OwnershipTransfer{
+		DocumentID: doc.ID,
+		FromUserID: userID,
+		ToUserID:   recipient.ID,
+		Status:     models.TransferStatusPending,
+	}
+	if err := h.repo.Transfer.Create(transfer); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Devir isteği oluşturulamadı: " + err.Error()})
+		return
+	}
+
+	h.notify(recipient.ID, models.NotificationTypeTransferRequested, &doc, userID,
+		fmt.Sprintf("\"%s\" belgesinin sahipliği size devredilmek isteniyor.", doc.Title))
+
+	c.JSON(http.StatusCreated, h.transferToResponse(c, transfer))
+}
+
+// CancelOwnershipTransfer godoc
+// @Tags Ownership
+// @Summary Cancel the pending ownership transfer of a document
+// @Produce json
+// @Param id path string true "Document ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/documents/{id}/transfer [delete]
+func (h *TransferHandler) CancelOwnershipTransfer(c *gin.Context) {
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Geçersiz belge ID'si"})
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	var doc models.Document
+	if err := h.documents(c).GetByID(docID, &doc); err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Belge bulunamadı"})
+		return
+	}
+
+	if !authorize(c, h.authorizer, userID, authz.ActionTransfer, &doc, "Yalnızca belge sahibi devir isteğini iptal edebilir") {
+		return
+	}
+
+	transfer, err := h.repo.Transfer.GetPendingByDocument(doc.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Bekleyen bir devir isteği bulunamadı"})
+		return
+	}
+
+	if _, err := h.repo.Transfer.Resolve(transfer.ID, models.TransferStatusCancelled); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Devir isteği iptal edilemedi: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Devir isteği iptal edildi."})
+}
+
+// GetPendingTransfers godoc
+// @Tags Ownership
+// @Summary List ownership transfers waiting for the current user
+// @Produce json
+// @Success 200 {array} OwnershipTransferResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/transfers/pending [get]
+func (h *TransferHandler) GetPendingTransfers(c *gin.Context) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Kimlik doğrulama hatası"})
+		return
+	}
+
+	organizationID, _ := utils.GetOrganizationFromContext(c)
+	transfers, err := h.repo.Transfer.InOrganization(organizationID).GetPendingByRecipient(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Bekleyen devir istekleri alınamadı: " + err.Error()})
+		return
+	}
+
+	responses := make([]OwnershipTransferResponse, 0, len(transfers))
+	for i := range transfers {
+		responses = append(responses, h.transferToResponse(c, &transfers[i]))
+	}
+	c.JSON(http.StatusOK, responses)
+}
+
+// AcceptTransfer godoc
+// @Tags Ownership
+// @Summary Accept an ownership transfer
+// @Description Makes the current user the owner of the document. The previous owner keeps admin access.
+// @Produce json
+// @Param transfer_id path string true "Transfer ID"
+// @Success 200 {object} OwnershipTransferResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/transfers/{transfer_id}/accept [post]
+func (h *TransferHandler) AcceptTransfer(c *gin.Context) {
+	transfer, doc, userID, ok := h.loadTransfer(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Transfer.Accept(transfer); err != nil {
+		if errors.Is(err, repository.ErrTransferNotPending) {
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "Devir isteği artık geçerli değil"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Sahiplik devredilemedi: " + err.Error()})
+		return
+	}
+	now := time.Now()
+	transfer.Status, transfer.RespondedAt = models.TransferStatusAccepted, &now
+
+	h.notify(transfer.FromUserID, models.NotificationTypeTransferAccepted, doc, userID,
+		fmt.Sprintf("\"%s\" belgesinin sahipliği devredildi; artık belgede yöneticisiniz.", doc.Title))
+
+	c.JSON(http.StatusOK, h.transferToResponse(c, transfer))
+}
+
+// RejectTransfer godoc
+// @Tags Ownership
+// @Summary Reject an ownership transfer
+// @Produce json
+// @Param transfer_id path string true "Transfer ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/transfers/{transfer_id}/reject [post]
+func (h *TransferHandler) RejectTransfer(c *gin.Context) {
+	transfer, doc, userID, ok := h.loadTransfer(c)
+	if !ok {
+		return
+	}
+
+	resolved, err := h.repo.Transfer.Resolve(transfer.ID, models.TransferStatusRejected)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Devir isteği reddedilemedi: " + err.Error()})
+		return
+	}
+	if !resolved {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Devir isteği artık geçerli değil"})
+		return
+	}
+
+	h.notify(transfer.FromUserID, models.NotificationTypeTransferRejected, doc, userID,
+		fmt.Sprintf("\"%s\" belgesinin sahiplik devri reddedildi.", doc.Title))
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Devir isteği reddedildi."})
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/dione-docs-backend/internal/delta"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// versionChange, bir içerik değişikliğinde oluşturulacak versiyon kaydının bilgilerini taşır.
+type versionChange struct {
+	ChangedBy    uuid.UUID
+	Action       models.VersionAction
+	RestoredFrom *int
+	FromHub      bool // içerik canlı oturumun operasyonlarından oluştu; yazarları hub kaydeder
+}
+
+// applyContentChange, belgenin mevcut içeriğini bir DocumentVersion olarak kaydeder,
+// ardından yeni içeriği belgeye uygular ve versiyon numarasını artırır. Hub dışından gelen
+// değişiklikler, yazar kaydına ChangedBy kullanıcısının operasyonu olarak eklenir.
+// Belgenin kendisi burada kaydedilmez; çağıran taraf Document.Update çağırmalıdır.
+// Versiyon kaydedilemese bile içerik uygulanır ve hata döndürülür; değişikliğin
+// versiyonsuz kaydedilip kaydedilmeyeceğine çağıran taraf karar verir.
+func applyContentChange(repo *repository.Repository, doc *models.Document, content []byte, change versionChange) error {
+	action := change.Action
+	if action == "" {
+		action = models.VersionActionEdit
+	}
+
+	version := &models.DocumentVersion{
+		DocumentID:          doc.ID,
+		Version:             doc.Version,
+		Content:             doc.Content,
+		ChangedBy:           change.ChangedBy,
+		Action:              action,
+		RestoredFromVersion: change.RestoredFrom,
+	}
+	err := repo.Document.SaveVersion(version)
+
+	if !change.FromHub {
+		if recordErr := recordContentChange(repo.Attribution, doc, content, change.ChangedBy); recordErr != nil {
+			log.Printf("Belge %s için yazar kaydı oluşturulamadı: %v", doc.ID, recordErr)
+		}
+	}
+
+	doc.Version++
+	doc.Content = content
+	return err
+}
+
+// recordContentChange, belgenin içeriğini content'e dönüştüren farkı author'un operasyonu
+// olarak kaydeder. Operasyon kaydı boşsa önce belgenin mevcut içeriği temel kayıt olarak yazılır.
+func recordContentChange(attributions repository.AttributionRepository, doc *models.Document, content []byte, author uuid.UUID) error {
+	current, err := delta.ParseDocument(doc.Content)
+	if err != nil {
+		return err
+	}
+	next, err := delta.ParseDocument(content)
+	if err != nil {
+		return err
+	}
+	change, err := delta.Diff(current, next)
+	if err != nil {
+		return err
+	}
+	changeData, err := change.Marshal()
+	if err != nil {
+		return err
+	}
+	baseData, err := current.Marshal()
+	if err != nil {
+		return err
+	}
+
+	baseAuthor := uuid.Nil
+	if doc.Version == 1 {
+		baseAuthor = doc.OwnerID
+	}
+	baseline := &models.DocumentOperation{
+		DocumentID: doc.ID,
+		UserID:     baseAuthor,
+		Version:    doc.Version,
+		Change:     baseData,
+	}
+	return attributions.RecordOperations(baseline, []models.DocumentOperation{{
+		DocumentID: doc.ID,
+		UserID:     author,
+		Version:    doc.Version + 1,
+		Change:     changeData,
+	}})
+}
+
+// headVersion, belgenin güncel içeriğini versiyon olarak döndürür. Güncel versiyonun içeriği
+// normalde bir sonraki değişiklikte saklanır; isimlendirilirken bu kayıt önceden yazılır ve
+// sonraki değişiklik aynı kaydı kullanır (bkz. DocumentRepository.SaveVersion).
+func headVersion(doc *models.Document) *models.DocumentVersion {
+	return &models.DocumentVersion{
+		DocumentID: doc.ID,
+		Version:    doc.Version,
+		Content:    doc.Content,
+		CreatedAt:  doc.UpdatedAt,
+	}
+}
@@ -11,9 +11,9 @@ func CORSMiddleware() gin.HandlerFunc {
 	return cors.New(cors.Config{
 		// Allow all origins during development; replace with specific domains in production
 		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Organization-ID", "X-Share-Password", "X-Share-Session"},
+		ExposeHeaders:    []string{"Content-Length", "X-Share-Session"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	})
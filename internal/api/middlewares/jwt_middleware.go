@@ -1,21 +1,17 @@
 package middleware
 
 import (
-	"errors"
 	"net/http"
 	"strings"
 
-	"github.com/dione-docs-backend/internal/config"
+	"github.com/dione-docs-backend/internal/services"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
 )
 
-type Claims struct {
-	UserID string `json:"user_id"`
-	jwt.RegisteredClaims
-}
-
-func JWTMiddleware(cfg *config.Config) gin.HandlerFunc {
+// JWTMiddleware, erişim token'ını doğrular ve token'ın ait olduğu oturum iptal edilmişse isteği reddeder.
+// "ddpat_" önekli bearer değerleri kişisel erişim token'ı olarak doğrulanır ve kapsamları
+// "token_scopes" anahtarıyla context'e yazılır.
+func JWTMiddleware(sessions *services.SessionService, tokens *services.AccessTokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var tokenString string
 
@@ -36,20 +32,30 @@ func JWTMiddleware(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("unexpected signing method")
+		if services.IsPersonalAccessToken(tokenString) {
+			token, err := tokens.Authenticate(tokenString)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+				return
 			}
-			return []byte(cfg.JWTSecret), nil
-		})
+			c.Set("user_id", token.UserID.String())
+			c.Set("token_id", token.ID)
+			c.Set("token_scopes", token.ScopeList())
+			c.Next()
+			return
+		}
 
-		if err != nil || !token.Valid {
+		claims, sessionID, err := sessions.Authenticate(tokenString, services.SessionMetadata{
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			return
 		}
 
 		c.Set("user_id", claims.UserID)
+		c.Set("session_id", sessionID)
 		c.Next()
 	}
 }
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OrganizationHeader, isteğin hangi organizasyon (çalışma alanı) içinde yapıldığını belirtir.
+// Başlık yoksa istek kullanıcının kişisel alanında değerlendirilir.
+const OrganizationHeader = "X-Organization-ID"
+
+// OrganizationMiddleware, aktif organizasyonu doğrular ve kullanıcının üyeliğini context'e yazar.
+// JWTMiddleware'den sonra kullanılmalıdır.
+func OrganizationMiddleware(repo repository.OrganizationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(OrganizationHeader)
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		organizationID, err := uuid.Parse(header)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid organization ID"})
+			return
+		}
+
+		userID, err := utils.GetUserIDFromContext(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		member, err := repo.GetMember(organizationID, userID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Not a member of this organization"})
+			return
+		}
+
+		c.Set("organization_id", organizationID)
+		c.Set("organization_role", member.Role)
+		c.Next()
+	}
+}
@@ -0,0 +1,200 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dione-docs-backend/internal/ratelimit"
+	"github.com/dione-docs-backend/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// maxKeyBodySize, anahtar için JSON gövdesinden okunacak en fazla bayt sayısıdır.
+const maxKeyBodySize = 64 << 10
+
+// KeyFunc, isteğin hız sınırı anahtarını döndürür. Boş anahtar dönerse istek sınırlanmaz.
+type KeyFunc func(c *gin.Context) string
+
+// KeyByIP, istemcinin IP adresini anahtar olarak kullanır.
+func KeyByIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// KeyByParamAndIP, URL'deki param değerini istemcinin IP adresiyle birlikte anahtar olarak
+// kullanır; böylece tek bir adresten belirli bir kaynağa (ör. bağlantı token'ı) yapılan denemeler
+// sınırlanır.
+func KeyByParamAndIP(param string) KeyFunc {
+	return func(c *gin.Context) string {
+		value := c.Param(param)
+		if value == "" {
+			return ""
+		}
+		return value + "|" + c.ClientIP()
+	}
+}
+
+// KeyByJSONField, JSON gövdesindeki field alanını (ör. hesabın e-posta adresi) küçük harfe
+// çevirerek anahtar olarak kullanır.
+func KeyByJSONField(field string) KeyFunc {
+	return func(c *gin.Context) string {
+		return strings.ToLower(strings.TrimSpace(jsonField(c, field)))
+	}
+}
+
+// KeyByJSONFieldAndIP, KeyByJSONField anahtarını istemcinin IP adresiyle birleştirir. Hesap
+// kilidinde kullanılır; böylece başka bir adresten yapılan denemeler hesabın sahibini kilitleyemez.
+func KeyByJSONFieldAndIP(field string) KeyFunc {
+	byField := KeyByJSONField(field)
+	return func(c *gin.Context) string {
+		value := byField(c)
+		if value == "" {
+			return ""
+		}
+		return value + "|" + c.ClientIP()
+	}
+}
+
+// KeyByMFAUser, /auth/login/mfa isteğindeki doğrulama token'ının ait olduğu kullanıcıyı anahtar
+// olarak kullanır; böylece kilit, isteklerin geldiği adresten bağımsız olarak hesaba uygulanır.
+// Geçersiz token'lar sınırlanmaz; handler bunları zaten reddeder.
+func KeyByMFAUser(mfa *services.MFAService) KeyFunc {
+	return func(c *gin.Context) string {
+		userID, _, err := mfa.ParseChallenge(jsonField(c, "mfa_token"))
+		if err != nil {
+			return ""
+		}
+		return userID.String()
+	}
+}
+
+// KeyByMFAChallenge, /auth/login/mfa isteğindeki doğrulama token'ının ID'sini anahtar olarak
+// kullanır; tek bir token ile yapılabilecek kod denemesi sayısını sınırlamak içindir.
+func KeyByMFAChallenge(mfa *services.MFAService) KeyFunc {
+	return func(c *gin.Context) string {
+		_, challengeID, err := mfa.ParseChallenge(jsonField(c, "mfa_token"))
+		if err != nil {
+			return ""
+		}
+		return challengeID
+	}
+}
+
+// jsonField, JSON gövdesindeki field alanının metin değerini döndürür. Gövde okunduktan sonra
+// handler için geri yerleştirilir.
+func jsonField(c *gin.Context, field string) string {
+	if c.Request.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxKeyBodySize))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+	if err != nil {
+		return ""
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return ""
+	}
+	value, _ := fields[field].(string)
+	return value
+}
+
+// RateLimit, isteği key ile belirlenen kovadan bir token alarak sınırlar. name, aynı anahtarın
+// farklı kurallarda ayrı sayılması için kova adının önekidir. Kova boşsa 429 döner. Yanıtlara
+// X-RateLimit-* başlıkları eklenir; aynı isteğe birden fazla sınır uygulanmışsa başlıklar en
+// kısıtlayıcı olanı gösterir. Depo hatalarında istek sınırlanmadan geçirilir.
+func RateLimit(store ratelimit.Store, name string, rule ratelimit.Rule, key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		k := key(c)
+		if k == "" {
+			c.Next()
+			return
+		}
+
+		result, err := store.Take(c.Request.Context(), storeKey(name, k), rule)
+		if err != nil {
+			log.Printf("Rate limit %s could not be checked: %v", name, err)
+			c.Next()
+			return
+		}
+
+		setRateLimitHeaders(c, result)
+		if !result.Allowed {
+			c.Header("Retry-After", formatSeconds(result.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, please try again later"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Lockout, art arda başarısız denemelerden sonra key'i policy'ye göre kilitler. Handler 401
+// döndürürse deneme başarısız, 2xx döndürürse başarılı sayılır ve sayaç sıfırlanır. Kilitli
+// anahtarlara gelen istekler handler'a ulaşmadan 429 ve Retry-After ile reddedilir.
+func Lockout(store ratelimit.Store, name string, policy ratelimit.LockoutPolicy, key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		k := key(c)
+		if k == "" {
+			c.Next()
+			return
+		}
+		k = storeKey(name, k)
+		ctx := c.Request.Context()
+
+		lockedUntil, err := store.LockedUntil(ctx, k)
+		if err != nil {
+			log.Printf("Lockout %s could not be checked: %v", name, err)
+		} else if wait := time.Until(lockedUntil); wait > 0 {
+			c.Header("Retry-After", formatSeconds(wait))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed attempts, please try again later"})
+			return
+		}
+
+		c.Next()
+
+		status := c.Writer.Status()
+		switch {
+		case status == http.StatusUnauthorized:
+			if _, err := store.RecordFailure(ctx, k, policy); err != nil {
+				log.Printf("Lockout %s failure could not be recorded: %v", name, err)
+			}
+		case status >= 200 && status < 300:
+			if err := store.ResetFailures(ctx, k); err != nil {
+				log.Printf("Lockout %s could not be reset: %v", name, err)
+			}
+		}
+	}
+}
+
+// storeKey, depodaki anahtarı oluşturur. Uzun değerler (ör. e-posta adresleri) sabit uzunlukta
+// saklanmak için özetlenir.
+func storeKey(name, key string) string {
+	if len(key) > 64 {
+		sum := sha256.Sum256([]byte(key))
+		key = hex.EncodeToString(sum[:])
+	}
+	return name + ":" + key
+}
+
+func setRateLimitHeaders(c *gin.Context, result ratelimit.Result) {
+	if previous, err := strconv.Atoi(c.Writer.Header().Get("X-RateLimit-Remaining")); err == nil && previous < result.Remaining {
+		return
+	}
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", formatSeconds(result.Reset))
+}
+
+// formatSeconds, süreyi başlıklarda kullanılan tam saniyeye yukarı yuvarlar.
+func formatSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
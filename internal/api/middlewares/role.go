@@ -3,43 +3,62 @@ package middleware
 import (
 	"net/http"
 
+	"github.com/dione-docs-backend/internal/authz"
+	"github.com/dione-docs-backend/internal/models"
 	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-func RequireRole(repo repository.PermissionRepository, requiredRole string, docIDKey string) gin.HandlerFunc {
+// RequireRole, kullanıcının path'teki belgede en az requiredRole rolüne sahip olmasını şart koşar.
+// Çözülen rol "document_role" anahtarıyla context'e yazılır.
+func RequireRole(authorizer *authz.Authorizer, documents repository.DocumentRepository, requiredRole authz.Role, docIDKey string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userIDStr, exists := c.Get("user_id")
-		if !exists {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		role, ok := resolveDocumentRole(c, authorizer, documents, docIDKey)
+		if !ok {
 			return
 		}
 
-		userID, err := uuid.Parse(userIDStr.(string))
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		if !role.AtLeast(requiredRole) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
 			return
 		}
 
-		docIDStr := c.Param(docIDKey)
-		docID, err := uuid.Parse(docIDStr)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid document ID"})
-			return
-		}
+		c.Set("document_role", role)
+		c.Next()
+	}
+}
 
-		perm, err := repo.GetByDocumentAndUser(userID, docID)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
-			return
-		}
+// RequireAction, kullanıcının path'teki belge üzerinde action işlemini yapabilmesini şart koşar.
+func RequireAction(authorizer *authz.Authorizer, documents repository.DocumentRepository, action authz.Action, docIDKey string) gin.HandlerFunc {
+	return RequireRole(authorizer, documents, authz.RequiredRole(action), docIDKey)
+}
 
-		if perm.AccessType != requiredRole {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
-			return
-		}
+func resolveDocumentRole(c *gin.Context, authorizer *authz.Authorizer, documents repository.DocumentRepository, docIDKey string) (authz.Role, bool) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return authz.RoleNone, false
+	}
 
-		c.Next()
+	docID, err := uuid.Parse(c.Param(docIDKey))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid document ID"})
+		return authz.RoleNone, false
+	}
+
+	organizationID, _ := utils.GetOrganizationFromContext(c)
+	var doc models.Document
+	if err := documents.InOrganization(organizationID).GetByID(docID, &doc); err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+		return authz.RoleNone, false
+	}
+
+	role, err := authorizer.Role(userID, &doc)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check permissions"})
+		return authz.RoleNone, false
 	}
+	return role, true
 }
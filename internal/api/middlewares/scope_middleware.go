@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope, isteği kişisel erişim token'ıyla yapılmışsa token'ın scope kapsamına sahip olmasını
+// şart koşar. Oturum token'larıyla yapılan istekler kullanıcının tüm yetkilerini taşır.
+func RequireScope(scope models.TokenScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasScope(c, scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Token does not have the required scope: " + string(scope)})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScopeByMethod, okuma isteklerinde (GET, HEAD, OPTIONS) read, diğerlerinde write kapsamını ister.
+func RequireScopeByMethod(read, write models.TokenScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := write
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			scope = read
+		}
+		if !hasScope(c, scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Token does not have the required scope: " + string(scope)})
+			return
+		}
+		c.Next()
+	}
+}
+
+func hasScope(c *gin.Context, scope models.TokenScope) bool {
+	value, exists := c.Get("token_scopes")
+	if !exists {
+		return true
+	}
+	scopes, ok := value.([]models.TokenScope)
+	return ok && models.HasTokenScope(scopes, scope)
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireSiteAdmin, isteği yapan kullanıcının site yöneticisi olmasını şart koşar. Yetki her
+// istekte veritabanından okunur; böylece geri alınan yöneticilik hemen etkili olur.
+// JWTMiddleware'den sonra kullanılmalıdır.
+func RequireSiteAdmin(users repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := utils.GetUserIDFromContext(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		var user models.User
+		if err := users.GetByID(userID, &user); err != nil || !user.IsSiteAdmin || user.IsDisabled() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Site administrator access required"})
+			return
+		}
+		c.Next()
+	}
+}
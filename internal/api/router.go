@@ -1,9 +1,15 @@
 package api
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/dione-docs-backend/internal/api/handlers"
 	middleware "github.com/dione-docs-backend/internal/api/middlewares"
+	"github.com/dione-docs-backend/internal/authz"
 	"github.com/dione-docs-backend/internal/config"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/ratelimit"
 	"github.com/dione-docs-backend/internal/repository"
 	"github.com/dione-docs-backend/internal/services"
 	"github.com/gin-gonic/gin"
@@ -13,27 +19,63 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// Kimlik doğrulama uç noktalarının hız sınırları. IP sınırları aynı adresten gelen toplu
+// denemeleri, hesap sınırları ise bir hesaba farklı adreslerden yapılan denemeleri yavaşlatır.
+var (
+	authIPRule          = ratelimit.Rule{Limit: 20, Period: time.Minute}
+	registerIPRule      = ratelimit.Rule{Limit: 5, Period: time.Hour}
+	loginAccountRule    = ratelimit.Rule{Limit: 10, Period: 15 * time.Minute}
+	passwordAccountRule = ratelimit.Rule{Limit: 3, Period: time.Hour}
+	loginLockoutPolicy  = ratelimit.LockoutPolicy{Threshold: 5, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: 24 * time.Hour}
+	refreshIPRule       = ratelimit.Rule{Limit: 60, Period: time.Minute}
+	// İki adımlı doğrulama token'ı 5 dakika geçerli olduğundan kova o süre içinde dolmaz ve
+	// bir token ile en fazla 5 kod denenebilir.
+	mfaChallengeRule = ratelimit.Rule{Limit: 5, Period: time.Hour}
+	// Paylaşım bağlantıları parola ile korunabildiğinden bir adresten aynı bağlantıya yapılan
+	// istekler sınırlanır.
+	shareLinkRule = ratelimit.Rule{Limit: 20, Period: time.Minute}
+)
+
 type Router struct {
 	engine     *gin.Engine
 	repository *repository.Repository
+	services   *services.Service
 	config     *config.Config
+	otHubs     *handlers.HubManager
+	chatHubs   *handlers.ChatHubManager
+	authorizer *authz.Authorizer
 }
 
-func NewRouter(repo *repository.Repository, cfg *config.Config) *Router {
+func NewRouter(repo *repository.Repository, svc *services.Service, cfg *config.Config) (*Router, error) {
+	authorizer := authz.NewAuthorizer(repo.Permission)
 	r := &Router{
 		engine:     gin.New(),
 		repository: repo,
+		services:   svc,
 		config:     cfg,
+		otHubs:     handlers.NewHubManager(repo),
+		chatHubs:   handlers.NewChatHubManager(repo, authorizer),
+		authorizer: authorizer,
+	}
+	// İstemci IP'si hız sınırlarında anahtar olarak kullanıldığından X-Forwarded-For yalnızca
+	// yapılandırılmış vekillerden geldiğinde dikkate alınır.
+	if err := r.engine.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
 	}
 	r.setupMiddlewares()
 	r.setupRoutes()
-	return r
+	return r, nil
 }
 
 func (r *Router) Engine() *gin.Engine {
 	return r.engine
 }
 
+// Disconnectors, kullanıcıların canlı websocket bağlantılarını kapatabilen hub yöneticilerini döndürür.
+func (r *Router) Disconnectors() []services.SessionDisconnector {
+	return []services.SessionDisconnector{r.otHubs, r.chatHubs}
+}
+
 func (r *Router) setupMiddlewares() {
 	r.engine.Use(
 		gin.Logger(),
@@ -43,38 +85,122 @@ func (r *Router) setupMiddlewares() {
 }
 
 func (r *Router) setupRoutes() {
-	importService := services.NewImportService(r.repository.Document, r.config)
-
 	// Instantiate Handlers
-	authHandler := handlers.NewAuthHandler(r.repository, r.config)
-	docHandler := handlers.NewDocumentHandler(r.repository)
-	permHandler := handlers.NewPermissionHandler(r.repository)
-	importHandler := handlers.NewImportHandler(importService)
+	authHandler := handlers.NewAuthHandler(r.repository, r.config, r.services.Sessions, r.services.Accounts, r.services.MFA)
+	oauthHandler := handlers.NewOAuthHandler(r.repository, r.config, r.services.Sessions, r.services.MFA)
+	mfaHandler := handlers.NewMFAHandler(r.repository, r.services.MFA)
+	accessTokenHandler := handlers.NewAccessTokenHandler(r.repository, r.services.Tokens)
+	jwksHandler := handlers.NewJWKSHandler(r.services.Signing)
+	profileHandler := handlers.NewProfileHandler(r.repository, r.services.Accounts, r.services.Avatars, r.services.Privacy, r.services.MFA)
+	otHubManager := r.otHubs
 
-	otHubManager := handlers.NewHubManager(r.repository.Document)
+	docHandler := handlers.NewDocumentHandler(r.repository, otHubManager, r.authorizer)
+	permHandler := handlers.NewPermissionHandler(r.repository, r.authorizer, r.services.Mailer, r.config)
+	importHandler := handlers.NewImportHandler(r.services.Import)
+	retentionHandler := handlers.NewRetentionHandler(r.repository, r.services.Retention, r.authorizer)
+	blameHandler := handlers.NewBlameHandler(r.repository, otHubManager, r.services.Blame, r.authorizer)
+	shareLinkHandler := handlers.NewShareLinkHandler(r.repository, r.authorizer, r.services.Signing)
+	notificationHandler := handlers.NewNotificationHandler(r.repository)
+	teamHandler := handlers.NewTeamHandler(r.repository, r.authorizer)
+	organizationHandler := handlers.NewOrganizationHandler(r.repository)
+	commentHandler := handlers.NewCommentHandler(r.repository, otHubManager, r.authorizer)
+	suggestionHandler := handlers.NewSuggestionHandler(r.repository, otHubManager, r.authorizer)
+	transferHandler := handlers.NewTransferHandler(r.repository, r.authorizer)
+	accessRequestHandler := handlers.NewAccessRequestHandler(r.repository, r.authorizer)
+	adminHandler := handlers.NewAdminHandler(r.repository, r.services.Sessions, r.Disconnectors())
 
-	chatHubManager := handlers.NewChatHubManager(r.repository)
-	chatHandler := handlers.NewChatHandler(r.repository, chatHubManager, r.config)
+	chatHubManager := r.chatHubs
+	chatHandler := handlers.NewChatHandler(r.repository, chatHubManager, r.config, r.authorizer)
 
 	// Public routes
 	apiPublic := r.engine.Group("/api/v1")
 	{
-		apiPublic.POST("/register", authHandler.RegisterHandler)
-		apiPublic.POST("/login", authHandler.LoginHandler)
+		limiter := r.services.Limiter
+		byEmail := middleware.KeyByJSONField("email")
+
+		apiPublic.POST("/register",
+			middleware.RateLimit(limiter, "register-ip", registerIPRule, middleware.KeyByIP),
+			authHandler.RegisterHandler)
+		apiPublic.POST("/login",
+			middleware.RateLimit(limiter, "login-ip", authIPRule, middleware.KeyByIP),
+			middleware.RateLimit(limiter, "login-account", loginAccountRule, byEmail),
+			middleware.Lockout(limiter, "login", loginLockoutPolicy, middleware.KeyByJSONFieldAndIP("email")),
+			authHandler.LoginHandler)
+		apiPublic.POST("/auth/login/mfa",
+			middleware.RateLimit(limiter, "login-mfa-ip", authIPRule, middleware.KeyByIP),
+			middleware.RateLimit(limiter, "login-mfa-challenge", mfaChallengeRule, middleware.KeyByMFAChallenge(r.services.MFA)),
+			middleware.Lockout(limiter, "login-mfa", loginLockoutPolicy, middleware.KeyByMFAUser(r.services.MFA)),
+			authHandler.LoginMFAHandler)
+		apiPublic.POST("/auth/refresh",
+			middleware.RateLimit(limiter, "refresh-ip", refreshIPRule, middleware.KeyByIP),
+			authHandler.RefreshHandler)
+		apiPublic.POST("/auth/password/forgot",
+			middleware.RateLimit(limiter, "password-forgot-ip", authIPRule, middleware.KeyByIP),
+			middleware.RateLimit(limiter, "password-forgot-account", passwordAccountRule, byEmail),
+			authHandler.ForgotPasswordHandler)
+		apiPublic.POST("/auth/password/reset",
+			middleware.RateLimit(limiter, "password-reset-ip", authIPRule, middleware.KeyByIP),
+			authHandler.ResetPasswordHandler)
+		apiPublic.POST("/auth/email/verify",
+			middleware.RateLimit(limiter, "email-verify-ip", authIPRule, middleware.KeyByIP),
+			authHandler.VerifyEmailHandler)
+		apiPublic.POST("/auth/email/change/confirm",
+			middleware.RateLimit(limiter, "email-change-ip", authIPRule, middleware.KeyByIP),
+			profileHandler.ConfirmEmailChange)
+		apiPublic.GET("/users/:user_id/avatar/:size", profileHandler.GetAvatar)
+		apiPublic.GET("/auth/google/login", oauthHandler.GoogleLogin)
+		apiPublic.GET("/auth/google/callback", oauthHandler.GoogleCallback)
+		apiPublic.GET("/links/:token",
+			middleware.RateLimit(limiter, "share-link", shareLinkRule, middleware.KeyByParamAndIP("token")),
+			shareLinkHandler.ViewSharedDocument)
 	}
 
 	// Authenticated routes
 	apiAuth := r.engine.Group("/api/v1")
-	apiAuth.Use(middleware.JWTMiddleware(r.config))
+	apiAuth.Use(middleware.JWTMiddleware(r.services.Sessions, r.services.Tokens), middleware.OrganizationMiddleware(r.repository.Organization))
 	{
+		// Kişisel erişim token'larıyla yapılan istekler grubun kapsamını taşımalıdır.
+		documentScope := middleware.RequireScopeByMethod(models.ScopeDocumentsRead, models.ScopeDocumentsWrite)
+		adminScope := middleware.RequireScope(models.ScopeAdmin)
+
 		apiAuth.GET("/me", authHandler.GetCurrentUser)
 
-		apiAuth.GET("/ws/documents/:id", otHubManager.ServeWs)
+		me := apiAuth.Group("/me", adminScope)
+		{
+			me.PATCH("", profileHandler.UpdateProfile)
+			me.DELETE("", profileHandler.DeleteAccount)
+			me.GET("/data-export", profileHandler.ExportAccountData)
+			me.POST("/avatar", profileHandler.UploadAvatar)
+			me.DELETE("/avatar", profileHandler.DeleteAvatar)
+			me.POST("/password", profileHandler.ChangePassword)
+			me.POST("/email", profileHandler.ChangeEmail)
 
-		apiAuth.GET("/ws/chat/documents/:id", chatHandler.ServeChatWs)
+			me.GET("/tokens", accessTokenHandler.GetAccessTokens)
+			me.POST("/tokens", accessTokenHandler.CreateAccessToken)
+			me.DELETE("/tokens/:token_id", accessTokenHandler.RevokeAccessToken)
+		}
+
+		auth := apiAuth.Group("/auth", adminScope)
+		{
+			auth.POST("/logout", authHandler.LogoutHandler)
+			auth.POST("/email/verification", authHandler.ResendVerificationHandler)
+			auth.GET("/sessions", authHandler.GetSessions)
+			auth.DELETE("/sessions", authHandler.RevokeAllSessions)
+			auth.DELETE("/sessions/:session_id", authHandler.RevokeSession)
+
+			auth.GET("/mfa", mfaHandler.GetMFAStatus)
+			auth.POST("/mfa/enroll", mfaHandler.EnrollMFA)
+			auth.POST("/mfa/confirm", mfaHandler.ConfirmMFA)
+			auth.POST("/mfa/disable", mfaHandler.DisableMFA)
+			auth.POST("/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+		}
+
+		apiAuth.GET("/ws/documents/:id", middleware.RequireScope(models.ScopeDocumentsWrite), middleware.RequireAction(r.authorizer, r.repository.Document, authz.ActionView, "id"), otHubManager.ServeWs)
+
+		apiAuth.GET("/ws/chat/documents/:id", middleware.RequireScope(models.ScopeChatWrite), chatHandler.ServeChatWs)
 
 		// Document Routes
-		docs := apiAuth.Group("/documents")
+		docs := apiAuth.Group("/documents", documentScope)
 		{
 			docs.POST("", docHandler.CreateDocument)
 			docs.GET("/user", docHandler.GetUserDocuments)
@@ -82,6 +208,16 @@ func (r *Router) setupRoutes() {
 			docs.PUT("/:id", docHandler.UpdateDocument)
 			docs.DELETE("/:id", docHandler.DeleteDocument)
 			docs.GET("/:id/versions", docHandler.GetDocumentVersions)
+			docs.GET("/:id/versions/:version", docHandler.GetDocumentVersion)
+			docs.PATCH("/:id/versions/:version", docHandler.UpdateDocumentVersion)
+			docs.POST("/:id/versions/:version/restore", docHandler.RestoreDocumentVersion)
+
+			docs.GET("/:id/blame", blameHandler.GetDocumentBlame)
+
+			docs.GET("/:id/retention", retentionHandler.GetRetentionPolicy)
+			docs.PUT("/:id/retention", retentionHandler.UpdateRetentionPolicy)
+			docs.DELETE("/:id/retention", retentionHandler.DeleteRetentionPolicy)
+			docs.GET("/:id/retention/report", retentionHandler.GetRetentionReport)
 
 			// YENİ: Chat geçmişini getirmek için REST endpoint'i
 			docs.GET("/:id/messages", chatHandler.GetMessages)
@@ -89,20 +225,109 @@ func (r *Router) setupRoutes() {
 			docs.POST("/:id/permissions/share", permHandler.ShareDocument)
 			docs.POST("/:id/permissions/remove", permHandler.RemoveAccess)
 			docs.GET("/:id/permissions", permHandler.GetDocumentPermissions)
+			docs.GET("/:id/permissions/email-invitations", permHandler.GetEmailInvitations)
 			docs.GET("/:id/role", permHandler.GetUserDocumentPermission)
+			docs.POST("/:id/permissions/teams", teamHandler.ShareDocumentWithTeam)
+			docs.GET("/:id/permissions/teams", teamHandler.GetDocumentTeamPermissions)
+			docs.DELETE("/:id/permissions/teams/:team_id", teamHandler.RemoveDocumentTeamPermission)
+
+			docs.GET("/:id/comments", commentHandler.GetComments)
+			docs.POST("/:id/comments", commentHandler.CreateComment)
+			docs.PUT("/:id/comments/:comment_id", commentHandler.UpdateComment)
+			docs.DELETE("/:id/comments/:comment_id", commentHandler.DeleteComment)
+			docs.POST("/:id/comments/:comment_id/replies", commentHandler.ReplyToComment)
+			docs.POST("/:id/comments/:comment_id/resolve", commentHandler.ResolveComment)
+			docs.POST("/:id/comments/:comment_id/reopen", commentHandler.ReopenComment)
+
+			docs.GET("/:id/suggestions", suggestionHandler.GetSuggestions)
+			docs.POST("/:id/suggestions/bulk", suggestionHandler.BulkResolveSuggestions)
+			docs.POST("/:id/suggestions/:suggestion_id/accept", suggestionHandler.AcceptSuggestion)
+			docs.POST("/:id/suggestions/:suggestion_id/reject", suggestionHandler.RejectSuggestion)
+
+			docs.POST("/:id/links", shareLinkHandler.CreateShareLink)
+			docs.GET("/:id/links", shareLinkHandler.GetShareLinks)
+			docs.DELETE("/:id/links/:link_id", shareLinkHandler.RevokeShareLink)
+
+			docs.POST("/:id/transfer", transferHandler.TransferOwnership)
+			docs.DELETE("/:id/transfer", transferHandler.CancelOwnershipTransfer)
+
+			docs.POST("/:id/access-requests", accessRequestHandler.CreateAccessRequest)
+			docs.GET("/:id/access-requests", accessRequestHandler.GetAccessRequests)
+			docs.POST("/:id/access-requests/:request_id/approve", accessRequestHandler.ApproveAccessRequest)
+			docs.POST("/:id/access-requests/:request_id/deny", accessRequestHandler.DenyAccessRequest)
 		}
 
-		invitations := apiAuth.Group("/invitations")
+		invitations := apiAuth.Group("/invitations", documentScope)
 		{
 			invitations.GET("/pending", permHandler.GetPendingInvitations)
 			invitations.POST("/:invitation_id/accept", permHandler.AcceptInvitation)
 			invitations.POST("/:invitation_id/reject", permHandler.RejectInvitation)
 		}
 
-		imp := apiAuth.Group("/import")
+		apiAuth.GET("/access-requests", documentScope, accessRequestHandler.GetMyAccessRequests)
+
+		transfers := apiAuth.Group("/transfers", documentScope)
+		{
+			transfers.GET("/pending", transferHandler.GetPendingTransfers)
+			transfers.POST("/:transfer_id/accept", transferHandler.AcceptTransfer)
+			transfers.POST("/:transfer_id/reject", transferHandler.RejectTransfer)
+		}
+
+		apiAuth.POST("/links/:token/redeem", documentScope,
+			middleware.RateLimit(r.services.Limiter, "share-link", shareLinkRule, middleware.KeyByParamAndIP("token")),
+			shareLinkHandler.RedeemShareLink)
+
+		teams := apiAuth.Group("/teams", adminScope)
+		{
+			teams.POST("", teamHandler.CreateTeam)
+			teams.GET("", teamHandler.GetUserTeams)
+			teams.GET("/:team_id", teamHandler.GetTeam)
+			teams.PUT("/:team_id", teamHandler.UpdateTeam)
+			teams.DELETE("/:team_id", teamHandler.DeleteTeam)
+			teams.POST("/:team_id/members", teamHandler.AddTeamMember)
+			teams.PUT("/:team_id/members/:user_id", teamHandler.UpdateTeamMember)
+			teams.DELETE("/:team_id/members/:user_id", teamHandler.RemoveTeamMember)
+		}
+
+		organizations := apiAuth.Group("/organizations", adminScope)
+		{
+			organizations.POST("", organizationHandler.CreateOrganization)
+			organizations.GET("", organizationHandler.GetUserOrganizations)
+			organizations.GET("/:org_id", organizationHandler.GetOrganization)
+			organizations.PUT("/:org_id", organizationHandler.UpdateOrganization)
+			organizations.DELETE("/:org_id", organizationHandler.DeleteOrganization)
+			organizations.GET("/:org_id/members", organizationHandler.GetOrganizationMembers)
+			organizations.POST("/:org_id/members", organizationHandler.AddOrganizationMember)
+			organizations.PUT("/:org_id/members/:user_id", organizationHandler.UpdateOrganizationMember)
+			organizations.DELETE("/:org_id/members/:user_id", organizationHandler.RemoveOrganizationMember)
+			organizations.POST("/:org_id/members/:user_id/transfer-documents", organizationHandler.TransferMemberDocuments)
+		}
+
+		notifications := apiAuth.Group("/notifications", documentScope)
+		{
+			notifications.GET("", notificationHandler.GetNotifications)
+			notifications.POST("/read-all", notificationHandler.MarkAllNotificationsRead)
+			notifications.POST("/:notification_id/read", notificationHandler.MarkNotificationRead)
+		}
+
+		imp := apiAuth.Group("/import", middleware.RequireScope(models.ScopeDocumentsWrite))
 		{
 			imp.POST("/docx", importHandler.ImportDocxHandler)
 		}
+
+		// Site yöneticisi işlemleri; her işlem denetim kaydına yazılır.
+		admin := apiAuth.Group("/admin", adminScope, middleware.RequireSiteAdmin(r.repository.User))
+		{
+			admin.GET("/users", adminHandler.SearchUsers)
+			admin.GET("/users/:user_id", adminHandler.GetUser)
+			admin.POST("/users/:user_id/disable", adminHandler.DisableUser)
+			admin.POST("/users/:user_id/enable", adminHandler.EnableUser)
+			admin.POST("/users/:user_id/logout", adminHandler.ForceLogout)
+			admin.DELETE("/users/:user_id/mfa", adminHandler.ResetUserMFA)
+			admin.GET("/documents/:id", adminHandler.GetDocument)
+			admin.POST("/documents/:id/restore", adminHandler.RestoreDocument)
+			admin.GET("/audit-logs", adminHandler.GetAuditLogs)
+		}
 	}
 
 	apiInternal := r.engine.Group("/api/v1/internal")
@@ -114,6 +339,9 @@ func (r *Router) setupRoutes() {
 		}
 	}
 
+	// Diğer servislerin erişim token'larını doğrulamak için kullandığı açık anahtarlar
+	r.engine.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
 	// Swagger documentation route
 	r.engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 }
@@ -13,6 +13,8 @@ import (
 	"github.com/dione-docs-backend/internal/api"
 	"github.com/dione-docs-backend/internal/config"
 	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/services"
+	"github.com/dione-docs-backend/internal/signing"
 	"github.com/dione-docs-backend/internal/utils"
 	"gorm.io/gorm"
 )
@@ -21,6 +23,8 @@ type Application struct {
 	cfg        *config.Config
 	db         *gorm.DB
 	repository *repository.Repository
+	services   *services.Service
+	scheduler  *services.Scheduler
 	router     *api.Router
 	server     *http.Server
 }
@@ -37,7 +41,14 @@ func NewApplication() (*Application, error) {
 	}
 
 	app.initializeRepositories()
-	app.initializeRouter()
+	app.migrateVersionStorage()
+	if err := app.initializeServices(); err != nil {
+		return nil, fmt.Errorf("service error: %w", err)
+	}
+	if err := app.initializeRouter(); err != nil {
+		return nil, fmt.Errorf("router error: %w", err)
+	}
+	app.initializeJobs()
 
 	return app, nil
 }
@@ -69,12 +80,51 @@ func (a *Application) initializeRepositories() {
 	a.repository = repository.NewRepository(a.db)
 }
 
-func (a *Application) initializeRouter() {
-	a.router = api.NewRouter(a.repository, a.cfg)
+// migrateVersionStorage, eski tam içerikli versiyonları snapshot + Delta düzenine çevirir.
+// Hata açılışı engellemez; dönüştürülemeyen kayıtlar bir sonraki açılışta tekrar denenir.
+func (a *Application) migrateVersionStorage() {
+	converted, err := a.repository.Document.ConvertLegacyVersions()
+	if err != nil {
+		log.Printf("Version storage migration failed: %v", err)
+	}
+	if converted > 0 {
+		log.Printf("Version storage migration converted %d versions", converted)
+	}
+}
+
+func (a *Application) initializeServices() error {
+	keys, err := signing.Load(a.cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load token signing keys: %w", err)
+	}
+	a.services = services.NewService(a.repository, a.cfg, keys)
+	return nil
+}
+
+func (a *Application) initializeJobs() {
+	a.scheduler = services.NewScheduler()
+	a.scheduler.Every("version-retention", a.cfg.VersionRetentionInterval, a.services.Retention.RunAll)
+
+	for _, disconnector := range a.router.Disconnectors() {
+		a.services.Expiry.AddDisconnector(disconnector)
+	}
+	a.scheduler.Every("permission-expiry", a.cfg.PermissionExpiryInterval, a.services.Expiry.RunAll)
+	a.scheduler.Every("session-cleanup", time.Hour, a.services.Sessions.RunCleanup)
+	a.scheduler.Every("account-token-cleanup", time.Hour, a.services.Accounts.RunCleanup)
+	a.scheduler.Every("rate-limit-cleanup", 10*time.Minute, a.services.Limiter.Cleanup)
+}
+
+func (a *Application) initializeRouter() error {
+	router, err := api.NewRouter(a.repository, a.services, a.cfg)
+	if err != nil {
+		return err
+	}
+	a.router = router
 	a.server = &http.Server{
 		Addr:    fmt.Sprintf(":%s", a.cfg.Port),
 		Handler: a.router.Engine(),
 	}
+	return nil
 }
 
 func (a *Application) Run() error {
@@ -89,6 +139,8 @@ func (a *Application) Run() error {
 		}
 	}()
 
+	a.scheduler.Start(ctx)
+
 	<-ctx.Done()
 	log.Println("Shutting down server...")
 
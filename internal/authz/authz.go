@@ -0,0 +1,113 @@
+// Package authz, belge erişim kurallarını tek bir yerde toplar. Handler'lar, middleware'ler ve
+// websocket hub'ları bir kullanıcının belge üzerinde bir işlemi yapıp yapamayacağını buradan sorar.
+package authz
+
+import (
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Role, kullanıcının belge üzerindeki etkin rolüdür.
+// Hiyerarşi: owner > admin > editor > commenter > viewer.
+type Role string
+
+const (
+	RoleNone      Role = ""
+	RoleViewer    Role = Role(models.AccessTypeViewer)
+	RoleCommenter Role = Role(models.AccessTypeCommenter)
+	RoleEditor    Role = Role(models.AccessTypeEditor)
+	RoleAdmin     Role = Role(models.AccessTypeAdmin)
+	RoleOwner     Role = "owner"
+)
+
+func (r Role) rank() int {
+	if r == RoleOwner {
+		return models.AccessRank(string(models.AccessTypeAdmin)) + 1
+	}
+	return models.AccessRank(string(r))
+}
+
+// AtLeast, rolün required rolünü veya daha yetkili bir rolü kapsayıp kapsamadığını döndürür.
+func (r Role) AtLeast(required Role) bool {
+	return r != RoleNone && r.rank() >= required.rank()
+}
+
+// Action, belge üzerinde yetki gerektiren bir işlemdir.
+type Action string
+
+const (
+	ActionView            Action = "view"             // belgeyi, geçmişini, yazar bilgisini ve sohbeti okuma
+	ActionComment         Action = "comment"          // sohbete mesaj ve belgeye yorum yazma, yorumları çözme
+	ActionSuggest         Action = "suggest"          // belgeyi değiştirmeden düzenleme önerme
+	ActionEdit            Action = "edit"             // içeriği ve versiyonları düzenleme, geri yükleme, önerileri kabul etme
+	ActionShare           Action = "share"            // izinleri, paylaşım bağlantılarını ve takım paylaşımlarını yönetme
+	ActionManageRetention Action = "manage_retention" // versiyon saklama politikasını yönetme
+	ActionDelete          Action = "delete"           // belgeyi silme
+	ActionModerate        Action = "moderate"         // başkalarının yorumlarını silme
+	ActionTransfer        Action = "transfer"         // belge sahipliğini devretme
+)
+
+var requiredRoles = map[Action]Role{
+	ActionView:            RoleViewer,
+	ActionComment:         RoleCommenter,
+	ActionSuggest:         RoleCommenter,
+	ActionEdit:            RoleEditor,
+	ActionShare:           RoleAdmin,
+	ActionManageRetention: RoleAdmin,
+	ActionDelete:          RoleAdmin,
+	ActionModerate:        RoleAdmin,
+	ActionTransfer:        RoleOwner,
+}
+
+// RequiredRole, işlem için gereken en düşük rolü döndürür. Bilinmeyen işlemler için owner döner.
+func RequiredRole(action Action) Role {
+	if role, ok := requiredRoles[action]; ok {
+		return role
+	}
+	return RoleOwner
+}
+
+// Allowed, role sahip bir kullanıcının işlemi yapıp yapamayacağını döndürür.
+func Allowed(role Role, action Action) bool {
+	return role.AtLeast(RequiredRole(action))
+}
+
+// Authorizer, kullanıcıların belgelerdeki rollerini çözer ve işlem yetkilerini denetler.
+type Authorizer struct {
+	permissions repository.PermissionRepository
+}
+
+func NewAuthorizer(permissions repository.PermissionRepository) *Authorizer {
+	return &Authorizer{
+		permissions: permissions,
+	}
+}
+
+// Role, kullanıcının belgedeki etkin rolünü döndürür: sahiplik, doğrudan, takım ve organizasyon
+// izinlerinden en yetkilisi. Herkese açık belgelerde her kullanıcı en az viewer'dır.
+func (a *Authorizer) Role(userID uuid.UUID, doc *models.Document) (Role, error) {
+	if doc.OwnerID == userID {
+		return RoleOwner, nil
+	}
+
+	accessType, err := a.permissions.GetEffectiveAccessType(doc.ID, userID)
+	if err != nil {
+		return RoleNone, err
+	}
+
+	role := Role(accessType)
+	if doc.IsPublic && !role.AtLeast(RoleViewer) {
+		role = RoleViewer
+	}
+	return role, nil
+}
+
+// Can, kullanıcının belge üzerinde işlemi yapıp yapamayacağını döndürür.
+func (a *Authorizer) Can(userID uuid.UUID, action Action, doc *models.Document) (bool, error) {
+	role, err := a.Role(userID, doc)
+	if err != nil {
+		return false, err
+	}
+	return Allowed(role, action), nil
+}
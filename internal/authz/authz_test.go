@@ -0,0 +1,236 @@
+package authz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	allRoles   = []Role{RoleNone, RoleViewer, RoleCommenter, RoleEditor, RoleAdmin, RoleOwner}
+	allActions = []Action{
+		ActionView, ActionComment, ActionSuggest, ActionEdit, ActionShare,
+		ActionManageRetention, ActionDelete, ActionModerate, ActionTransfer,
+	}
+)
+
+func TestRequiredRole(t *testing.T) {
+	tests := []struct {
+		action Action
+		want   Role
+	}{
+		{ActionView, RoleViewer},
+		{ActionComment, RoleCommenter},
+		{ActionSuggest, RoleCommenter},
+		{ActionEdit, RoleEditor},
+		{ActionShare, RoleAdmin},
+		{ActionManageRetention, RoleAdmin},
+		{ActionDelete, RoleAdmin},
+		{ActionModerate, RoleAdmin},
+		{ActionTransfer, RoleOwner},
+		{Action("unknown"), RoleOwner},
+	}
+	if len(tests)-1 != len(allActions) {
+		t.Fatalf("table covers %d actions, want %d", len(tests)-1, len(allActions))
+	}
+
+	for _, tt := range tests {
+		if got := RequiredRole(tt.action); got != tt.want {
+			t.Errorf("RequiredRole(%q) = %q, want %q", tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	// allowed[action], işlemi yapabilen rollerin kümesidir.
+	allowed := map[Action][]Role{
+		ActionView:            {RoleViewer, RoleCommenter, RoleEditor, RoleAdmin, RoleOwner},
+		ActionComment:         {RoleCommenter, RoleEditor, RoleAdmin, RoleOwner},
+		ActionSuggest:         {RoleCommenter, RoleEditor, RoleAdmin, RoleOwner},
+		ActionEdit:            {RoleEditor, RoleAdmin, RoleOwner},
+		ActionShare:           {RoleAdmin, RoleOwner},
+		ActionManageRetention: {RoleAdmin, RoleOwner},
+		ActionDelete:          {RoleAdmin, RoleOwner},
+		ActionModerate:        {RoleAdmin, RoleOwner},
+		ActionTransfer:        {RoleOwner},
+	}
+
+	for _, action := range allActions {
+		roles, ok := allowed[action]
+		if !ok {
+			t.Fatalf("no expectation for action %q", action)
+		}
+		for _, role := range allRoles {
+			want := false
+			for _, r := range roles {
+				if r == role {
+					want = true
+				}
+			}
+			if got := Allowed(role, action); got != want {
+				t.Errorf("Allowed(%q, %q) = %v, want %v", role, action, got, want)
+			}
+		}
+	}
+}
+
+func TestAllowedUnknownRole(t *testing.T) {
+	for _, action := range allActions {
+		if Allowed(Role("superuser"), action) {
+			t.Errorf("Allowed(unknown role, %q) = true, want false", action)
+		}
+	}
+}
+
+// fakePermissions, GetEffectiveAccessType'ı doğrudan, takım ve organizasyon izinlerinden
+// gerçek depo ile aynı kurallarla hesaplar. Diğer metodlar kullanılmaz.
+type fakePermissions struct {
+	repository.PermissionRepository
+	direct       map[uuid.UUID]string
+	team         map[uuid.UUID]string
+	organization map[uuid.UUID]models.OrganizationRole
+	orgDefault   string
+	err          error
+}
+
+func (f *fakePermissions) GetEffectiveAccessType(documentID, userID uuid.UUID) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	accessTypes := []string{f.direct[userID], f.team[userID]}
+	if role, ok := f.organization[userID]; ok {
+		member := models.OrganizationMember{Role: role}
+		accessTypes = append(accessTypes, member.DocumentAccess(f.orgDefault))
+	}
+	return models.HighestAccess(accessTypes...), nil
+}
+
+func TestAuthorizerRole(t *testing.T) {
+	owner := uuid.New()
+	user := uuid.New()
+
+	tests := []struct {
+		name     string
+		public   bool
+		userID   uuid.UUID
+		perms    fakePermissions
+		wantRole Role
+	}{
+		{
+			name:     "owner",
+			userID:   owner,
+			perms:    fakePermissions{direct: map[uuid.UUID]string{owner: string(models.AccessTypeViewer)}},
+			wantRole: RoleOwner,
+		},
+		{
+			name:     "no access",
+			userID:   user,
+			wantRole: RoleNone,
+		},
+		{
+			name:     "public document",
+			public:   true,
+			userID:   user,
+			wantRole: RoleViewer,
+		},
+		{
+			name:     "public document keeps higher grant",
+			public:   true,
+			userID:   user,
+			perms:    fakePermissions{direct: map[uuid.UUID]string{user: string(models.AccessTypeEditor)}},
+			wantRole: RoleEditor,
+		},
+		{
+			name:     "direct permission",
+			userID:   user,
+			perms:    fakePermissions{direct: map[uuid.UUID]string{user: string(models.AccessTypeCommenter)}},
+			wantRole: RoleCommenter,
+		},
+		{
+			name:     "team permission",
+			userID:   user,
+			perms:    fakePermissions{team: map[uuid.UUID]string{user: string(models.AccessTypeEditor)}},
+			wantRole: RoleEditor,
+		},
+		{
+			name:   "team permission outranks direct",
+			userID: user,
+			perms: fakePermissions{
+				direct: map[uuid.UUID]string{user: string(models.AccessTypeViewer)},
+				team:   map[uuid.UUID]string{user: string(models.AccessTypeAdmin)},
+			},
+			wantRole: RoleAdmin,
+		},
+		{
+			name:   "organization member gets default access",
+			userID: user,
+			perms: fakePermissions{
+				organization: map[uuid.UUID]models.OrganizationRole{user: models.OrganizationRoleMember},
+				orgDefault:   string(models.AccessTypeCommenter),
+			},
+			wantRole: RoleCommenter,
+		},
+		{
+			name:   "organization admin",
+			userID: user,
+			perms: fakePermissions{
+				organization: map[uuid.UUID]models.OrganizationRole{user: models.OrganizationRoleAdmin},
+				orgDefault:   string(models.AccessTypeViewer),
+			},
+			wantRole: RoleAdmin,
+		},
+		{
+			name:   "organization guest relies on explicit grants",
+			userID: user,
+			perms: fakePermissions{
+				direct:       map[uuid.UUID]string{user: string(models.AccessTypeViewer)},
+				organization: map[uuid.UUID]models.OrganizationRole{user: models.OrganizationRoleGuest},
+				orgDefault:   string(models.AccessTypeEditor),
+			},
+			wantRole: RoleViewer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &models.Document{ID: uuid.New(), OwnerID: owner, IsPublic: tt.public}
+			perms := tt.perms
+			authorizer := NewAuthorizer(&perms)
+
+			role, err := authorizer.Role(tt.userID, doc)
+			if err != nil {
+				t.Fatalf("Role returned error: %v", err)
+			}
+			if role != tt.wantRole {
+				t.Fatalf("Role = %q, want %q", role, tt.wantRole)
+			}
+
+			for _, action := range allActions {
+				can, err := authorizer.Can(tt.userID, action, doc)
+				if err != nil {
+					t.Fatalf("Can returned error: %v", err)
+				}
+				if want := Allowed(tt.wantRole, action); can != want {
+					t.Errorf("Can(%q) = %v, want %v", action, can, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthorizerRoleError(t *testing.T) {
+	errDB := errors.New("db down")
+	authorizer := NewAuthorizer(&fakePermissions{err: errDB})
+	doc := &models.Document{ID: uuid.New(), OwnerID: uuid.New(), IsPublic: true}
+
+	role, err := authorizer.Role(uuid.New(), doc)
+	if !errors.Is(err, errDB) || role != RoleNone {
+		t.Fatalf("Role = (%q, %v), want (%q, %v)", role, err, RoleNone, errDB)
+	}
+	if can, err := authorizer.Can(uuid.New(), ActionView, doc); can || !errors.Is(err, errDB) {
+		t.Fatalf("Can = (%v, %v), want (false, %v)", can, err, errDB)
+	}
+}
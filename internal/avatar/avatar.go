@@ -0,0 +1,113 @@
+// Package avatar, yüklenen profil resimlerini kare olarak kırpar ve standart boyutlara küçültür.
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	// Yüklemelerde desteklenen biçimlerin çözücüleri
+	_ "image/gif"
+	_ "image/jpeg"
+)
+
+// Sizes, her avatar için üretilen kare boyutlardır (piksel).
+var Sizes = []int{32, 64, 128, 256}
+
+// MaxPixels, çözülmesine izin verilen en büyük resim alanıdır; sıkıştırma bombalarına karşı korur.
+const MaxPixels = 40_000_000
+
+var ErrUnsupportedImage = errors.New("unsupported or invalid image")
+
+// Process, resmi çözer, ortasından kare olarak kırpar ve Sizes'daki her boyut için PNG üretir.
+func Process(r io.Reader) (map[int][]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, ErrUnsupportedImage
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	square := centerSquare(src.Bounds())
+	images := make(map[int][]byte, len(Sizes))
+	for _, size := range Sizes {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, resize(src, square, size)); err != nil {
+			return nil, err
+		}
+		images[size] = buf.Bytes()
+	}
+	return images, nil
+}
+
+// ValidSize, boyutun üretilen avatar boyutlarından biri olup olmadığını döndürür.
+func ValidSize(size int) bool {
+	for _, s := range Sizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+func centerSquare(bounds image.Rectangle) image.Rectangle {
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+	return image.Rect(x, y, x+side, y+side)
+}
+
+// resize, src'nin area bölgesini size x size boyutuna kutu filtresiyle (alan ortalaması) ölçekler.
+// Kaynaktan küçük boyutlarda her hedef pikseli kapsadığı kaynak piksellerin ortalamasıdır;
+// büyütmede en yakın piksel kullanılır.
+func resize(src image.Image, area image.Rectangle, size int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	side := area.Dx()
+
+	for y := 0; y < size; y++ {
+		y0 := area.Min.Y + y*side/size
+		y1 := area.Min.Y + (y+1)*side/size
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < size; x++ {
+			x0 := area.Min.X + x*side/size
+			x1 := area.Min.X + (x+1)*side/size
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			// RGBA() alfa ile çarpılmış değerler döndürür; ortalama da öyle kalır.
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
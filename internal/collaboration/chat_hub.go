@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dione-docs-backend/internal/authz"
 	"github.com/dione-docs-backend/internal/models"
 	"github.com/dione-docs-backend/internal/repository"
 	"github.com/google/uuid"
@@ -22,9 +23,10 @@ type ChatHub struct {
 	broadcast  chan *models.Message
 	mu         sync.Mutex
 	repo       *repository.Repository
+	authorizer *authz.Authorizer
 }
 
-func NewChatHub(docID uuid.UUID, repo *repository.Repository) *ChatHub {
+func NewChatHub(docID uuid.UUID, repo *repository.Repository, authorizer *authz.Authorizer) *ChatHub {
 	return &ChatHub{
 		docID:      docID,
 		clients:    make(map[*ChatClient]bool),
@@ -32,6 +34,7 @@ func NewChatHub(docID uuid.UUID, repo *repository.Repository) *ChatHub {
 		Unregister: make(chan *ChatClient),
 		broadcast:  make(chan *models.Message, 5),
 		repo:       repo,
+		authorizer: authorizer,
 	}
 }
 
@@ -68,50 +71,40 @@ func (h *ChatHub) Run() {
 	}
 }
 
-func (h *ChatHub) processAndBroadcast(incomingMsg IncomingMessage, userID uuid.UUID) {
-	log.Println("--- [DEBUG] processAndBroadcast: Function started. ---")
-	log.Printf("[DEBUG] Incoming message: '%s' from user: %s for doc: %s", incomingMsg.Content, userID, h.docID)
+// DisconnectUser, kullanıcının bu sohbet hub'ındaki tüm bağlantılarını kapatır.
+func (h *ChatHub) DisconnectUser(userID uuid.UUID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 
-	log.Printf("[DEBUG] Step 1: Finding document by ID: %s", h.docID)
+	for client := range h.clients {
+		if client.userID == userID {
+			close(client.send)
+			delete(h.clients, client)
+			log.Printf("Chat Client %s disconnected from hub for doc %s", userID, h.docID)
+		}
+	}
+}
+
+func (h *ChatHub) processAndBroadcast(incomingMsg IncomingMessage, userID uuid.UUID) {
 	var doc models.Document
 	if err := h.repo.Document.GetByID(h.docID, &doc); err != nil {
-		log.Printf("[DEBUG] FATAL: Could not find document. Error: %v", err)
-		log.Println("--- [DEBUG] processAndBroadcast: Exiting due to document not found. ---")
+		log.Printf("Error getting document %s for chat message: %v", h.docID, err)
 		return
 	}
 
-	isOwner := doc.OwnerID == userID
-	canWrite := isOwner
-
-	if !isOwner {
-		log.Println("[DEBUG] Step 3: User is not owner. Checking permissions...")
-		permission, err := h.repo.Permission.GetAcceptedByDocumentAndUser(h.docID, userID)
-		if err != nil {
-			log.Printf("[DEBUG] FATAL: Permission check failed for non-owner. Error: %v", err)
-			log.Println("--- [DEBUG] processAndBroadcast: Exiting due to permission check error. ---")
-			return
-		}
-
-		if permission.AccessType == "editor" {
-			canWrite = true
-			log.Println("[DEBUG] User has 'editor' access. Setting canWrite to true.")
-		}
+	canWrite, err := h.authorizer.Can(userID, authz.ActionComment, &doc)
+	if err != nil {
+		log.Printf("Error checking chat permission of user %s in doc %s: %v", userID, h.docID, err)
+		return
 	}
-
-	log.Printf("[DEBUG] Step 4: Final check. Can user write? %t", canWrite)
 	if !canWrite {
-		log.Println("[DEBUG] FATAL: User does not have write permission.")
-		log.Println("--- [DEBUG] processAndBroadcast: Exiting because canWrite is false. ---")
+		log.Printf("User %s is not allowed to write to chat of doc %s", userID, h.docID)
 		return
 	}
 
-	log.Println("[DEBUG] PERMISSION OK. Proceeding to process message.")
-
-	log.Printf("[DEBUG] Step 5: Finding user details by ID: %s", userID)
 	var user models.User
 	if err := h.repo.User.GetByID(userID, &user); err != nil {
-		log.Printf("[DEBUG] FATAL: Could not find user details in database. Error: %v", err)
-		log.Println("--- [DEBUG] processAndBroadcast: Exiting because user could not be found. ---")
+		log.Printf("Error getting user %s for chat message: %v", userID, err)
 		return
 	}
 
@@ -126,8 +119,7 @@ func (h *ChatHub) processAndBroadcast(incomingMsg IncomingMessage, userID uuid.U
 	}
 
 	if err := h.repo.Message.Create(dbMessage); err != nil {
-		log.Printf("[DEBUG] FATAL: Error saving message to DB. Error: %v", err)
-		log.Println("--- [DEBUG] processAndBroadcast: Exiting because DB save failed. ---")
+		log.Printf("Error saving chat message for doc %s: %v", h.docID, err)
 		return
 	}
 
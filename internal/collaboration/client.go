@@ -4,6 +4,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
@@ -15,18 +16,28 @@ const (
 )
 
 type Client struct {
-	ID   string
-	hub  *Hub
-	conn *websocket.Conn
-	send chan OTOperation
+	ID         string
+	userID     uuid.UUID
+	canEdit    bool // false ise client'ın doğrudan düzenleme operasyonları yok sayılır
+	canSuggest bool // false ise client'ın öneri operasyonları yok sayılır
+	hub        *Hub
+	conn       *websocket.Conn
+	send       chan OTOperation
+	onClose    func() // bağlantı kapanıp hub'dan ayrıldıktan sonra çağrılır
 }
 
-func NewClient(hub *Hub, conn *websocket.Conn, clientID string) {
+// NewClient, bağlantıyı hub'a kaydeder ve okuma/yazma döngülerini başlatır. onClose, bağlantı
+// kapandığında hub'dan ayrıldıktan sonra çağrılır; nil olabilir.
+func NewClient(hub *Hub, conn *websocket.Conn, clientID string, userID uuid.UUID, canEdit, canSuggest bool, onClose func()) {
 	client := &Client{
-		ID:   clientID,
-		hub:  hub,
-		conn: conn,
-		send: make(chan OTOperation, 256),
+		ID:         clientID,
+		userID:     userID,
+		canEdit:    canEdit,
+		canSuggest: canSuggest,
+		hub:        hub,
+		conn:       conn,
+		send:       make(chan OTOperation, 256),
+		onClose:    onClose,
 	}
 	client.hub.Register <- client
 
@@ -38,6 +49,9 @@ func (c *Client) readPump() {
 	defer func() {
 		c.hub.Unregister <- c
 		c.conn.Close()
+		if c.onClose != nil {
+			c.onClose()
+		}
 	}()
 	c.conn.SetReadLimit(maxMessageSize)
 	c.conn.SetReadDeadline(time.Now().Add(pongWait))
@@ -52,7 +66,20 @@ func (c *Client) readPump() {
 			}
 			break
 		}
+		if op.Type == OperationTypeSuggestion {
+			if !c.canSuggest {
+				continue
+			}
+		} else {
+			if !c.canEdit {
+				continue
+			}
+			// Kontrol mesajları yalnızca sunucu tarafından üretilebilir.
+			op.Type = ""
+		}
+		op.Content = nil
 		op.ClientID = c.ID
+		op.authorID = c.userID
 		c.hub.broadcast <- op
 	}
 }
@@ -2,64 +2,132 @@
 package collaboration
 
 import (
+	"encoding/json"
 	"log"
 	"sync"
+	"time"
 
+	"github.com/dione-docs-backend/internal/delta"
 	"github.com/dione-docs-backend/internal/models"
 	"github.com/dione-docs-backend/internal/repository"
 	"github.com/google/uuid"
 )
 
+const (
+	// OperationTypeReload, istemcilere belgeyi Content alanındaki içerikle
+	// baştan yüklemeleri gerektiğini bildirir (ör. versiyon geri yükleme sonrası).
+	OperationTypeReload = "reload"
+
+	// OperationTypeComment, Content alanında bir yorum olayı taşır; belge içeriğini değiştirmez.
+	OperationTypeComment = "comment"
+
+	// OperationTypeSuggestion, öneri modundaki istemcilerden gelen ve belgeye uygulanmadan
+	// öneri olarak saklanan operasyonları, sunucudan giderken de Content alanında bir öneri olayını belirtir.
+	OperationTypeSuggestion = "suggestion"
+
+	// ServerClientID, sunucu tarafında üretilen operasyonların ClientID değeridir.
+	ServerClientID = "server"
+
+	// trackedFlushInterval, bellekte güncellenen yorum çapalarının ve bekleyen önerilerin
+	// veritabanına yazılma aralığıdır.
+	trackedFlushInterval = 2 * time.Second
+)
+
 type OTOperation struct {
-	Version  int           `json:"version"`
-	ClientID string        `json:"clientId"`
-	Ops      []interface{} `json:"ops"`
+	Type     string          `json:"type,omitempty"`
+	Version  int             `json:"version"`
+	ClientID string          `json:"clientId"`
+	Ops      []interface{}   `json:"ops"`
+	Content  json.RawMessage `json:"content,omitempty"`
+
+	authorID uuid.UUID   // operasyonu gönderen kullanıcı; sunucu operasyonlarında uuid.Nil
+	change   delta.Delta // hub sırasına göre yeniden temellendirilmiş değişiklik
+}
+
+// commentAnchor, bir yorum dizisinin bağlı olduğu metin aralığıdır.
+type commentAnchor struct {
+	start  int
+	length int
 }
 
 type Hub struct {
-	docID         uuid.UUID
-	clients       map[*Client]bool
-	Register      chan *Client
-	Unregister    chan *Client
-	broadcast     chan OTOperation
-	mu            sync.Mutex
-	docRepo       repository.DocumentRepository
-	documentState []byte
-	version       int
-	history       []OTOperation
-}
-
-func NewHub(docID uuid.UUID, docRepo repository.DocumentRepository) *Hub {
+	docID            uuid.UUID
+	clients          map[*Client]bool
+	Register         chan *Client
+	Unregister       chan *Client
+	broadcast        chan OTOperation
+	quit             chan struct{}
+	mu               sync.Mutex
+	repo             *repository.Repository
+	documentState    []byte
+	version          int
+	history          []OTOperation
+	anchors          map[uuid.UUID]commentAnchor
+	dirtyAnchors     map[uuid.UUID]bool
+	suggestions      map[uuid.UUID]delta.Delta // bekleyen önerilerin güncel belgeye göre değişiklikleri
+	dirtySuggestions map[uuid.UUID]bool
+	ownerID          uuid.UUID
+	savedVersion     int                        // belgenin kayıtlı son versiyonu
+	baseline         *models.DocumentOperation  // operasyon kaydı boşsa ilk yazılacak temel kayıt
+	operations       []models.DocumentOperation // henüz kaydedilmemiş yazar kayıtları
+}
+
+func NewHub(docID uuid.UUID, repo *repository.Repository) *Hub {
+	hub := &Hub{
+		docID:            docID,
+		clients:          make(map[*Client]bool),
+		Register:         make(chan *Client),
+		Unregister:       make(chan *Client),
+		broadcast:        make(chan OTOperation, 5),
+		quit:             make(chan struct{}),
+		repo:             repo,
+		documentState:    []byte(`{"ops":[{"insert":"\n"}]}`),
+		version:          1,
+		history:          make([]OTOperation, 0),
+		anchors:          make(map[uuid.UUID]commentAnchor),
+		dirtyAnchors:     make(map[uuid.UUID]bool),
+		suggestions:      make(map[uuid.UUID]delta.Delta),
+		dirtySuggestions: make(map[uuid.UUID]bool),
+	}
+
 	var doc models.Document
-	if err := docRepo.GetByID(docID, &doc); err != nil {
+	if err := repo.Document.GetByID(docID, &doc); err != nil {
 		log.Printf("Error getting document for hub %s: %v. Starting with empty doc.", docID, err)
-		return &Hub{
-			docID:         docID,
-			clients:       make(map[*Client]bool),
-			Register:      make(chan *Client),
-			Unregister:    make(chan *Client),
-			broadcast:     make(chan OTOperation, 5),
-			docRepo:       docRepo,
-			documentState: []byte(`{"ops":[{"insert":"\n"}]}`),
-			version:       1,
-			history:       make([]OTOperation, 0),
-		}
+	} else {
+		hub.documentState = doc.Content
+		hub.version = doc.Version
+		hub.ownerID = doc.OwnerID
+		hub.savedVersion = doc.Version
+		hub.baseline = hub.newBaseline()
 	}
 
-	return &Hub{
-		docID:         docID,
-		clients:       make(map[*Client]bool),
-		Register:      make(chan *Client),
-		Unregister:    make(chan *Client),
-		broadcast:     make(chan OTOperation, 5),
-		docRepo:       docRepo,
-		documentState: doc.Content,
-		version:       doc.Version,
-		history:       make([]OTOperation, 0),
+	threads, err := repo.Comment.GetThreads(docID)
+	if err != nil {
+		log.Printf("Error getting comments for hub %s: %v", docID, err)
 	}
+	for _, comment := range threads {
+		hub.anchors[comment.ID] = commentAnchor{start: comment.AnchorStart, length: comment.AnchorLength}
+	}
+
+	pending, err := repo.Suggestion.GetByDocumentID(docID, models.SuggestionStatusPending)
+	if err != nil {
+		log.Printf("Error getting suggestions for hub %s: %v", docID, err)
+	}
+	for _, suggestion := range pending {
+		change, err := delta.Parse(suggestion.Change)
+		if err != nil {
+			log.Printf("Skipping invalid suggestion %s for hub %s: %v", suggestion.ID, docID, err)
+			continue
+		}
+		hub.suggestions[suggestion.ID] = change
+	}
+	return hub
 }
 
 func (h *Hub) Run() {
+	flush := time.NewTicker(trackedFlushInterval)
+	defer flush.Stop()
+
 	for {
 		select {
 		case client := <-h.Register:
@@ -75,24 +143,303 @@ func (h *Hub) Run() {
 				close(client.send)
 				log.Printf("Client %s disconnected from hub for doc %s", client.ID, h.docID)
 			}
+			if len(h.clients) == 0 {
+				h.flushTracked()
+			}
 			h.mu.Unlock()
 
 		case operation := <-h.broadcast:
 			h.mu.Lock()
-			h.version++
-			operation.Version = h.version
-			h.history = append(h.history, operation)
-			for client := range h.clients {
-				if client.ID != operation.ClientID {
-					select {
-					case client.send <- operation:
-					default:
-						close(client.send)
-						delete(h.clients, client)
-					}
-				}
+			if operation.Type == OperationTypeSuggestion {
+				h.addSuggestion(operation)
+			} else {
+				h.apply(operation)
 			}
 			h.mu.Unlock()
+
+		case <-flush.C:
+			h.mu.Lock()
+			h.flushTracked()
+			h.mu.Unlock()
+
+		case <-h.quit:
+			h.mu.Lock()
+			h.flushTracked()
+			h.mu.Unlock()
+			log.Printf("Hub for doc %s stopped", h.docID)
+			return
+		}
+	}
+}
+
+// Stop, hub'ın döngüsünü bekleyen çapa ve öneri değişikliklerini kaydettikten sonra sonlandırır.
+// Hub'a bağlı istemci kalmadığında çağrılmalıdır; bir kez çağrılabilir.
+func (h *Hub) Stop() {
+	close(h.quit)
+}
+
+// apply, operasyona yeni bir versiyon verir, yorum çapalarını, bekleyen önerileri ve yazar
+// kaydını operasyona göre günceller ve operasyonu gönderen dışındaki istemcilere iletir.
+// Çağıran h.mu'yu tutmalıdır.
+func (h *Hub) apply(operation OTOperation) {
+	change, err := h.rebase(operation)
+	if err != nil {
+		log.Printf("Could not parse operation from client %s in doc %s: %v", operation.ClientID, h.docID, err)
+	}
+
+	h.version++
+	operation.Version = h.version
+	operation.change = change
+	h.history = append(h.history, operation)
+	if err == nil {
+		h.transformTracked(change)
+		h.recordOperation(operation.authorID, change)
+	}
+	for client := range h.clients {
+		if client.ID != operation.ClientID {
+			select {
+			case client.send <- operation:
+			default:
+				close(client.send)
+				delete(h.clients, client)
+			}
+		}
+	}
+}
+
+// sendAll, operasyonu bağlı tüm istemcilere gönderir. Çağıran h.mu'yu tutmalıdır.
+func (h *Hub) sendAll(operation OTOperation) {
+	for client := range h.clients {
+		select {
+		case client.send <- operation:
+		default:
+			close(client.send)
+			delete(h.clients, client)
+		}
+	}
+}
+
+// Reload, hub'ın belge durumunu verilen içerik ve versiyonla değiştirir ve
+// bağlı tüm istemcilere belgeyi yeniden yüklemelerini bildirir.
+func (h *Hub) Reload(content []byte, version int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.documentState = content
+	h.version = version
+	h.history = make([]OTOperation, 0)
+	h.savedVersion = version
+	h.baseline = h.newBaseline()
+
+	operation := OTOperation{
+		Type:     OperationTypeReload,
+		Version:  version,
+		ClientID: ServerClientID,
+		Content:  content,
+	}
+	h.sendAll(operation)
+	log.Printf("Hub for doc %s reloaded at version %d", h.docID, version)
+}
+
+// Saved, belgenin hub'daki operasyonları içeren içeriğinin version numarasıyla kaydedildiğini
+// bildirir. Sonraki operasyonlar bir sonraki versiyona atfedilir.
+func (h *Hub) Saved(version int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if version > h.savedVersion {
+		h.savedVersion = version
+	}
+}
+
+// DisconnectUser, kullanıcının bu hub'daki tüm bağlantılarını kapatır.
+func (h *Hub) DisconnectUser(userID uuid.UUID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range h.clients {
+		if client.userID == userID {
+			close(client.send)
+			delete(h.clients, client)
+			log.Printf("Client %s of user %s disconnected from hub for doc %s", client.ID, userID, h.docID)
+		}
+	}
+}
+
+// Notify, belge içeriğini değiştirmeyen bir sunucu olayını (ör. yorum) bağlı tüm istemcilere iletir.
+func (h *Hub) Notify(operationType string, event interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sendEvent(operationType, event)
+}
+
+// AnchorComment, baseVersion versiyonundaki belgeye göre verilmiş yorum çapasını o versiyondan
+// sonra uygulanan operasyonlardan geçirir, yorumu save ile kaydeder ve çapayı izlemeye alır.
+// Kayıt kilit altında yapıldığından arada gelen operasyonlar kaçırılmaz.
+func (h *Hub) AnchorComment(comment *models.Comment, baseVersion int, save func(*models.Comment) error) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, operation := range h.history {
+		if operation.Type != "" || operation.Version <= baseVersion {
+			continue
+		}
+		comment.AnchorStart, comment.AnchorLength = delta.TransformRange(operation.change, comment.AnchorStart, comment.AnchorLength)
+	}
+
+	if err := save(comment); err != nil {
+		return err
+	}
+	if !comment.IsReply() {
+		h.anchors[comment.ID] = commentAnchor{start: comment.AnchorStart, length: comment.AnchorLength}
+	}
+	return nil
+}
+
+// UntrackComment, silinen bir yorum dizisinin çapasını izlemeyi bırakır.
+func (h *Hub) UntrackComment(commentID uuid.UUID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.anchors, commentID)
+	delete(h.dirtyAnchors, commentID)
+}
+
+// Rebase, hub dışında yapılan bir içerik değişikliğini (ör. versiyon geri yükleme) yorum
+// çapalarına ve bekleyen önerilere uygular ve sonucu hemen kaydeder.
+func (h *Hub) Rebase(change delta.Delta) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remapAnchors(change)
+	h.rebaseSuggestions(change)
+	h.flushTracked()
+}
+
+// Flush, bellekte bekleyen çapa, öneri ve yazar kayıtlarını hemen kaydeder.
+func (h *Hub) Flush() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.flushTracked()
+}
+
+// rebase, operasyonu istemcinin gördüğü versiyondan (operation.Version) sonra uygulanmış
+// diğer istemcilerin operasyonlarına göre yeniden temellendirir. İstemcinin kendi önceki
+// operasyonları zaten onun belgesinde olduğundan atlanır.
+func (h *Hub) rebase(operation OTOperation) (delta.Delta, error) {
+	change, err := parseOperation(operation)
+	if err != nil {
+		return delta.Delta{}, err
+	}
+	for _, applied := range h.history {
+		if applied.Type != "" || applied.Version <= operation.Version || applied.ClientID == operation.ClientID {
+			continue
+		}
+		change = delta.Transform(applied.change, change, true)
+	}
+	return change, nil
+}
+
+// transformTracked, uygulanan değişikliği yorum çapalarına ve bekleyen önerilere yansıtır.
+// Çağıran h.mu'yu tutmalıdır.
+func (h *Hub) transformTracked(change delta.Delta) {
+	h.remapAnchors(change)
+	h.rebaseSuggestions(change)
+}
+
+// recordOperation, değişikliği yazarıyla birlikte kaydedilmek üzere sıraya alır. Değişiklik,
+// belgenin kaydedilecek bir sonraki versiyonuna atfedilir. Çağıran h.mu'yu tutmalıdır.
+func (h *Hub) recordOperation(authorID uuid.UUID, change delta.Delta) {
+	if len(change.Ops) == 0 {
+		return
+	}
+	data, err := change.Marshal()
+	if err != nil {
+		log.Printf("Could not encode operation for authorship in doc %s: %v", h.docID, err)
+		return
+	}
+	h.operations = append(h.operations, models.DocumentOperation{
+		DocumentID: h.docID,
+		UserID:     authorID,
+		Version:    h.savedVersion + 1,
+		Change:     data,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// newBaseline, hub'ın yüklediği içeriği operasyon kaydının temeli olarak döndürür. İlk
+// versiyonun içeriği belge sahibine, daha sonrakiler bilinmeyen yazara atfedilir.
+func (h *Hub) newBaseline() *models.DocumentOperation {
+	content, err := delta.ParseDocument(h.documentState)
+	if err != nil {
+		log.Printf("Could not parse content of doc %s for authorship: %v", h.docID, err)
+		return nil
+	}
+	data, err := content.Marshal()
+	if err != nil {
+		return nil
+	}
+
+	author := uuid.Nil
+	if h.savedVersion == 1 {
+		author = h.ownerID
+	}
+	return &models.DocumentOperation{
+		DocumentID: h.docID,
+		UserID:     author,
+		Version:    h.savedVersion,
+		Change:     data,
+	}
+}
+
+func (h *Hub) remapAnchors(change delta.Delta) {
+	for id, anchor := range h.anchors {
+		start, length := delta.TransformRange(change, anchor.start, anchor.length)
+		if start != anchor.start || length != anchor.length {
+			h.anchors[id] = commentAnchor{start: start, length: length}
+			h.dirtyAnchors[id] = true
+		}
+	}
+}
+
+// flushTracked, değişen çapaları, önerileri ve yazar kayıtlarını veritabanına yazar.
+// Çağıran h.mu'yu tutmalıdır.
+func (h *Hub) flushTracked() {
+	if len(h.operations) > 0 {
+		if err := h.repo.Attribution.RecordOperations(h.baseline, h.operations); err != nil {
+			log.Printf("Could not save operations for doc %s: %v", h.docID, err)
+		} else {
+			h.operations = nil
+			h.baseline = nil
+		}
+	}
+	for id := range h.dirtyAnchors {
+		anchor := h.anchors[id]
+		if err := h.repo.Comment.UpdateAnchor(id, anchor.start, anchor.length); err != nil {
+			log.Printf("Could not save comment anchor %s for doc %s: %v", id, h.docID, err)
+			continue
+		}
+		delete(h.dirtyAnchors, id)
+	}
+	for id := range h.dirtySuggestions {
+		change, err := h.suggestions[id].Marshal()
+		if err == nil {
+			err = h.repo.Suggestion.UpdateChange(id, change)
+		}
+		if err != nil {
+			log.Printf("Could not save suggestion %s for doc %s: %v", id, h.docID, err)
+			continue
 		}
+		delete(h.dirtySuggestions, id)
+	}
+}
+
+func parseOperation(operation OTOperation) (delta.Delta, error) {
+	data, err := json.Marshal(map[string]interface{}{"ops": operation.Ops})
+	if err != nil {
+		return delta.Delta{}, err
 	}
+	return delta.Parse(data)
 }
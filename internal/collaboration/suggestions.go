@@ -0,0 +1,119 @@
+package collaboration
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/dione-docs-backend/internal/delta"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// Öneri olaylarının istemcilere iletilen türleri.
+const (
+	SuggestionEventCreated  = "created"
+	SuggestionEventAccepted = "accepted"
+	SuggestionEventRejected = "rejected"
+)
+
+// SuggestionEvent, öneri olaylarının OperationTypeSuggestion operasyonlarının Content
+// alanında istemcilere iletilen biçimidir. İstemciler Change'i izlenen değişiklik olarak gösterir.
+type SuggestionEvent struct {
+	Action     string                  `json:"action"`
+	ID         uuid.UUID               `json:"id"`
+	AuthorID   uuid.UUID               `json:"author_id"`
+	Change     json.RawMessage         `json:"change"`
+	Status     models.SuggestionStatus `json:"status"`
+	ResolvedBy *uuid.UUID              `json:"resolved_by,omitempty"`
+}
+
+func NewSuggestionEvent(action string, suggestion *models.Suggestion) SuggestionEvent {
+	return SuggestionEvent{
+		Action:     action,
+		ID:         suggestion.ID,
+		AuthorID:   suggestion.AuthorID,
+		Change:     suggestion.Change,
+		Status:     suggestion.Status,
+		ResolvedBy: suggestion.ResolvedBy,
+	}
+}
+
+// HasChanges, Delta'nın belgeyi değiştiren bir insert veya delete içerip içermediğini döndürür.
+// Yalnızca retain içeren öneriler (ör. hedef aldığı metin başkası tarafından silinmiş) uygulanacak bir şey taşımaz.
+func HasChanges(d delta.Delta) bool {
+	for _, op := range d.Ops {
+		if op.Insert != nil || op.Delete > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// addSuggestion, öneri modundaki bir istemcinin operasyonunu belgeye uygulamadan bekleyen
+// öneri olarak kaydeder ve bağlı tüm istemcilere iletir. Çağıran h.mu'yu tutmalıdır.
+func (h *Hub) addSuggestion(operation OTOperation) {
+	change, err := parseOperation(operation)
+	if err != nil || !HasChanges(change) {
+		log.Printf("Ignoring invalid suggestion from client %s in doc %s: %v", operation.ClientID, h.docID, err)
+		return
+	}
+
+	// İstemcinin gördüğü versiyondan sonra uygulanan operasyonlara göre yeniden temellendir.
+	for _, applied := range h.history {
+		if applied.Type != "" || applied.Version <= operation.Version {
+			continue
+		}
+		change = delta.Transform(applied.change, change, true)
+	}
+
+	data, err := change.Marshal()
+	if err != nil {
+		log.Printf("Could not encode suggestion in doc %s: %v", h.docID, err)
+		return
+	}
+	suggestion := &models.Suggestion{
+		DocumentID: h.docID,
+		AuthorID:   operation.authorID,
+		Change:     data,
+		Status:     models.SuggestionStatusPending,
+	}
+	if err := h.repo.Suggestion.Create(suggestion); err != nil {
+		log.Printf("Could not save suggestion in doc %s: %v", h.docID, err)
+		return
+	}
+	h.suggestions[suggestion.ID] = change
+
+	h.sendEvent(OperationTypeSuggestion, NewSuggestionEvent(SuggestionEventCreated, suggestion))
+}
+
+// UntrackSuggestion, reddedilen veya kabul edilip belgeye uygulanan öneriyi izlemeyi bırakır.
+func (h *Hub) UntrackSuggestion(suggestionID uuid.UUID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.suggestions, suggestionID)
+	delete(h.dirtySuggestions, suggestionID)
+}
+
+// rebaseSuggestions, uygulanan değişikliği bekleyen önerilere yansıtır. Çağıran h.mu'yu tutmalıdır.
+func (h *Hub) rebaseSuggestions(change delta.Delta) {
+	for id, suggestion := range h.suggestions {
+		h.suggestions[id] = delta.Transform(change, suggestion, true)
+		h.dirtySuggestions[id] = true
+	}
+}
+
+// sendEvent, olayı JSON olarak kodlayıp bağlı tüm istemcilere iletir. Çağıran h.mu'yu tutmalıdır.
+func (h *Hub) sendEvent(operationType string, event interface{}) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Could not encode %s event for doc %s: %v", operationType, h.docID, err)
+		return
+	}
+	h.sendAll(OTOperation{
+		Type:     operationType,
+		Version:  h.version,
+		ClientID: ServerClientID,
+		Content:  payload,
+	})
+}
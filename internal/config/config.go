@@ -1,9 +1,13 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -22,6 +26,59 @@ type Config struct {
 	GoogleClientID     string `mapstructure:"GOOGLE_CLIENT_ID"`
 	GoogleClientSecret string `mapstructure:"GOOGLE_CLIENT_SECRET"`
 	GoogleRedirectURL  string `mapstructure:"GOOGLE_REDIRECT_URL"`
+	// Google uç noktaları; testlerde sahte bir kimlik sağlayıcıya yönlendirilebilir.
+	GoogleAuthURL     string `mapstructure:"GOOGLE_AUTH_URL"`
+	GoogleTokenURL    string `mapstructure:"GOOGLE_TOKEN_URL"`
+	GoogleUserInfoURL string `mapstructure:"GOOGLE_USERINFO_URL"`
+
+	// Versiyon saklama (retention) varsayılanları
+	VersionKeepAllHours      int           `mapstructure:"VERSION_KEEP_ALL_HOURS"`
+	VersionHourlyDays        int           `mapstructure:"VERSION_HOURLY_DAYS"`
+	VersionRetentionInterval time.Duration `mapstructure:"VERSION_RETENTION_INTERVAL"`
+
+	// Süreli izinlerin kontrol edilme sıklığı
+	PermissionExpiryInterval time.Duration `mapstructure:"PERMISSION_EXPIRY_INTERVAL"`
+
+	// E-posta gönderimi. MailDriver "smtp" değilse e-postalar loglanır ve MailDir doluysa diske yazılır.
+	MailDriver   string `mapstructure:"MAIL_DRIVER"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+	MailDir      string `mapstructure:"MAIL_DIR"`
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     string `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+
+	// Erişim token'larını imzalayan anahtarlar. JWTKeysDir'deki her PEM dosyası bir anahtardır
+	// (dosya adı kid); JWTSigningKeyID boşsa adı en son gelen özel anahtar kullanılır.
+	JWTKeysDir      string `mapstructure:"JWT_KEYS_DIR"`
+	JWTSigningKeyID string `mapstructure:"JWT_SIGNING_KEY_ID"`
+	JWTIssuer       string `mapstructure:"JWT_ISSUER"`
+	// JWTAllowEphemeralKey, JWTKeysDir boşken her açılışta yeni bir anahtar üretilmesine izin
+	// verir. Yalnızca geliştirme içindir; yeniden başlatmada tüm oturumlar geçersiz olur.
+	JWTAllowEphemeralKey bool `mapstructure:"JWT_ALLOW_EPHEMERAL_KEY"`
+
+	// Oturumlar: kısa ömürlü erişim token'ları ve her kullanımda değişen yenileme token'ları
+	AccessTokenTTL  time.Duration `mapstructure:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL time.Duration `mapstructure:"REFRESH_TOKEN_TTL"`
+
+	// MFAEncryptionKey, TOTP anahtarlarını veritabanında şifrelemek için kullanılır; tanımsızsa
+	// JWTSecret kullanılır. İkisi de boşsa uygulama açılmaz.
+	MFAEncryptionKey string `mapstructure:"MFA_ENCRYPTION_KEY"`
+	MFAIssuer        string `mapstructure:"MFA_ISSUER"`
+
+	// RateLimitStore, hız sınırı sayaçlarının tutulduğu yerdir: "memory" (varsayılan) veya
+	// birden fazla sunucunun sayaçları paylaştığı "postgres".
+	RateLimitStore string `mapstructure:"RATE_LIMIT_STORE"`
+
+	// TrustedProxies, X-Forwarded-For başlığına güvenilen ters vekil (reverse proxy) adresleri
+	// veya CIDR aralıklarıdır. Boşsa başlık yok sayılır ve istemci IP'si bağlantı adresidir.
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
+
+	// StorageDir, yüklenen dosyaların (avatarlar) yerel blob deposudur.
+	StorageDir string `mapstructure:"STORAGE_DIR"`
+
+	// AppURL, e-postalardaki bağlantılarda kullanılan ön yüz adresidir.
+	AppURL string `mapstructure:"APP_URL"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -41,11 +98,113 @@ func LoadConfig() (*Config, error) {
 		JWTSecret:        os.Getenv("JWT_SECRET"),
 		InternalApiKey:   os.Getenv("INTERNAL_API_KEY"),
 		PythonServiceURL: os.Getenv("PYTHON_SERVICE_URL"),
+
+		GoogleClientID:     os.Getenv("GOOGLE_CLIENT_ID"),
+		GoogleClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
+		GoogleRedirectURL:  os.Getenv("GOOGLE_REDIRECT_URL"),
+		GoogleAuthURL:      getEnv("GOOGLE_AUTH_URL", "https://accounts.google.com/o/oauth2/v2/auth"),
+		GoogleTokenURL:     getEnv("GOOGLE_TOKEN_URL", "https://oauth2.googleapis.com/token"),
+		GoogleUserInfoURL:  getEnv("GOOGLE_USERINFO_URL", "https://openidconnect.googleapis.com/v1/userinfo"),
+
+		VersionKeepAllHours:      getEnvInt("VERSION_KEEP_ALL_HOURS", 24),
+		VersionHourlyDays:        getEnvInt("VERSION_HOURLY_DAYS", 7),
+		VersionRetentionInterval: getEnvDuration("VERSION_RETENTION_INTERVAL", time.Hour),
+
+		PermissionExpiryInterval: getEnvDuration("PERMISSION_EXPIRY_INTERVAL", time.Minute),
+
+		MailDriver:   os.Getenv("MAIL_DRIVER"),
+		MailFrom:     getEnv("MAIL_FROM", "no-reply@dione-docs.local"),
+		MailDir:      os.Getenv("MAIL_DIR"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+
+		JWTKeysDir:      os.Getenv("JWT_KEYS_DIR"),
+		JWTSigningKeyID: os.Getenv("JWT_SIGNING_KEY_ID"),
+		JWTIssuer:       getEnv("JWT_ISSUER", "dione-docs"),
+
+		JWTAllowEphemeralKey: getEnvBool("JWT_ALLOW_EPHEMERAL_KEY", false),
+
+		AccessTokenTTL:  getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		MFAEncryptionKey: getEnv("MFA_ENCRYPTION_KEY", os.Getenv("JWT_SECRET")),
+		MFAIssuer:        getEnv("MFA_ISSUER", "Dione Docs"),
+
+		RateLimitStore: getEnv("RATE_LIMIT_STORE", "memory"),
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+
+		StorageDir: getEnv("STORAGE_DIR", "./data/blobs"),
+
+		AppURL: strings.TrimSuffix(getEnv("APP_URL", "http://localhost:3000"), "/"),
 	}
 
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
 	return config, nil
 }
 
+// validate, güvenlik için zorunlu ayarları kontrol eder. JWT_SECRET parola sıfırlama ve e-posta
+// doğrulama token'larının HMAC anahtarı, MFA_ENCRYPTION_KEY verilmemişse de TOTP anahtarlarının
+// şifreleme anahtarıdır; boş bir anahtarla bu token'lar herkes tarafından üretilebilir.
+func (cfg *Config) validate() error {
+	if cfg.JWTSecret == "" {
+		return errors.New("JWT_SECRET must be set")
+	}
+	if cfg.MFAEncryptionKey == "" {
+		return errors.New("MFA_ENCRYPTION_KEY must be set")
+	}
+	return nil
+}
+
+// getEnv, ortam değişkenini okur; tanımsız veya boşsa varsayılanı döndürür.
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getEnvInt, ortam değişkenini tam sayı olarak okur; tanımsız veya geçersizse varsayılanı döndürür.
+// getEnvList, virgülle ayrılmış ortam değişkenini boş öğeler olmadan döndürür.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvBool, ortam değişkenini "true", "1" gibi bir mantıksal değer olarak okur.
+func getEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvDuration, ortam değişkenini "90m", "1h" gibi bir süre olarak okur.
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
 func (cfg *Config) DBConnectionStringWName() string {
 	return fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s",
 		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBName, cfg.DBPass, cfg.DBSSLMode)
@@ -0,0 +1,388 @@
+// Package delta, Quill Delta formatındaki belge içerikleri ve değişiklikleri üzerinde
+// çalışan yardımcıları içerir. Uzunluklar ve konumlar, editörle uyumlu olması için
+// UTF-16 kod birimi cinsindendir; gömülü (embed) nesneler 1 uzunluğundadır.
+package delta
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"unicode/utf16"
+)
+
+// infinity, iteratör tükendiğinde döndürülen sonsuz retain uzunluğudur.
+const infinity = math.MaxInt
+
+// Attributes, bir op'a uygulanan biçimlendirmedir. nil değer, niteliğin kaldırılması anlamına gelir.
+type Attributes map[string]interface{}
+
+// Op, tek bir Delta işlemidir. Insert bir string veya gömülü nesne (map) olabilir.
+type Op struct {
+	Insert     interface{} `json:"insert,omitempty"`
+	Delete     int         `json:"delete,omitempty"`
+	Retain     int         `json:"retain,omitempty"`
+	Attributes Attributes  `json:"attributes,omitempty"`
+}
+
+// Delta, sıralı op listesidir. Yalnızca insert içeren bir Delta bir belgeyi temsil eder.
+type Delta struct {
+	Ops []Op `json:"ops"`
+}
+
+var ErrNotDocument = errors.New("delta: not a document (contains retain or delete)")
+
+// Parse, {"ops":[...]} biçimindeki JSON'u çözer.
+func Parse(data []byte) (Delta, error) {
+	var d Delta
+	if err := json.Unmarshal(data, &d); err != nil {
+		return Delta{}, err
+	}
+	if d.Ops == nil {
+		return Delta{}, errors.New("delta: missing ops")
+	}
+	for _, op := range d.Ops {
+		if op.Insert == nil && op.Delete <= 0 && op.Retain <= 0 {
+			return Delta{}, errors.New("delta: invalid op")
+		}
+	}
+	return d, nil
+}
+
+// ParseDocument, belge içeriğini çözer. İçe aktarılan belgelerde içerik bir JSON
+// string'i içinde saklandığından bu biçim de kabul edilir. Sonuç yalnızca insert içermelidir.
+func ParseDocument(content []byte) (Delta, error) {
+	d, err := Parse(content)
+	if err != nil {
+		var wrapped string
+		if json.Unmarshal(content, &wrapped) != nil {
+			return Delta{}, err
+		}
+		if d, err = Parse([]byte(wrapped)); err != nil {
+			return Delta{}, err
+		}
+	}
+	if !d.IsDocument() {
+		return Delta{}, ErrNotDocument
+	}
+	return d, nil
+}
+
+// Marshal, Delta'yı {"ops":[...]} biçiminde JSON'a çevirir.
+func (d Delta) Marshal() ([]byte, error) {
+	if d.Ops == nil {
+		d.Ops = []Op{}
+	}
+	return json.Marshal(d)
+}
+
+// IsDocument, Delta'nın yalnızca insert op'larından oluşup oluşmadığını döndürür.
+func (d Delta) IsDocument() bool {
+	for _, op := range d.Ops {
+		if op.Insert == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Length, Delta'nın toplam uzunluğunu döndürür.
+func (d Delta) Length() int {
+	total := 0
+	for _, op := range d.Ops {
+		total += op.Length()
+	}
+	return total
+}
+
+// Length, op'un uzunluğunu döndürür.
+func (op Op) Length() int {
+	switch {
+	case op.Delete > 0:
+		return op.Delete
+	case op.Retain > 0:
+		return op.Retain
+	}
+	if s, ok := op.Insert.(string); ok {
+		return utf16Len(s)
+	}
+	return 1
+}
+
+func (op Op) opType() string {
+	switch {
+	case op.Delete > 0:
+		return "delete"
+	case op.Retain > 0:
+		return "retain"
+	}
+	return "insert"
+}
+
+// Insert, Delta'ya bir insert op'u ekler.
+func (d *Delta) Insert(value interface{}, attrs Attributes) *Delta {
+	if s, ok := value.(string); ok && s == "" {
+		return d
+	}
+	return d.Push(Op{Insert: value, Attributes: attrs})
+}
+
+// Delete, Delta'ya bir delete op'u ekler.
+func (d *Delta) Delete(length int) *Delta {
+	if length <= 0 {
+		return d
+	}
+	return d.Push(Op{Delete: length})
+}
+
+// Retain, Delta'ya bir retain op'u ekler.
+func (d *Delta) Retain(length int, attrs Attributes) *Delta {
+	if length <= 0 {
+		return d
+	}
+	return d.Push(Op{Retain: length, Attributes: attrs})
+}
+
+// Push, op'u Delta'nın sonuna ekler; mümkünse son op ile birleştirir.
+// Quill ile uyumlu olması için insert'ler her zaman delete'lerden önce yer alır.
+func (d *Delta) Push(newOp Op) *Delta {
+	if len(newOp.Attributes) == 0 {
+		newOp.Attributes = nil
+	}
+
+	index := len(d.Ops)
+	if index > 0 {
+		lastOp := &d.Ops[index-1]
+		if newOp.Delete > 0 && lastOp.Delete > 0 {
+			lastOp.Delete += newOp.Delete
+			return d
+		}
+		if lastOp.Delete > 0 && newOp.Insert != nil {
+			index--
+			if index == 0 {
+				d.Ops = append([]Op{newOp}, d.Ops...)
+				return d
+			}
+			lastOp = &d.Ops[index-1]
+		}
+		if reflect.DeepEqual(newOp.Attributes, lastOp.Attributes) {
+			newText, newIsText := newOp.Insert.(string)
+			lastText, lastIsText := lastOp.Insert.(string)
+			if newIsText && lastIsText {
+				lastOp.Insert = lastText + newText
+				return d
+			}
+			if newOp.Retain > 0 && lastOp.Retain > 0 {
+				lastOp.Retain += newOp.Retain
+				return d
+			}
+		}
+	}
+
+	if index == len(d.Ops) {
+		d.Ops = append(d.Ops, newOp)
+	} else {
+		d.Ops = append(d.Ops, Op{})
+		copy(d.Ops[index+1:], d.Ops[index:])
+		d.Ops[index] = newOp
+	}
+	return d
+}
+
+// Chop, sondaki niteliksiz retain op'unu kaldırır.
+func (d *Delta) Chop() *Delta {
+	if n := len(d.Ops); n > 0 {
+		last := d.Ops[n-1]
+		if last.Retain > 0 && last.Attributes == nil {
+			d.Ops = d.Ops[:n-1]
+		}
+	}
+	return d
+}
+
+// Compose, a'nın ardından b uygulanmış gibi tek bir Delta döndürür.
+func Compose(a, b Delta) Delta {
+	thisIter := newIterator(a.Ops)
+	otherIter := newIterator(b.Ops)
+	result := Delta{Ops: []Op{}}
+
+	// Baştaki niteliksiz retain, a'nın insert'lerini olduğu gibi kopyalayarak atlanabilir.
+	if first, ok := otherIter.peek(); ok && first.Retain > 0 && first.Attributes == nil {
+		firstLeft := first.Retain
+		for thisIter.peekType() == "insert" && thisIter.peekLength() <= firstLeft {
+			firstLeft -= thisIter.peekLength()
+			result.Ops = append(result.Ops, thisIter.next(infinity))
+		}
+		if first.Retain-firstLeft > 0 {
+			otherIter.next(first.Retain - firstLeft)
+		}
+	}
+
+	for thisIter.hasNext() || otherIter.hasNext() {
+		switch {
+		case otherIter.peekType() == "insert":
+			result.Push(otherIter.next(infinity))
+		case thisIter.peekType() == "delete":
+			result.Push(thisIter.next(infinity))
+		default:
+			length := min(thisIter.peekLength(), otherIter.peekLength())
+			thisOp := thisIter.next(length)
+			otherOp := otherIter.next(length)
+			if otherOp.Retain > 0 {
+				newOp := Op{}
+				if thisOp.Retain > 0 {
+					newOp.Retain = length
+				} else {
+					newOp.Insert = thisOp.Insert
+				}
+				newOp.Attributes = composeAttributes(thisOp.Attributes, otherOp.Attributes, thisOp.Retain > 0)
+				result.Push(newOp)
+			} else if otherOp.Delete > 0 && thisOp.Retain > 0 {
+				result.Push(otherOp)
+			}
+		}
+	}
+	result.Chop()
+	return result
+}
+
+// Transform, b'yi a'dan sonra uygulanabilecek şekilde dönüştürür. priority true ise
+// aynı konumdaki insert'lerde a önceliklidir (a'nın insert'i önce gelir).
+func Transform(a, b Delta, priority bool) Delta {
+	thisIter := newIterator(a.Ops)
+	otherIter := newIterator(b.Ops)
+	result := Delta{Ops: []Op{}}
+
+	for thisIter.hasNext() || otherIter.hasNext() {
+		switch {
+		case thisIter.peekType() == "insert" && (priority || otherIter.peekType() != "insert"):
+			result.Retain(thisIter.next(infinity).Length(), nil)
+		case otherIter.peekType() == "insert":
+			result.Push(otherIter.next(infinity))
+		default:
+			length := min(thisIter.peekLength(), otherIter.peekLength())
+			thisOp := thisIter.next(length)
+			otherOp := otherIter.next(length)
+			if thisOp.Delete > 0 {
+				continue
+			}
+			if otherOp.Delete > 0 {
+				result.Push(otherOp)
+			} else {
+				result.Retain(length, transformAttributes(thisOp.Attributes, otherOp.Attributes, priority))
+			}
+		}
+	}
+	result.Chop()
+	return result
+}
+
+// TransformPosition, d uygulandıktan sonra index konumunun yeni değerini döndürür.
+// priority true ise tam konumdaki insert'ler index'i ileri kaydırmaz.
+func TransformPosition(d Delta, index int, priority bool) int {
+	iter := newIterator(d.Ops)
+	offset := 0
+	for iter.hasNext() && offset <= index {
+		length := iter.peekLength()
+		nextType := iter.peekType()
+		iter.next(infinity)
+		if nextType == "delete" {
+			index -= min(length, index-offset)
+			continue
+		}
+		if nextType == "insert" && (offset < index || !priority) {
+			index += length
+		}
+		offset += length
+	}
+	return index
+}
+
+// TransformRange, [start, start+length) aralığının d uygulandıktan sonraki karşılığını döndürür.
+// Aralığın sınırlarına yapılan insert'ler aralığa dahil edilmez; aralığın tamamı silinirse
+// uzunluk 0 olur.
+func TransformRange(d Delta, start, length int) (int, int) {
+	newStart := TransformPosition(d, start, false)
+	newEnd := TransformPosition(d, start+length, true)
+	return newStart, max(newEnd-newStart, 0)
+}
+
+func composeAttributes(a, b Attributes, keepNull bool) Attributes {
+	attributes := Attributes{}
+	for key, value := range b {
+		if value == nil && !keepNull {
+			continue
+		}
+		attributes[key] = value
+	}
+	for key, value := range a {
+		if _, ok := b[key]; !ok && value != nil {
+			attributes[key] = value
+		}
+	}
+	if len(attributes) == 0 {
+		return nil
+	}
+	return attributes
+}
+
+func transformAttributes(a, b Attributes, priority bool) Attributes {
+	if a == nil || b == nil {
+		return b
+	}
+	if !priority {
+		return b
+	}
+	attributes := Attributes{}
+	for key, value := range b {
+		if _, ok := a[key]; !ok {
+			attributes[key] = value
+		}
+	}
+	if len(attributes) == 0 {
+		return nil
+	}
+	return attributes
+}
+
+func diffAttributes(a, b Attributes) Attributes {
+	attributes := Attributes{}
+	for key, value := range a {
+		if other, ok := b[key]; !ok {
+			attributes[key] = nil
+		} else if !reflect.DeepEqual(value, other) {
+			attributes[key] = other
+		}
+	}
+	for key, value := range b {
+		if _, ok := a[key]; !ok {
+			attributes[key] = value
+		}
+	}
+	if len(attributes) == 0 {
+		return nil
+	}
+	return attributes
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// sliceUTF16, s'nin UTF-16 cinsinden [offset, offset+length) aralığını döndürür.
+func sliceUTF16(s string, offset, length int) string {
+	units := utf16.Encode([]rune(s))
+	end := offset + length
+	if end > len(units) {
+		end = len(units)
+	}
+	return string(utf16.Decode(units[offset:end]))
+}
@@ -0,0 +1,478 @@
+package delta
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func doc(text string) Delta {
+	d := Delta{Ops: []Op{}}
+	d.Insert(text, nil)
+	return d
+}
+
+func mustParse(t *testing.T, data string) Delta {
+	t.Helper()
+	d, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse(%s): %v", data, err)
+	}
+	return d
+}
+
+func marshal(t *testing.T, d Delta) string {
+	t.Helper()
+	data, err := d.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return string(data)
+}
+
+func assertDelta(t *testing.T, got Delta, want string) {
+	t.Helper()
+	if g := marshal(t, got); g != want {
+		t.Errorf("got %s, want %s", g, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"document", `{"ops":[{"insert":"abc\n"}]}`, false},
+		{"change", `{"ops":[{"retain":2},{"delete":1}]}`, false},
+		{"empty ops", `{"ops":[]}`, false},
+		{"missing ops", `{}`, true},
+		{"empty op", `{"ops":[{}]}`, true},
+		{"invalid json", `{"ops":`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseDocument(t *testing.T) {
+	d, err := ParseDocument([]byte(`"{\"ops\":[{\"insert\":\"wrapped\\n\"}]}"`))
+	if err != nil {
+		t.Fatalf("ParseDocument(wrapped): %v", err)
+	}
+	assertDelta(t, d, `{"ops":[{"insert":"wrapped\n"}]}`)
+
+	if _, err := ParseDocument([]byte(`{"ops":[{"retain":1}]}`)); err != ErrNotDocument {
+		t.Fatalf("ParseDocument(change) error = %v, want %v", err, ErrNotDocument)
+	}
+}
+
+func TestLength(t *testing.T) {
+	d := mustParse(t, `{"ops":[{"insert":"a😀"},{"insert":{"image":"x.png"}},{"retain":3},{"delete":2}]}`)
+	// "a" 1, emoji 2 (vekil çift), embed 1, retain 3, delete 2
+	if got := d.Length(); got != 9 {
+		t.Fatalf("Length = %d, want 9", got)
+	}
+}
+
+func TestPush(t *testing.T) {
+	d := Delta{}
+	d.Insert("ab", nil).Insert("cd", nil).Retain(2, nil).Retain(3, nil).Delete(1).Delete(2)
+	assertDelta(t, d, `{"ops":[{"insert":"abcd"},{"retain":5},{"delete":3}]}`)
+
+	// Insert'ler delete'lerden önce gelir.
+	d = Delta{}
+	d.Retain(1, nil).Delete(2).Insert("x", nil)
+	assertDelta(t, d, `{"ops":[{"retain":1},{"insert":"x"},{"delete":2}]}`)
+
+	// Farklı nitelikli insert'ler birleşmez.
+	d = Delta{}
+	d.Insert("a", Attributes{"bold": true}).Insert("b", nil)
+	assertDelta(t, d, `{"ops":[{"insert":"a","attributes":{"bold":true}},{"insert":"b"}]}`)
+}
+
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "insert into document",
+			a:    `{"ops":[{"insert":"Hello\n"}]}`,
+			b:    `{"ops":[{"retain":5},{"insert":" world"}]}`,
+			want: `{"ops":[{"insert":"Hello world\n"}]}`,
+		},
+		{
+			name: "delete from document",
+			a:    `{"ops":[{"insert":"Hello world\n"}]}`,
+			b:    `{"ops":[{"retain":5},{"delete":6}]}`,
+			want: `{"ops":[{"insert":"Hello\n"}]}`,
+		},
+		{
+			name: "format document",
+			a:    `{"ops":[{"insert":"Hello\n"}]}`,
+			b:    `{"ops":[{"retain":5,"attributes":{"bold":true}}]}`,
+			want: `{"ops":[{"insert":"Hello","attributes":{"bold":true}},{"insert":"\n"}]}`,
+		},
+		{
+			name: "remove format",
+			a:    `{"ops":[{"insert":"Hi","attributes":{"bold":true,"italic":true}}]}`,
+			b:    `{"ops":[{"retain":2,"attributes":{"bold":null}}]}`,
+			want: `{"ops":[{"insert":"Hi","attributes":{"italic":true}}]}`,
+		},
+		{
+			name: "delete inserted text",
+			a:    `{"ops":[{"retain":1},{"insert":"abc"}]}`,
+			b:    `{"ops":[{"retain":2},{"delete":1}]}`,
+			want: `{"ops":[{"retain":1},{"insert":"ac"}]}`,
+		},
+		{
+			name: "two changes",
+			a:    `{"ops":[{"retain":3},{"delete":2}]}`,
+			b:    `{"ops":[{"retain":1},{"insert":"x"}]}`,
+			want: `{"ops":[{"retain":1},{"insert":"x"},{"retain":2},{"delete":2}]}`,
+		},
+		{
+			name: "keep null attribute on retain",
+			a:    `{"ops":[{"retain":2}]}`,
+			b:    `{"ops":[{"retain":2,"attributes":{"bold":null}}]}`,
+			want: `{"ops":[{"retain":2,"attributes":{"bold":null}}]}`,
+		},
+		{
+			name: "embed",
+			a:    `{"ops":[{"insert":{"image":"a.png"}},{"insert":"\n"}]}`,
+			b:    `{"ops":[{"delete":1},{"insert":"x"}]}`,
+			want: `{"ops":[{"insert":"x\n"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDelta(t, Compose(mustParse(t, tt.a), mustParse(t, tt.b)), tt.want)
+		})
+	}
+}
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		priority bool
+		want     string
+	}{
+		{
+			name:     "insert vs insert with priority",
+			a:        `{"ops":[{"insert":"A"}]}`,
+			b:        `{"ops":[{"insert":"B"}]}`,
+			priority: true,
+			want:     `{"ops":[{"retain":1},{"insert":"B"}]}`,
+		},
+		{
+			name:     "insert vs insert without priority",
+			a:        `{"ops":[{"insert":"A"}]}`,
+			b:        `{"ops":[{"insert":"B"}]}`,
+			priority: false,
+			want:     `{"ops":[{"insert":"B"}]}`,
+		},
+		{
+			name: "insert before delete",
+			a:    `{"ops":[{"insert":"A"}]}`,
+			b:    `{"ops":[{"delete":1}]}`,
+			want: `{"ops":[{"retain":1},{"delete":1}]}`,
+		},
+		{
+			name: "delete vs delete",
+			a:    `{"ops":[{"retain":1},{"delete":2}]}`,
+			b:    `{"ops":[{"retain":2},{"delete":2}]}`,
+			want: `{"ops":[{"retain":1},{"delete":1}]}`,
+		},
+		{
+			name: "delete before insert",
+			a:    `{"ops":[{"delete":2}]}`,
+			b:    `{"ops":[{"retain":3},{"insert":"x"}]}`,
+			want: `{"ops":[{"retain":1},{"insert":"x"}]}`,
+		},
+		{
+			name:     "conflicting formats with priority",
+			a:        `{"ops":[{"retain":2,"attributes":{"bold":true,"color":"red"}}]}`,
+			b:        `{"ops":[{"retain":2,"attributes":{"bold":false,"italic":true}}]}`,
+			priority: true,
+			want:     `{"ops":[{"retain":2,"attributes":{"italic":true}}]}`,
+		},
+		{
+			name:     "conflicting formats without priority",
+			a:        `{"ops":[{"retain":2,"attributes":{"bold":true}}]}`,
+			b:        `{"ops":[{"retain":2,"attributes":{"bold":false}}]}`,
+			priority: false,
+			want:     `{"ops":[{"retain":2,"attributes":{"bold":false}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertDelta(t, Transform(mustParse(t, tt.a), mustParse(t, tt.b), tt.priority), tt.want)
+		})
+	}
+}
+
+// TestTransformConvergence, aynı belgeye eşzamanlı uygulanan iki değişikliğin dönüştürüldükten
+// sonra her iki sırada da aynı belgeyi ürettiğini doğrular.
+func TestTransformConvergence(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		base := doc(randomText(rng, 12))
+		a := randomChange(rng, base.Length())
+		b := randomChange(rng, base.Length())
+
+		left := Compose(Compose(base, a), Transform(a, b, true))
+		right := Compose(Compose(base, b), Transform(b, a, false))
+		if l, r := marshal(t, left), marshal(t, right); l != r {
+			t.Fatalf("diverged on base=%s a=%s b=%s: %s != %s",
+				marshal(t, base), marshal(t, a), marshal(t, b), l, r)
+		}
+	}
+}
+
+func TestTransformPosition(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   string
+		index    int
+		priority bool
+		want     int
+	}{
+		{"insert before", `{"ops":[{"retain":1},{"insert":"ab"}]}`, 3, false, 5},
+		{"insert after", `{"ops":[{"retain":4},{"insert":"ab"}]}`, 3, false, 3},
+		{"insert at without priority", `{"ops":[{"retain":3},{"insert":"ab"}]}`, 3, false, 5},
+		{"insert at with priority", `{"ops":[{"retain":3},{"insert":"ab"}]}`, 3, true, 3},
+		{"delete before", `{"ops":[{"delete":2}]}`, 3, false, 1},
+		{"delete around", `{"ops":[{"retain":1},{"delete":4}]}`, 3, false, 1},
+		{"delete after", `{"ops":[{"retain":3},{"delete":2}]}`, 3, false, 3},
+		{"retain only", `{"ops":[{"retain":10,"attributes":{"bold":true}}]}`, 3, false, 3},
+		{"surrogate pair", `{"ops":[{"insert":"😀"}]}`, 0, false, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TransformPosition(mustParse(t, tt.change), tt.index, tt.priority); got != tt.want {
+				t.Fatalf("TransformPosition = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformRange(t *testing.T) {
+	insert := mustParse(t, `{"ops":[{"retain":2},{"insert":"xx"}]}`)
+	del := mustParse(t, `{"ops":[{"retain":1},{"delete":4}]}`)
+
+	tests := []struct {
+		name          string
+		change        Delta
+		start, length int
+		wantStart     int
+		wantLength    int
+	}{
+		{"insert at start stays outside", insert, 2, 3, 4, 3},
+		{"insert at end stays outside", insert, 0, 2, 0, 2},
+		{"insert inside grows range", insert, 1, 3, 1, 5},
+		{"insert before shifts range", insert, 5, 0, 7, 0},
+		{"range fully deleted", del, 2, 2, 1, 0},
+		{"delete overlaps start", del, 0, 3, 0, 1},
+		{"delete overlaps end", del, 3, 5, 1, 3},
+		{"delete before shifts range", del, 6, 1, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, length := TransformRange(tt.change, tt.start, tt.length)
+			if start != tt.wantStart || length != tt.wantLength {
+				t.Fatalf("TransformRange = (%d, %d), want (%d, %d)", start, length, tt.wantStart, tt.wantLength)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "equal",
+			a:    `{"ops":[{"insert":"same\n"}]}`,
+			b:    `{"ops":[{"insert":"same\n"}]}`,
+			want: `{"ops":[]}`,
+		},
+		{
+			name: "insert",
+			a:    `{"ops":[{"insert":"ac\n"}]}`,
+			b:    `{"ops":[{"insert":"abc\n"}]}`,
+			want: `{"ops":[{"retain":1},{"insert":"b"}]}`,
+		},
+		{
+			name: "delete",
+			a:    `{"ops":[{"insert":"abc\n"}]}`,
+			b:    `{"ops":[{"insert":"ac\n"}]}`,
+			want: `{"ops":[{"retain":1},{"delete":1}]}`,
+		},
+		{
+			name: "format",
+			a:    `{"ops":[{"insert":"ab\n"}]}`,
+			b:    `{"ops":[{"insert":"a"},{"insert":"b","attributes":{"bold":true}},{"insert":"\n"}]}`,
+			want: `{"ops":[{"retain":1},{"retain":1,"attributes":{"bold":true}}]}`,
+		},
+		{
+			name: "remove format",
+			a:    `{"ops":[{"insert":"ab","attributes":{"bold":true}}]}`,
+			b:    `{"ops":[{"insert":"ab"}]}`,
+			want: `{"ops":[{"retain":2,"attributes":{"bold":null}}]}`,
+		},
+		{
+			name: "replace embed",
+			a:    `{"ops":[{"insert":{"image":"a.png"}},{"insert":"\n"}]}`,
+			b:    `{"ops":[{"insert":{"image":"b.png"}},{"insert":"\n"}]}`,
+			want: `{"ops":[{"insert":{"image":"b.png"}},{"delete":1}]}`,
+		},
+		{
+			name: "surrogate pair",
+			a:    `{"ops":[{"insert":"a😀b"}]}`,
+			b:    `{"ops":[{"insert":"ab"}]}`,
+			want: `{"ops":[{"retain":1},{"delete":2}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := mustParse(t, tt.a), mustParse(t, tt.b)
+			diff, err := Diff(a, b)
+			if err != nil {
+				t.Fatalf("Diff: %v", err)
+			}
+			assertDelta(t, diff, tt.want)
+			if got, want := marshal(t, Compose(a, diff)), marshal(t, b); got != want {
+				t.Fatalf("Compose(a, Diff(a, b)) = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestDiffRejectsChanges(t *testing.T) {
+	change := mustParse(t, `{"ops":[{"retain":1}]}`)
+	if _, err := Diff(change, doc("a")); err != ErrNotDocument {
+		t.Fatalf("Diff error = %v, want %v", err, ErrNotDocument)
+	}
+}
+
+// TestDiffMinimal, rastgele metinlerde Diff'in hem doğru hem de en kısa düzenlemeyi
+// ürettiğini en uzun ortak alt dizi ile karşılaştırarak doğrular.
+func TestDiffMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 1000; i++ {
+		textA, textB := randomText(rng, 20), randomText(rng, 20)
+		a, b := doc(textA), doc(textB)
+
+		diff, err := Diff(a, b)
+		if err != nil {
+			t.Fatalf("Diff: %v", err)
+		}
+		if got, want := marshal(t, Compose(a, diff)), marshal(t, b); got != want {
+			t.Fatalf("Compose(%q, Diff) = %s, want %s", textA, got, want)
+		}
+
+		edits := 0
+		for _, op := range diff.Ops {
+			if op.Retain == 0 {
+				edits += op.Length()
+			}
+		}
+		if want := len(textA) + len(textB) - 2*lcs(textA, textB); edits != want {
+			t.Fatalf("Diff(%q, %q) has %d edits, want %d", textA, textB, edits, want)
+		}
+	}
+}
+
+func TestDiffLargeEdit(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	textA := randomText(rng, 3*maxEditDistance)
+	textB := randomText(rng, 3*maxEditDistance)
+	a, b := doc("intro "+textA+"\n"), doc("intro "+textB+"\n")
+
+	diff, err := Diff(a, b)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if got, want := marshal(t, Compose(a, diff)), marshal(t, b); got != want {
+		t.Fatal("Compose(a, Diff(a, b)) does not rebuild b")
+	}
+}
+
+func TestDiffLongDocumentSmallEdit(t *testing.T) {
+	text := strings.Repeat("lorem ipsum dolor sit amet ", 20000)
+	a := doc(text)
+	b := doc(text[:len(text)/2] + "EDIT" + text[len(text)/2+3:])
+
+	diff, err := Diff(a, b)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if got, want := marshal(t, Compose(a, diff)), marshal(t, b); got != want {
+		t.Fatal("Compose(a, Diff(a, b)) does not rebuild b")
+	}
+	if len(diff.Ops) > 4 {
+		t.Fatalf("Diff produced %d ops, want a local edit", len(diff.Ops))
+	}
+}
+
+func randomText(rng *rand.Rand, maxLen int) string {
+	const alphabet = "abc d"
+	n := rng.Intn(maxLen + 1)
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		sb.WriteByte(alphabet[rng.Intn(len(alphabet))])
+	}
+	return sb.String()
+}
+
+// randomChange, length uzunluğundaki bir belgeye uygulanabilecek rastgele bir değişiklik üretir.
+func randomChange(rng *rand.Rand, length int) Delta {
+	change := Delta{Ops: []Op{}}
+	for remaining := length; remaining > 0; {
+		n := 1 + rng.Intn(remaining)
+		switch rng.Intn(4) {
+		case 0:
+			change.Insert(randomText(rng, 3), nil)
+		case 1:
+			change.Delete(n)
+			remaining -= n
+		case 2:
+			change.Retain(n, Attributes{"bold": rng.Intn(2) == 0})
+			remaining -= n
+		default:
+			change.Retain(n, nil)
+			remaining -= n
+		}
+	}
+	if rng.Intn(2) == 0 {
+		change.Insert(randomText(rng, 3), nil)
+	}
+	change.Chop()
+	return change
+}
+
+func lcs(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				cur[j] = prev[j-1] + 1
+			} else {
+				cur[j] = max(prev[j], cur[j-1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
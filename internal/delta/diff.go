@@ -0,0 +1,256 @@
+package delta
+
+import "reflect"
+
+// embedPlaceholder, metin karşılaştırmasında gömülü nesneleri temsil eden karakterdir.
+const embedPlaceholder = '\x00'
+
+// maxEditDistance, Myers algoritmasının arayacağı en fazla düzenleme sayısıdır ve çalışma
+// süresini sınırlar. Aşılırsa farklı bölüm tamamen silinip yeniden eklenmiş kabul edilir;
+// sonuç yine doğrudur ancak en küçük fark olmayabilir.
+const maxEditDistance = 4000
+
+type diffKind int
+
+const (
+	diffEqual diffKind = iota
+	diffInsert
+	diffDelete
+)
+
+type diffComponent struct {
+	kind   diffKind
+	length int // UTF-16 kod birimi cinsinden
+}
+
+// Diff, a belgesini b belgesine dönüştüren Delta'yı döndürür. İki Delta da
+// yalnızca insert içermelidir; Compose(a, Diff(a, b)) b'ye eşittir.
+func Diff(a, b Delta) (Delta, error) {
+	if !a.IsDocument() || !b.IsDocument() {
+		return Delta{}, ErrNotDocument
+	}
+
+	result := Delta{Ops: []Op{}}
+	if reflect.DeepEqual(a.Ops, b.Ops) {
+		return result, nil
+	}
+
+	thisIter := newIterator(a.Ops)
+	otherIter := newIterator(b.Ops)
+	for _, component := range diffRunes(documentRunes(a), documentRunes(b)) {
+		length := component.length
+		for length > 0 {
+			var opLength int
+			switch component.kind {
+			case diffInsert:
+				opLength = min(otherIter.peekLength(), length)
+				result.Push(otherIter.next(opLength))
+			case diffDelete:
+				opLength = min(length, thisIter.peekLength())
+				thisIter.next(opLength)
+				result.Delete(opLength)
+			case diffEqual:
+				opLength = min(thisIter.peekLength(), otherIter.peekLength(), length)
+				thisOp := thisIter.next(opLength)
+				otherOp := otherIter.next(opLength)
+				if reflect.DeepEqual(thisOp.Insert, otherOp.Insert) {
+					result.Retain(opLength, diffAttributes(thisOp.Attributes, otherOp.Attributes))
+				} else {
+					result.Push(otherOp)
+					result.Delete(opLength)
+				}
+			}
+			length -= opLength
+		}
+	}
+	result.Chop()
+	return result, nil
+}
+
+// documentRunes, belgeyi karşılaştırılabilir bir rune dizisine çevirir.
+func documentRunes(d Delta) []rune {
+	var runes []rune
+	for _, op := range d.Ops {
+		if text, ok := op.Insert.(string); ok {
+			runes = append(runes, []rune(text)...)
+		} else {
+			runes = append(runes, embedPlaceholder)
+		}
+	}
+	return runes
+}
+
+func runesUTF16Len(runes []rune) int {
+	n := 0
+	for _, r := range runes {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// diffRunes, iki rune dizisi arasındaki farkı eşit/ekleme/silme parçaları olarak döndürür.
+func diffRunes(a, b []rune) []diffComponent {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var components []diffComponent
+	appendComponent := func(kind diffKind, runes []rune) {
+		length := runesUTF16Len(runes)
+		if length == 0 {
+			return
+		}
+		if n := len(components); n > 0 && components[n-1].kind == kind {
+			components[n-1].length += length
+			return
+		}
+		components = append(components, diffComponent{kind: kind, length: length})
+	}
+
+	appendComponent(diffEqual, a[:prefix])
+	for _, edit := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		appendComponent(edit.kind, edit.runes)
+	}
+	appendComponent(diffEqual, a[len(a)-suffix:])
+	return components
+}
+
+type runeEdit struct {
+	kind  diffKind
+	runes []rune
+}
+
+// myers, Myers'in doğrusal bellekli O(ND) algoritmasıyla a'dan b'ye en kısa düzenleme dizisini
+// bulur: orta yılan (middle snake) bulunur, iki yarı ayrı ayrı çözülür. Bellek kullanımı O(n+m)'dir.
+func myers(a, b []rune) []runeEdit {
+	n, m := len(a), len(b)
+	switch {
+	case n == 0 && m == 0:
+		return nil
+	case n == 0:
+		return []runeEdit{{kind: diffInsert, runes: b}}
+	case m == 0:
+		return []runeEdit{{kind: diffDelete, runes: a}}
+	}
+
+	x, y, ok := middleSnake(a, b)
+	if !ok || (x == 0 && y == 0) || (x == n && y == m) {
+		return []runeEdit{{kind: diffDelete, runes: a}, {kind: diffInsert, runes: b}}
+	}
+	return append(myersTrimmed(a[:x], b[:y]), myersTrimmed(a[x:], b[y:])...)
+}
+
+// myersTrimmed, ortak baş ve son ekleri eşit parça olarak ayırıp kalanını myers ile çözer.
+func myersTrimmed(a, b []rune) []runeEdit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var edits []runeEdit
+	if prefix > 0 {
+		edits = append(edits, runeEdit{kind: diffEqual, runes: a[:prefix]})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	if suffix > 0 {
+		edits = append(edits, runeEdit{kind: diffEqual, runes: a[len(a)-suffix:]})
+	}
+	return edits
+}
+
+// middleSnake, a ile b arasındaki en kısa düzenleme yolunun ortasındaki noktayı, ileri ve geri
+// aramaları aynı anda yürüterek bulur. Yol maxEditDistance düzenlemeden uzunsa ok false döner.
+func middleSnake(a, b []rune) (x, y int, ok bool) {
+	n, m := len(a), len(b)
+	maxD := min((n+m+1)/2, maxEditDistance/2+1)
+	offset := maxD
+	size := 2*maxD + 2
+	forward := make([]int, size)
+	backward := make([]int, size)
+	for i := range forward {
+		forward[i] = -1
+		backward[i] = -1
+	}
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	delta := n - m
+	// Toplam uzunluk tekse yollar ileri aramada, çiftse geri aramada kesişir.
+	front := delta%2 != 0
+	// Izgaranın dışına taşan köşegenler sonraki adımlarda atlanır.
+	var forwardStart, forwardEnd, backwardStart, backwardEnd int
+
+	for d := 0; d < maxD; d++ {
+		for k := -d + forwardStart; k <= d-forwardEnd; k += 2 {
+			i := offset + k
+			var x1 int
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x1 = forward[i+1]
+			} else {
+				x1 = forward[i-1] + 1
+			}
+			y1 := x1 - k
+			for x1 < n && y1 < m && a[x1] == b[y1] {
+				x1++
+				y1++
+			}
+			forward[i] = x1
+			switch {
+			case x1 > n:
+				forwardEnd += 2
+			case y1 > m:
+				forwardStart += 2
+			case front:
+				j := offset + delta - k
+				if j >= 0 && j < size && backward[j] != -1 && x1 >= n-backward[j] {
+					return x1, y1, true
+				}
+			}
+		}
+
+		for k := -d + backwardStart; k <= d-backwardEnd; k += 2 {
+			i := offset + k
+			var x2 int
+			if k == -d || (k != d && backward[i-1] < backward[i+1]) {
+				x2 = backward[i+1]
+			} else {
+				x2 = backward[i-1] + 1
+			}
+			y2 := x2 - k
+			for x2 < n && y2 < m && a[n-x2-1] == b[m-y2-1] {
+				x2++
+				y2++
+			}
+			backward[i] = x2
+			switch {
+			case x2 > n:
+				backwardEnd += 2
+			case y2 > m:
+				backwardStart += 2
+			case !front:
+				j := offset + delta - k
+				if j >= 0 && j < size && forward[j] != -1 {
+					x1 := forward[j]
+					y1 := offset + x1 - j
+					if x1 >= n-x2 {
+						return x1, y1, true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
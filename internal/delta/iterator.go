@@ -0,0 +1,66 @@
+package delta
+
+// iterator, op listesinde istenen uzunlukta parçalar alarak ilerler.
+type iterator struct {
+	ops    []Op
+	index  int
+	offset int
+}
+
+func newIterator(ops []Op) *iterator {
+	return &iterator{ops: ops}
+}
+
+func (it *iterator) hasNext() bool {
+	return it.peekLength() < infinity
+}
+
+func (it *iterator) peek() (Op, bool) {
+	if it.index < len(it.ops) {
+		return it.ops[it.index], true
+	}
+	return Op{}, false
+}
+
+func (it *iterator) peekLength() int {
+	if op, ok := it.peek(); ok {
+		return op.Length() - it.offset
+	}
+	return infinity
+}
+
+func (it *iterator) peekType() string {
+	if op, ok := it.peek(); ok {
+		return op.opType()
+	}
+	return "retain"
+}
+
+// next, en fazla length uzunluğunda bir op döndürür. Liste tükenmişse sonsuz retain döner.
+func (it *iterator) next(length int) Op {
+	op, ok := it.peek()
+	if !ok {
+		return Op{Retain: infinity}
+	}
+
+	offset := it.offset
+	opLength := op.Length()
+	if length >= opLength-offset {
+		length = opLength - offset
+		it.index++
+		it.offset = 0
+	} else {
+		it.offset += length
+	}
+
+	switch {
+	case op.Delete > 0:
+		return Op{Delete: length}
+	case op.Retain > 0:
+		return Op{Retain: length, Attributes: op.Attributes}
+	}
+	if text, ok := op.Insert.(string); ok {
+		return Op{Insert: sliceUTF16(text, offset, length), Attributes: op.Attributes}
+	}
+	return Op{Insert: op.Insert, Attributes: op.Attributes}
+}
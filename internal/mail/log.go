@@ -0,0 +1,38 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LogSender, yerel geliştirme için e-postaları göndermek yerine loglar. dir verilmişse
+// her e-posta bu klasöre .eml dosyası olarak da yazılır.
+type LogSender struct {
+	dir  string
+	from string
+}
+
+func NewLogSender(dir, from string) *LogSender {
+	return &LogSender{
+		dir:  dir,
+		from: from,
+	}
+}
+
+func (s *LogSender) Send(message Message) error {
+	log.Printf("Mail to %s: %s\n%s", message.To, message.Subject, message.Body)
+	if s.dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(message.To)
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000000"), recipient)
+	return os.WriteFile(filepath.Join(s.dir, name), compose(s.from, message), 0o644)
+}
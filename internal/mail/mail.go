@@ -0,0 +1,48 @@
+// Package mail, kullanıcılara e-posta gönderimini soyutlar. Üretimde SMTP, yerel geliştirmede
+// e-postaları diske yazan veya loglayan gönderici kullanılır.
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/dione-docs-backend/internal/config"
+)
+
+// Message, gönderilecek düz metin e-postadır.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender, e-posta gönderen bileşenlerin arayüzüdür.
+type Sender interface {
+	Send(message Message) error
+}
+
+// NewSender, MAIL_DRIVER ayarına göre göndericiyi oluşturur: "smtp" için SMTPSender,
+// diğer tüm değerler için LogSender.
+func NewSender(cfg *config.Config) Sender {
+	if cfg.MailDriver == "smtp" {
+		return NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	}
+	return NewLogSender(cfg.MailDir, cfg.MailFrom)
+}
+
+// compose, mesajı RFC 5322 biçiminde UTF-8 düz metin e-postaya çevirir.
+func compose(from string, message Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return buf.Bytes()
+}
@@ -0,0 +1,34 @@
+package mail
+
+import (
+	"net"
+	"net/smtp"
+)
+
+// SMTPSender, e-postaları bir SMTP sunucusu üzerinden gönderir. Kullanıcı adı boşsa
+// kimlik doğrulama yapılmaz.
+type SMTPSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPSender(host, port, username, password, from string) *SMTPSender {
+	return &SMTPSender{
+		addr:     net.JoinHostPort(host, port),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (s *SMTPSender) Send(message Message) error {
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+	return smtp.SendMail(s.addr, auth, s.from, []string{message.To}, compose(s.from, message))
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccessRequestStatus string
+
+const (
+	AccessRequestStatusPending  AccessRequestStatus = "pending"
+	AccessRequestStatusApproved AccessRequestStatus = "approved"
+	AccessRequestStatusDenied   AccessRequestStatus = "denied"
+)
+
+// AccessRequest, belgeye erişimi olmayan (veya daha yüksek bir rol isteyen) bir kullanıcının
+// belge sahibine ve yöneticilerine ilettiği erişim isteğidir. Onaylandığında kabul edilmiş bir
+// Permission oluşturulur.
+type AccessRequest struct {
+	ID          uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	DocumentID  uuid.UUID           `gorm:"type:uuid;not null;index"`
+	RequesterID uuid.UUID           `gorm:"type:uuid;not null;index"`
+	AccessType  string              `gorm:"not null"`
+	Message     string              `gorm:"type:text;not null;default:''"`
+	Status      AccessRequestStatus `gorm:"type:varchar(10);not null;default:'pending';index"`
+	ResolvedBy  *uuid.UUID          `gorm:"type:uuid"`
+	ResolvedAt  *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,36 @@
+package models
+
+// AccessType, kullanıcının bir belgeye doğrudan, takım, organizasyon veya paylaşım bağlantısı
+// üzerinden sahip olduğu erişim seviyesidir.
+type AccessType string
+
+const (
+	AccessTypeViewer    AccessType = "viewer"
+	AccessTypeCommenter AccessType = "commenter" // okuyabilir, yorum ve öneri bırakabilir; içeriği düzenleyemez
+	AccessTypeEditor    AccessType = "editor"
+	AccessTypeAdmin     AccessType = "admin"
+)
+
+// accessRanks, erişim tiplerini yetki seviyesine göre sıralar; bilinmeyen tipler 0'dır.
+var accessRanks = map[AccessType]int{
+	AccessTypeViewer:    1,
+	AccessTypeCommenter: 2,
+	AccessTypeEditor:    3,
+	AccessTypeAdmin:     4,
+}
+
+// AccessRank, erişim tipinin yetki seviyesini döndürür.
+func AccessRank(accessType string) int {
+	return accessRanks[AccessType(accessType)]
+}
+
+// HighestAccess, verilen erişim tiplerinden en yetkili olanını döndürür; liste boşsa "" döner.
+func HighestAccess(accessTypes ...string) string {
+	best := ""
+	for _, accessType := range accessTypes {
+		if AccessRank(accessType) > AccessRank(best) {
+			best = accessType
+		}
+	}
+	return best
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AttributionSpan, belge içeriğindeki ardışık bir aralığın hangi kullanıcı tarafından
+// hangi versiyonda yazıldığını belirtir. Length, UTF-16 kod birimi cinsindendir.
+// UserID, sistem tarafından yapılan veya yazarı bilinmeyen değişikliklerde uuid.Nil'dir.
+type AttributionSpan struct {
+	Length  int       `json:"length"`
+	UserID  uuid.UUID `json:"user_id"`
+	Version int       `json:"version"`
+}
+
+// DocumentOperation, belgeye uygulanmış tek bir değişikliği ve yazarını saklar. Canlı
+// oturumdaki her operasyon hub tarafından, hub dışındaki değişiklikler (düzenleme, geri
+// yükleme, öneri kabulü) içerik farkı olarak kaydedilir. Bir belgenin ilk kaydı, kayıt
+// tutulmaya başlandığı andaki içeriğin tamamını ekleyen temel kayıttır.
+type DocumentOperation struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	DocumentID uuid.UUID `gorm:"type:uuid;not null;index"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null"`
+	Version    int       `gorm:"not null"` // değişikliğin ilk yer aldığı belge versiyonu
+	Change     []byte    `gorm:"type:jsonb;not null"`
+	CreatedAt  time.Time
+}
+
+// DocumentAttribution, belgenin operasyon kaydının LastOperationID'ye kadar uygulanmasıyla
+// hesaplanmış yazar indeksidir. Content, bu operasyonlar sonunda oluşan içeriktir; yeni
+// operasyonlar geldiğinde indeks baştan değil, bu kayıttan devam edilerek güncellenir.
+type DocumentAttribution struct {
+	DocumentID      uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Version         int       `gorm:"not null"`
+	LastOperationID int64     `gorm:"not null;default:0"`
+	Content         []byte    `gorm:"type:jsonb"`
+	Spans           []byte    `gorm:"type:jsonb;not null"`
+	UpdatedAt       time.Time
+}
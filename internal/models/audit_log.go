@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditActionUserDisable     AuditAction = "user.disable"
+	AuditActionUserEnable      AuditAction = "user.enable"
+	AuditActionUserLogout      AuditAction = "user.force_logout"
+	AuditActionUserMFAReset    AuditAction = "user.mfa_reset"
+	AuditActionUserView        AuditAction = "user.view"
+	AuditActionUserSearch      AuditAction = "user.search"
+	AuditActionDocumentView    AuditAction = "document.view"
+	AuditActionDocumentRestore AuditAction = "document.restore"
+)
+
+type AuditTargetType string
+
+const (
+	AuditTargetUser     AuditTargetType = "user"
+	AuditTargetDocument AuditTargetType = "document"
+)
+
+// AuditLog, bir site yöneticisinin yönetim API'si üzerinden yaptığı bir işlemin kaydıdır.
+// Kayıtlar değiştirilmez ve silinmez.
+type AuditLog struct {
+	ID         uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ActorID    uuid.UUID       `gorm:"type:uuid;not null;index"`
+	Action     AuditAction     `gorm:"type:varchar(40);not null;index"`
+	TargetType AuditTargetType `gorm:"type:varchar(20)"`
+	TargetID   *uuid.UUID      `gorm:"type:uuid;index"` // arama gibi tek bir hedefi olmayan işlemlerde boştur
+	Details    []byte          `gorm:"type:jsonb"`      // işleme özgü ek bilgiler (ör. sebep, arama sorgusu)
+	IPAddress  string          `gorm:"type:varchar(45)"`
+	UserAgent  string          `gorm:"type:text"`
+	CreatedAt  time.Time       `gorm:"index"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment, belgenin bir metin aralığına bağlı yorum dizisinin kök yorumu veya bir yanıtıdır.
+// Anchor alanları yalnızca kök yorumlarda anlamlıdır ve belge düzenlendikçe yeniden konumlandırılır.
+// Konumlar delta paketindeki gibi UTF-16 kod birimi cinsindendir.
+type Comment struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	DocumentID   uuid.UUID  `gorm:"type:uuid;not null;index"`
+	ParentID     *uuid.UUID `gorm:"type:uuid;index"` // nil ise dizinin kök yorumu
+	AuthorID     uuid.UUID  `gorm:"type:uuid;not null"`
+	Body         string     `gorm:"type:text;not null"`
+	AnchorStart  int        `gorm:"not null;default:0"`
+	AnchorLength int        `gorm:"not null;default:0"`
+	Quote        string     `gorm:"type:text"` // yorum eklenirken seçili olan metin
+	ResolvedAt   *time.Time
+	ResolvedBy   *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (c *Comment) IsReply() bool {
+	return c.ParentID != nil
+}
+
+func (c *Comment) IsResolved() bool {
+	return c.ResolvedAt != nil
+}
@@ -8,24 +8,64 @@ import (
 )
 
 type Document struct {
-	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	Title       string    `gorm:"not null"`
-	Description string
-	OwnerID     uuid.UUID `gorm:"type:uuid;not null"`
-	Content     []byte    `gorm:"type:jsonb"`
-	Version     int       `gorm:"not null;default:1"`
-	IsPublic    bool      `gorm:"not null;default:false"`
-	Status      string    `gorm:"not null;default:'draft'"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Title          string    `gorm:"not null"`
+	Description    string
+	OwnerID        uuid.UUID  `gorm:"type:uuid;not null"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid;index"` // nil ise belge sahibinin kişisel alanındadır
+	Content        []byte     `gorm:"type:jsonb"`
+	Version        int        `gorm:"not null;default:1"`
+	IsPublic       bool       `gorm:"not null;default:false"`
+	Status         string     `gorm:"not null;default:'draft'"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
 }
 
+// VersionAction, bir DocumentVersion kaydının hangi işlem sonucunda oluştuğunu belirtir.
+type VersionAction string
+
+const (
+	VersionActionEdit       VersionAction = "edit"
+	VersionActionRestore    VersionAction = "restore"
+	VersionActionSuggestion VersionAction = "suggestion" // kabul edilen bir öneri uygulandı
+)
+
+// VersionEncoding, DocumentVersion.Content alanının nasıl saklandığını belirtir.
+type VersionEncoding string
+
+const (
+	// VersionEncodingLegacy, delta kodlamasından önce kaydedilmiş, henüz dönüştürülmemiş tam içeriktir.
+	VersionEncodingLegacy VersionEncoding = "legacy"
+	// VersionEncodingFull, içeriğin tamamını saklayan anlık görüntüdür (snapshot).
+	VersionEncodingFull VersionEncoding = "full"
+	// VersionEncodingDelta, BaseVersionID ile gösterilen snapshot'a göre Delta farkıdır.
+	VersionEncodingDelta VersionEncoding = "delta"
+)
+
+// DocumentVersion, belgenin bir önceki halini saklar. ChangedBy bu içeriği
+// değiştiren (yeni versiyona geçiren) kullanıcıdır. Name dolu olan versiyonlar
+// kullanıcı tarafından isimlendirilmiştir ve hiçbir temizlikte silinmez.
+// Content, Encoding alanına göre tam içerik veya bir snapshot'a göre Delta farkıdır;
+// repository okurken her zaman tam içeriği döndürür.
 type DocumentVersion struct {
-	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	DocumentID uuid.UUID `gorm:"type:uuid;not null"`
-	Version    int       `gorm:"not null"`
-	Content    []byte    `gorm:"type:jsonb"`
-	ChangedBy  uuid.UUID `gorm:"type:uuid;not null"`
-	CreatedAt  time.Time
+	ID                  uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	DocumentID          uuid.UUID       `gorm:"type:uuid;not null;index:idx_doc_version"`
+	Version             int             `gorm:"not null;index:idx_doc_version"`
+	Content             []byte          `gorm:"type:jsonb"`
+	Encoding            VersionEncoding `gorm:"type:varchar(10);not null;default:'legacy'"`
+	BaseVersionID       *uuid.UUID      `gorm:"type:uuid;index"`
+	ChangedBy           uuid.UUID       `gorm:"type:uuid;not null"`
+	Action              VersionAction   `gorm:"type:varchar(20);not null;default:'edit'"`
+	RestoredFromVersion *int
+	Name                string     `gorm:"type:varchar(120);not null;default:''"`
+	Note                string     `gorm:"type:text"`
+	NamedBy             *uuid.UUID `gorm:"type:uuid"`
+	NamedAt             *time.Time
+	CreatedAt           time.Time
+}
+
+// IsNamed, versiyonun kullanıcı tarafından isimlendirilip isimlendirilmediğini döndürür.
+func (v *DocumentVersion) IsNamed() bool {
+	return v.Name != ""
 }
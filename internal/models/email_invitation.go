@@ -0,0 +1,35 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EmailInvitationStatus string
+
+const (
+	EmailInvitationStatusPending   EmailInvitationStatus = "pending"
+	EmailInvitationStatusConverted EmailInvitationStatus = "converted" // e-posta sahibi kayıt oldu, Permission'a çevrildi
+	EmailInvitationStatusExpired   EmailInvitationStatus = "expired"   // kayıt olunmadan izin süresi doldu
+)
+
+// EmailInvitation, henüz hesabı olmayan bir e-posta adresine gönderilen belge davetidir.
+// Adres kayıt olduğunda bekleyen bir Permission davetiyesine dönüştürülür.
+type EmailInvitation struct {
+	ID         uuid.UUID             `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	DocumentID uuid.UUID             `gorm:"type:uuid;not null;index"`
+	Email      string                `gorm:"type:varchar(255);not null;index"` // küçük harfle saklanır
+	AccessType string                `gorm:"not null"`
+	Status     EmailInvitationStatus `gorm:"type:varchar(10);not null;default:'pending';index"`
+	InvitedBy  uuid.UUID             `gorm:"type:uuid;not null"`
+	ExpiresAt  *time.Time            // oluşacak iznin sona ereceği zaman; nil ise süresizdir
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NormalizeEmail, e-posta adreslerinin davetlerde karşılaştırılabilir biçimidir.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserMFA, kullanıcının TOTP iki adımlı doğrulama ayarıdır. Secret şifrelenmiş olarak saklanır;
+// kayıt, kullanıcı ilk kodu doğrulayana kadar etkin değildir.
+type UserMFA struct {
+	UserID       uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Secret       string    `gorm:"type:text;not null"`
+	Enabled      bool      `gorm:"not null;default:false"`
+	ConfirmedAt  *time.Time
+	LastUsedStep int64 `gorm:"not null;default:0"` // aynı TOTP kodunun tekrar kullanılmasını önler
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// RecoveryCode, doğrulama uygulamasına erişilemediğinde kullanılan tek kullanımlık koddur.
+// Kodun kendisi değil, imzalı özeti saklanır.
+type RecoveryCode struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	CodeHash  string    `gorm:"type:varchar(64);not null;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
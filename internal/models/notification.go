@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationType string
+
+const (
+	NotificationTypePermissionExpired NotificationType = "permission_expired"
+	NotificationTypeTransferRequested NotificationType = "ownership_transfer_requested"
+	NotificationTypeTransferAccepted  NotificationType = "ownership_transfer_accepted"
+	NotificationTypeTransferRejected  NotificationType = "ownership_transfer_rejected"
+	NotificationTypeOwnershipReceived NotificationType = "ownership_received" // sahiplik bir yönetici tarafından devredildi
+	NotificationTypeAccessRequested   NotificationType = "access_requested"
+	NotificationTypeAccessApproved    NotificationType = "access_approved"
+	NotificationTypeAccessDenied      NotificationType = "access_denied"
+)
+
+// Notification, bir kullanıcıya sunucu tarafından iletilen bildirimdir.
+type Notification struct {
+	ID         uuid.UUID        `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID     uuid.UUID        `gorm:"type:uuid;not null;index:idx_notification_user_read"`
+	Type       NotificationType `gorm:"type:varchar(40);not null"`
+	DocumentID *uuid.UUID       `gorm:"type:uuid;index"`
+	ActorID    *uuid.UUID       `gorm:"type:uuid"` // bildirime konu olan kullanıcı
+	Message    string           `gorm:"type:text;not null"`
+	ReadAt     *time.Time       `gorm:"index:idx_notification_user_read"`
+	CreatedAt  time.Time
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrganizationRole string
+
+const (
+	OrganizationRoleOwner  OrganizationRole = "owner"
+	OrganizationRoleAdmin  OrganizationRole = "admin"
+	OrganizationRoleMember OrganizationRole = "member"
+	OrganizationRoleGuest  OrganizationRole = "guest"
+)
+
+// IsManager, rolün organizasyonu yönetebilen (owner veya admin) bir rol olup olmadığını döndürür.
+func (r OrganizationRole) IsManager() bool {
+	return r == OrganizationRoleOwner || r == OrganizationRoleAdmin
+}
+
+// Organization, belgelerin ve üyelerin ayrı tutulduğu çalışma alanıdır.
+//
+// DefaultAccess boş değilse misafir olmayan tüm üyeler organizasyonun belgelerine bu
+// seviyede erişir. RestrictSharing açıksa belgeler yalnızca üyelerle veya e-posta alan adı
+// AllowedDomains içinde olan kullanıcılarla paylaşılabilir.
+type Organization struct {
+	ID              uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name            string    `gorm:"type:varchar(100);not null"`
+	DefaultAccess   string    `gorm:"type:varchar(20);not null;default:''"`
+	RestrictSharing bool      `gorm:"not null;default:false"`
+	AllowedDomains  string    `gorm:"type:text;not null;default:''"` // virgülle ayrılmış alan adları
+	CreatedBy       uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Domains, izin verilen alan adlarını küçük harfe çevrilmiş olarak döndürür.
+func (o *Organization) Domains() []string {
+	var domains []string
+	for _, domain := range strings.Split(o.AllowedDomains, ",") {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return domains
+}
+
+// AllowsEmail, e-posta adresinin alan adının izin verilen alan adlarından biri olup olmadığını döndürür.
+func (o *Organization) AllowsEmail(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, allowed := range o.Domains() {
+		if domain == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+type OrganizationMember struct {
+	OrganizationID uuid.UUID        `gorm:"type:uuid;primaryKey"`
+	UserID         uuid.UUID        `gorm:"type:uuid;primaryKey;index"`
+	Role           OrganizationRole `gorm:"type:varchar(10);not null;default:'member'"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// DocumentAccess, üyenin organizasyon belgelerine rolü ve organizasyonun varsayılan
+// erişimi üzerinden sahip olduğu erişim tipini döndürür; erişim yoksa "" döner.
+func (m *OrganizationMember) DocumentAccess(defaultAccess string) string {
+	switch m.Role {
+	case OrganizationRoleOwner, OrganizationRoleAdmin:
+		return string(AccessTypeAdmin)
+	case OrganizationRoleMember:
+		return defaultAccess
+	}
+	return ""
+}
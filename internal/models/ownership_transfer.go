@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TransferStatus string
+
+const (
+	TransferStatusPending   TransferStatus = "pending"
+	TransferStatusAccepted  TransferStatus = "accepted"
+	TransferStatusRejected  TransferStatus = "rejected"
+	TransferStatusCancelled TransferStatus = "cancelled"
+)
+
+// OwnershipTransfer, belge sahibinin sahipliği başka bir kullanıcıya devretme isteğidir.
+// Alıcı kabul edene kadar beklemede kalır; kabul edildiğinde FromUserID belgede admin olur.
+type OwnershipTransfer struct {
+	ID          uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	DocumentID  uuid.UUID      `gorm:"type:uuid;not null;index"`
+	FromUserID  uuid.UUID      `gorm:"type:uuid;not null"`
+	ToUserID    uuid.UUID      `gorm:"type:uuid;not null;index"`
+	Status      TransferStatus `gorm:"type:varchar(10);not null;default:'pending';index"`
+	RespondedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -12,14 +12,7 @@ const (
 	PermissionStatusPending  PermissionStatus = "pending"
 	PermissionStatusAccepted PermissionStatus = "accepted"
 	PermissionStatusRejected PermissionStatus = "rejected"
-)
-
-type AccessType string
-
-const (
-	AccessTypeViewer AccessType = "viewer"
-	AccessTypeEditor AccessType = "editor"
-	AccessTypeAdmin  AccessType = "admin"
+	PermissionStatusExpired  PermissionStatus = "expired"
 )
 
 type Permission struct {
@@ -29,6 +22,15 @@ type Permission struct {
 	AccessType string           `gorm:"not null"`
 	Status     PermissionStatus `gorm:"type:varchar(10);not null;default:'pending';index:idx_doc_user_status,unique"`
 	SharedBy   uuid.UUID        `gorm:"type:uuid;not null"`
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ExpiresAt  *time.Time       `gorm:"index"` // nil ise izin süresizdir
+	// ShareLinkID, izni veren paylaşım bağlantısıdır; bağlantı iptal edildiğinde izin silinir.
+	// Doğrudan paylaşılan izinlerde nil'dir.
+	ShareLinkID *uuid.UUID `gorm:"type:uuid;index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// IsExpired, iznin süresinin dolup dolmadığını döndürür.
+func (p *Permission) IsExpired(now time.Time) bool {
+	return p.ExpiresAt != nil && !now.Before(*p.ExpiresAt)
 }
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TokenScope string
+
+const (
+	ScopeDocumentsRead  TokenScope = "documents:read"
+	ScopeDocumentsWrite TokenScope = "documents:write"
+	ScopeChatWrite      TokenScope = "chat:write"
+	ScopeAdmin          TokenScope = "admin" // diğer tüm kapsamları da içerir
+)
+
+// ValidTokenScope, kapsamın tanımlı olup olmadığını döndürür.
+func ValidTokenScope(scope TokenScope) bool {
+	switch scope {
+	case ScopeDocumentsRead, ScopeDocumentsWrite, ScopeChatWrite, ScopeAdmin:
+		return true
+	}
+	return false
+}
+
+// PersonalAccessToken, betik ve otomasyon istemcilerinin şifre yerine kullandığı uzun ömürlü
+// token'dır. Token'ın kendisi yalnızca oluşturulurken gösterilir; SHA-256 özeti saklanır.
+type PersonalAccessToken struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name       string    `gorm:"type:varchar(100);not null"`
+	TokenHash  string    `gorm:"type:varchar(64);uniqueIndex;not null"`
+	Prefix     string    `gorm:"type:varchar(16);not null"`  // listelerde token'ı tanımak için ilk karakterler
+	Scopes     string    `gorm:"type:varchar(255);not null"` // boşlukla ayrılmış kapsamlar
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ScopeList, token'ın kapsamlarını döndürür.
+func (t *PersonalAccessToken) ScopeList() []TokenScope {
+	fields := strings.Fields(t.Scopes)
+	scopes := make([]TokenScope, len(fields))
+	for i, field := range fields {
+		scopes[i] = TokenScope(field)
+	}
+	return scopes
+}
+
+// IsActive, token'ın iptal edilmemiş ve süresinin dolmamış olup olmadığını döndürür.
+func (t *PersonalAccessToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// JoinTokenScopes, kapsamları veritabanında saklanan biçime çevirir.
+func JoinTokenScopes(scopes []TokenScope) string {
+	parts := make([]string, len(scopes))
+	for i, scope := range scopes {
+		parts[i] = string(scope)
+	}
+	return strings.Join(parts, " ")
+}
+
+// HasTokenScope, kapsam listesinin required kapsamını içerip içermediğini döndürür. admin tüm
+// kapsamları, documents:write ise documents:read kapsamını da içerir.
+func HasTokenScope(scopes []TokenScope, required TokenScope) bool {
+	for _, scope := range scopes {
+		if scope == required || scope == ScopeAdmin {
+			return true
+		}
+		if scope == ScopeDocumentsWrite && required == ScopeDocumentsRead {
+			return true
+		}
+	}
+	return false
+}
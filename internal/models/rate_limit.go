@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RateLimitBucket, Postgres deposundaki bir hız sınırı anahtarının token kovasıdır.
+type RateLimitBucket struct {
+	Key        string    `gorm:"type:varchar(255);primaryKey"`
+	Tokens     float64   `gorm:"not null"`
+	RefilledAt time.Time `gorm:"not null"`
+	ExpiresAt  time.Time `gorm:"not null;index"` // kova bu andan sonra dolu sayılır ve silinebilir
+}
+
+// RateLimitFailure, bir anahtarın art arda başarısız denemeleri ve varsa kilidinin bitiş zamanıdır.
+type RateLimitFailure struct {
+	Key         string `gorm:"type:varchar(255);primaryKey"`
+	Count       int    `gorm:"not null"`
+	LastFailure time.Time
+	LockedUntil time.Time
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VersionRetentionPolicy, bir belge için varsayılan versiyon saklama politikasını geçersiz kılar.
+// KeepAllHours süresince tüm versiyonlar, HourlyDays gün boyunca saatte bir versiyon,
+// daha eskiler için günde bir versiyon saklanır. İsimlendirilmiş versiyonlar her zaman korunur.
+type VersionRetentionPolicy struct {
+	DocumentID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	KeepAllHours int       `gorm:"not null"`
+	HourlyDays   int       `gorm:"not null"`
+	Disabled     bool      `gorm:"not null;default:false"`
+	UpdatedBy    uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session, bir cihazdaki oturum açma kaydıdır. Erişim token'ları oturum ID'sini taşır;
+// oturum iptal edildiğinde veya süresi dolduğunda token'lar da geçersiz olur. Yenileme
+// token'ları yalnızca SHA-256 özetleri olarak saklanır ve her kullanımda değiştirilir.
+type Session struct {
+	ID                uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID            uuid.UUID `gorm:"type:uuid;not null;index"`
+	RefreshTokenHash  string    `gorm:"type:varchar(64);uniqueIndex;not null"`
+	PreviousTokenHash string    `gorm:"type:varchar(64);index"` // tekrar kullanılırsa token çalınmış sayılır
+	DeviceName        string    `gorm:"type:varchar(100)"`
+	IPAddress         string    `gorm:"type:varchar(45)"`
+	UserAgent         string    `gorm:"type:text"`
+	LastUsedAt        time.Time
+	ExpiresAt         time.Time `gorm:"not null;index"`
+	RevokedAt         *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// IsActive, oturumun iptal edilmemiş ve süresinin dolmamış olup olmadığını döndürür.
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShareLink, bağlantıya sahip herkese belgeye AccessType seviyesinde erişim verir.
+// MaxUses 0 ise kullanım sınırı yoktur.
+type ShareLink struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	DocumentID   uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Token        string     `gorm:"type:varchar(64);uniqueIndex;not null"`
+	AccessType   string     `gorm:"type:varchar(20);not null"`
+	PasswordHash string     `gorm:"not null;default:''"`
+	ExpiresAt    *time.Time `gorm:"index"`
+	MaxUses      int        `gorm:"not null;default:0"`
+	UseCount     int        `gorm:"not null;default:0"`
+	CreatedBy    uuid.UUID  `gorm:"type:uuid;not null"`
+	RevokedAt    *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// HasPassword, bağlantının parola ile korunup korunmadığını döndürür.
+func (l *ShareLink) HasPassword() bool {
+	return l.PasswordHash != ""
+}
+
+// IsOpen, bağlantının iptal edilmemiş ve süresi dolmamış olduğunu döndürür. Kullanım hakkı dikkate alınmaz.
+func (l *ShareLink) IsOpen(now time.Time) bool {
+	if l.RevokedAt != nil {
+		return false
+	}
+	return l.ExpiresAt == nil || now.Before(*l.ExpiresAt)
+}
+
+// IsActive, bağlantının iptal edilmemiş, süresi dolmamış ve kullanım hakkı bitmemiş olduğunu döndürür.
+func (l *ShareLink) IsActive(now time.Time) bool {
+	return l.IsOpen(now) && (l.MaxUses == 0 || l.UseCount < l.MaxUses)
+}
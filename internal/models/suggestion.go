@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SuggestionStatus string
+
+const (
+	SuggestionStatusPending  SuggestionStatus = "pending"
+	SuggestionStatusAccepted SuggestionStatus = "accepted"
+	SuggestionStatusRejected SuggestionStatus = "rejected"
+)
+
+// Suggestion, öneri modundaki bir kullanıcının belgeyi değiştirmeden önerdiği düzenlemedir.
+// Change, belgenin güncel haline uygulanabilecek bir Delta'dır; bekleyen öneriler belge
+// düzenlendikçe yeniden temellendirilir (rebase).
+type Suggestion struct {
+	ID         uuid.UUID        `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	DocumentID uuid.UUID        `gorm:"type:uuid;not null;index"`
+	AuthorID   uuid.UUID        `gorm:"type:uuid;not null"`
+	Change     []byte           `gorm:"type:jsonb;not null"`
+	Status     SuggestionStatus `gorm:"type:varchar(10);not null;default:'pending';index"`
+	ResolvedBy *uuid.UUID       `gorm:"type:uuid"`
+	ResolvedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (s *Suggestion) IsPending() bool {
+	return s.Status == SuggestionStatusPending
+}
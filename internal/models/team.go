@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TeamRole string
+
+const (
+	TeamRoleAdmin  TeamRole = "admin"
+	TeamRoleMember TeamRole = "member"
+)
+
+// Team, belgelerin topluca paylaşılabildiği kullanıcı grubudur. OwnerID takımı
+// oluşturan kullanıcıdır ve takımdan çıkarılamaz.
+type Team struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name        string    `gorm:"type:varchar(100);not null"`
+	Description string    `gorm:"type:text"`
+	OwnerID     uuid.UUID `gorm:"type:uuid;not null;index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type TeamMember struct {
+	TeamID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	Role      TeamRole  `gorm:"type:varchar(10);not null;default:'member'"`
+	AddedBy   uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TeamPermission, bir belgenin takımın tüm üyeleriyle paylaşıldığı erişim iznidir.
+type TeamPermission struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	DocumentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_doc_team"`
+	TeamID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_doc_team;index"`
+	AccessType string    `gorm:"type:varchar(20);not null"`
+	SharedBy   uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
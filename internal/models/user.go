@@ -4,14 +4,34 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type User struct {
-	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	Username     string    `gorm:"uniqueIndex;not null"`
-	Email        string    `gorm:"uniqueIndex;not null"`
-	PasswordHash string    `gorm:"not null"`
-	ProfileImage string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Username      string    `gorm:"uniqueIndex;not null"`
+	FullName      string    `gorm:"type:varchar(100)"`
+	Email         string    `gorm:"uniqueIndex;not null"`
+	PendingEmail  string    `gorm:"type:varchar(255)"`      // doğrulama bekleyen yeni e-posta adresi
+	PasswordHash  string    `gorm:"not null"`               // yalnızca harici kimlikle giriş yapan kullanıcılarda boştur
+	EmailVerified bool      `gorm:"not null;default:false"` // e-posta adresinin sahipliği doğrulandı mı
+	ProfileImage  string    // yüklenen avatarın depo anahtarı öneki veya harici resim URL'si
+	IsSiteAdmin   bool      `gorm:"not null;default:false"` // /api/v1/admin uç noktalarını kullanabilir
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DisabledAt    *time.Time     // doluysa hesap bir site yöneticisi tarafından devre dışı bırakılmıştır
+	DeletedAt     gorm.DeletedAt `gorm:"index"` // hesap silindiğinde kişisel bilgiler temizlenir ve satır silinmiş işaretlenir
+}
+
+// IsDisabled, hesabın devre dışı bırakılıp bırakılmadığını döndürür. Devre dışı hesaplar giriş yapamaz.
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// DisplayName, kullanıcının arayüzde gösterilecek adıdır; ad soyad girilmemişse kullanıcı adıdır.
+func (u *User) DisplayName() string {
+	if u.FullName != "" {
+		return u.FullName
+	}
+	return u.Username
 }
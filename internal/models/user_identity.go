@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity, kullanıcının harici bir kimlik sağlayıcıdaki (ör. Google) hesabıdır. Bir
+// kullanıcının şifresiyle birlikte birden fazla bağlı kimliği olabilir.
+type UserIdentity struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Provider  string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_identity_provider_subject"`
+	Subject   string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_identity_provider_subject"` // sağlayıcıdaki kullanıcı ID'si (sub)
+	Email     string    `gorm:"type:varchar(255)"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
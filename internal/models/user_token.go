@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserTokenPurpose string
+
+const (
+	UserTokenPasswordReset     UserTokenPurpose = "password_reset"
+	UserTokenEmailVerification UserTokenPurpose = "email_verification"
+	UserTokenEmailChange       UserTokenPurpose = "email_change"
+)
+
+// UserToken, e-postayla gönderilen tek kullanımlık hesap token'ıdır (şifre sıfırlama, e-posta
+// doğrulama). Token'ın kendisi saklanmaz; yalnızca sunucu anahtarıyla imzalanmış özeti tutulur.
+type UserToken struct {
+	ID        uuid.UUID        `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID    uuid.UUID        `gorm:"type:uuid;not null;index"`
+	Purpose   UserTokenPurpose `gorm:"type:varchar(30);not null"`
+	TokenHash string           `gorm:"type:varchar(64);uniqueIndex;not null"`
+	ExpiresAt time.Time        `gorm:"not null;index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
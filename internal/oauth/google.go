@@ -0,0 +1,16 @@
+package oauth
+
+import "github.com/dione-docs-backend/internal/config"
+
+// ProviderGoogle, Google hesaplarının UserIdentity kayıtlarındaki sağlayıcı adıdır.
+const ProviderGoogle = "google"
+
+// NewGoogleProvider, Google OIDC sağlayıcısını yapılandırmadan oluşturur. Uç noktalar
+// GOOGLE_AUTH_URL, GOOGLE_TOKEN_URL ve GOOGLE_USERINFO_URL ile değiştirilebilir.
+func NewGoogleProvider(cfg *config.Config) *Provider {
+	return NewProvider(ProviderGoogle, cfg.GoogleClientID, cfg.GoogleClientSecret, cfg.GoogleRedirectURL, Endpoints{
+		AuthURL:     cfg.GoogleAuthURL,
+		TokenURL:    cfg.GoogleTokenURL,
+		UserInfoURL: cfg.GoogleUserInfoURL,
+	}, "openid", "email", "profile")
+}
@@ -0,0 +1,185 @@
+// Package oauth, harici kimlik sağlayıcılarla (Google vb.) OAuth2/OIDC yetkilendirme kodu
+// akışını PKCE ile yürütür. Uç noktalar yapılandırılabilir olduğundan testlerde sahte bir
+// sağlayıcı kullanılabilir.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotConfigured, sağlayıcı için istemci bilgileri tanımlanmadığında döner.
+var ErrNotConfigured = errors.New("oauth provider is not configured")
+
+// Endpoints, sağlayıcının yetkilendirme, token ve kullanıcı bilgisi adresleridir.
+type Endpoints struct {
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+}
+
+// UserInfo, sağlayıcının OIDC userinfo uç noktasından dönen kullanıcı bilgileridir.
+type UserInfo struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+}
+
+// Provider, tek bir OAuth2/OIDC sağlayıcısının istemci yapılandırmasıdır.
+type Provider struct {
+	name         string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+	endpoints    Endpoints
+	client       *http.Client
+}
+
+func NewProvider(name, clientID, clientSecret, redirectURL string, endpoints Endpoints, scopes ...string) *Provider {
+	return &Provider{
+		name:         name,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		scopes:       scopes,
+		endpoints:    endpoints,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name, sağlayıcının UserIdentity kayıtlarında kullanılan adıdır.
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Enabled, sağlayıcının istemci bilgileri ve dönüş adresi tanımlıysa true döner.
+func (p *Provider) Enabled() bool {
+	return p.clientID != "" && p.redirectURL != "" && p.endpoints.AuthURL != "" &&
+		p.endpoints.TokenURL != "" && p.endpoints.UserInfoURL != ""
+}
+
+// AuthCodeURL, kullanıcının yönlendirileceği yetkilendirme adresini state ve PKCE
+// code_challenge ile oluşturur.
+func (p *Provider) AuthCodeURL(state, verifier string) string {
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.clientID},
+		"redirect_uri":          {p.redirectURL},
+		"scope":                 {strings.Join(p.scopes, " ")},
+		"state":                 {state},
+		"code_challenge":        {challenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(p.endpoints.AuthURL, "?") {
+		separator = "&"
+	}
+	return p.endpoints.AuthURL + separator + params.Encode()
+}
+
+// Exchange, yetkilendirme kodunu PKCE code_verifier ile erişim token'ına çevirir.
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	if !p.Enabled() {
+		return "", ErrNotConfigured
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.redirectURL},
+		"client_id":     {p.clientID},
+		"client_secret": {p.clientSecret},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoints.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := p.do(req, &token); err != nil {
+		return "", fmt.Errorf("token exchange failed: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("token exchange failed: response has no access_token")
+	}
+	return token.AccessToken, nil
+}
+
+// UserInfo, erişim token'ıyla kullanıcının kimlik bilgilerini alır.
+func (p *Provider) UserInfo(ctx context.Context, accessToken string) (*UserInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoints.UserInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	var info UserInfo
+	if err := p.do(req, &info); err != nil {
+		return nil, fmt.Errorf("userinfo request failed: %w", err)
+	}
+	if info.Subject == "" {
+		return nil, errors.New("userinfo response has no subject")
+	}
+	return &info, nil
+}
+
+func (p *Provider) do(req *http.Request, out interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d: %s", req.URL.Host, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, out)
+}
+
+// NewState, CSRF'e karşı yetkilendirme isteğine eklenen rastgele state değerini üretir.
+func NewState() (string, error) {
+	return randomString(24)
+}
+
+// NewVerifier, PKCE için rastgele bir code_verifier üretir (RFC 7636).
+func NewVerifier() (string, error) {
+	return randomString(32)
+}
+
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomString(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryBucket struct {
+	bucket    Bucket
+	expiresAt time.Time
+}
+
+type memoryFailures struct {
+	failures  Failures
+	expiresAt time.Time
+}
+
+// MemoryStore, durumu süreç belleğinde tutar. Yalnızca tek sunuculu kurulumlarda doğru sonuç
+// verir; sunucu yeniden başladığında sayaçlar sıfırlanır.
+type MemoryStore struct {
+	mu       sync.Mutex
+	buckets  map[string]*memoryBucket
+	failures map[string]*memoryFailures
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]*memoryBucket),
+		failures: make(map[string]*memoryFailures),
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, rule Rule) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := s.buckets[key]
+	if !ok {
+		entry = &memoryBucket{}
+		s.buckets[key] = entry
+	}
+	bucket, result := entry.bucket.Take(rule, now)
+	entry.bucket = bucket
+	entry.expiresAt = now.Add(result.Reset)
+	return result, nil
+}
+
+func (s *MemoryStore) LockedUntil(_ context.Context, key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.failures[key]; ok && entry.failures.LockedUntil.After(time.Now()) {
+		return entry.failures.LockedUntil, nil
+	}
+	return time.Time{}, nil
+}
+
+func (s *MemoryStore) RecordFailure(_ context.Context, key string, policy LockoutPolicy) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.failures[key]
+	if !ok {
+		entry = &memoryFailures{}
+		s.failures[key] = entry
+	}
+	entry.failures = entry.failures.Record(policy, time.Now())
+	entry.expiresAt = entry.failures.ExpiresAt(policy)
+	return entry.failures.LockedUntil, nil
+}
+
+func (s *MemoryStore) ResetFailures(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	return nil
+}
+
+func (s *MemoryStore) Cleanup(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, entry := range s.buckets {
+		if !now.Before(entry.expiresAt) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, entry := range s.failures {
+		if !now.Before(entry.expiresAt) {
+			delete(s.failures, key)
+		}
+	}
+	return nil
+}
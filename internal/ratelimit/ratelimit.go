@@ -0,0 +1,126 @@
+// Package ratelimit, istekleri token kovası (token bucket) algoritmasıyla sınırlar ve art arda
+// başarısız denemelerden sonra giderek uzayan kilitlemeler uygular. Durum bir Store'da tutulur;
+// tek sunuculu kurulumlar için bellek içi, birden fazla sunucu için Postgres deposu kullanılır.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Rule, bir anahtar için izin verilen istek hızıdır: kova Limit token alır ve Period süresinde
+// boştan tamamen dolar. Böylece Limit kadar ani istek yapılabilir, sonrası Period/Limit hızıyla açılır.
+type Rule struct {
+	Limit  int
+	Period time.Duration
+}
+
+// refillRate, saniyede kovaya eklenen token sayısıdır.
+func (r Rule) refillRate() float64 {
+	return float64(r.Limit) / r.Period.Seconds()
+}
+
+// Result, bir Take çağrısının sonucudur.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // Allowed false ise bir sonraki token'ın oluşmasına kalan süre
+	Reset      time.Duration // kovanın tamamen dolmasına kalan süre
+}
+
+// Bucket, bir anahtarın token kovasının durumudur.
+type Bucket struct {
+	Tokens     float64
+	RefilledAt time.Time
+}
+
+// Take, kovayı now anına kadar doldurur ve bir token almaya çalışır. Güncellenmiş kovayı ve
+// sonucu döndürür. Sıfır değerli kova dolu kabul edilir.
+func (b Bucket) Take(rule Rule, now time.Time) (Bucket, Result) {
+	capacity := float64(rule.Limit)
+	if b.RefilledAt.IsZero() {
+		b = Bucket{Tokens: capacity, RefilledAt: now}
+	}
+	if elapsed := now.Sub(b.RefilledAt).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(capacity, b.Tokens+elapsed*rule.refillRate())
+	}
+	b.RefilledAt = now
+
+	result := Result{Limit: rule.Limit}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.Tokens) / rule.refillRate())
+	}
+	result.Remaining = int(math.Floor(b.Tokens))
+	result.Reset = secondsToDuration((capacity - b.Tokens) / rule.refillRate())
+	return b, result
+}
+
+// LockoutPolicy, art arda başarısız denemelerden sonraki kilitlemeyi tanımlar. Threshold
+// başarısızlıktan sonra anahtar BaseDelay süresince kilitlenir; her ek başarısızlıkta süre
+// ikiye katlanır ve MaxDelay'i aşmaz. Son başarısızlıktan Window süre geçince sayaç sıfırlanır.
+type LockoutPolicy struct {
+	Threshold int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Window    time.Duration
+}
+
+// Failures, bir anahtarın başarısız deneme geçmişidir.
+type Failures struct {
+	Count       int
+	LastFailure time.Time
+	LockedUntil time.Time
+}
+
+// Record, now anındaki bir başarısızlığı ekler ve gerekirse kilitleme süresini hesaplar.
+func (f Failures) Record(policy LockoutPolicy, now time.Time) Failures {
+	if !f.LastFailure.IsZero() && now.Sub(f.LastFailure) > policy.Window {
+		f = Failures{}
+	}
+	f.Count++
+	f.LastFailure = now
+	if f.Count >= policy.Threshold {
+		delay := policy.BaseDelay
+		for i := policy.Threshold; i < f.Count && delay < policy.MaxDelay; i++ {
+			delay *= 2
+		}
+		if delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+		f.LockedUntil = now.Add(delay)
+	}
+	return f
+}
+
+// ExpiresAt, kaydın artık hiçbir etkisinin kalmadığı andır.
+func (f Failures) ExpiresAt(policy LockoutPolicy) time.Time {
+	expires := f.LastFailure.Add(policy.Window)
+	if f.LockedUntil.After(expires) {
+		return f.LockedUntil
+	}
+	return expires
+}
+
+// Store, kovaların ve başarısız deneme sayaçlarının saklandığı yerdir. Uygulamalar eşzamanlı
+// kullanıma uygun olmalıdır.
+type Store interface {
+	// Take, key kovasından rule ile bir token almaya çalışır.
+	Take(ctx context.Context, key string, rule Rule) (Result, error)
+	// LockedUntil, key kilitliyse kilidin açılacağı anı, değilse sıfır zamanı döndürür.
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+	// RecordFailure, key için başarısız bir deneme kaydeder ve varsa yeni kilit bitiş zamanını döndürür.
+	RecordFailure(ctx context.Context, key string, policy LockoutPolicy) (time.Time, error)
+	// ResetFailures, başarılı bir denemeden sonra key'in başarısızlık sayacını siler.
+	ResetFailures(ctx context.Context, key string) error
+	// Cleanup, artık etkisi kalmamış kova ve sayaçları siler.
+	Cleanup(ctx context.Context) error
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrAccessRequestNotPending, erişim isteği artık beklemede olmadığında döner.
+var ErrAccessRequestNotPending = errors.New("access request is no longer pending")
+
+type AccessRequestRepository interface {
+	Create(request *models.AccessRequest) error
+	GetByID(id any, request *models.AccessRequest) error
+	Update(request *models.AccessRequest) error
+	GetPendingByDocumentAndUser(documentID, userID uuid.UUID) (*models.AccessRequest, error)
+	// GetByDocumentID, belgenin erişim isteklerini en yeniden eskiye döndürür. status boşsa tümü döner.
+	GetByDocumentID(documentID uuid.UUID, status models.AccessRequestStatus) ([]models.AccessRequest, error)
+	// GetByRequesterID, kullanıcının erişim isteklerini en yeniden eskiye döndürür.
+	GetByRequesterID(userID uuid.UUID) ([]models.AccessRequest, error)
+	// Deny, bekleyen isteği reddeder. İstek beklemede değilse false döner.
+	Deny(requestID, resolvedBy uuid.UUID) (bool, error)
+	// Approve, bekleyen isteği onaylar ve kullanıcıya accessType erişimiyle kabul edilmiş bir
+	// Permission verir; kullanıcının bekleyen davetiyesi veya mevcut izni bunun yerine geçer.
+	// İstek beklemede değilse ErrAccessRequestNotPending döner.
+	Approve(request *models.AccessRequest, accessType string, resolvedBy uuid.UUID) error
+
+	// InOrganization, kullanıcıya göre listelenen istekleri verilen çalışma alanındaki
+	// belgelerle sınırlayan bir repository döndürür (nil kişisel alandır).
+	InOrganization(organizationID *uuid.UUID) AccessRequestRepository
+}
+
+type accessRequestRepo struct {
+	*GenericRepository[models.AccessRequest]
+	db *gorm.DB
+
+	organization organizationScope
+}
+
+func NewAccessRequestRepository(db *gorm.DB) AccessRequestRepository {
+	return &accessRequestRepo{
+		GenericRepository: NewGenericRepository[models.AccessRequest](db),
+		db:                db,
+	}
+}
+
+func (r *accessRequestRepo) InOrganization(organizationID *uuid.UUID) AccessRequestRepository {
+	scoped := *r
+	scoped.organization = newOrganizationScope(organizationID)
+	return &scoped
+}
+
+func (r *accessRequestRepo) Update(request *models.AccessRequest) error {
+	return r.db.Save(request).Error
+}
+
+func (r *accessRequestRepo) GetPendingByDocumentAndUser(documentID, userID uuid.UUID) (*models.AccessRequest, error) {
+	var request models.AccessRequest
+	if err := r.db.Where("document_id = ? AND requester_id = ? AND status = ?", documentID, userID, models.AccessRequestStatusPending).
+		First(&request).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *accessRequestRepo) GetByDocumentID(documentID uuid.UUID, status models.AccessRequestStatus) ([]models.AccessRequest, error) {
+	query := r.db.Where("document_id = ?", documentID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var requests []models.AccessRequest
+	if err := query.Order("created_at desc").Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (r *accessRequestRepo) GetByRequesterID(userID uuid.UUID) ([]models.AccessRequest, error) {
+	var requests []models.AccessRequest
+	if err := r.organization.apply(r.db, "document_id").
+		Where("requester_id = ?", userID).
+		Order("created_at desc").
+		Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (r *accessRequestRepo) Deny(requestID, resolvedBy uuid.UUID) (bool, error) {
+	result := resolveAccessRequest(r.db, requestID, models.AccessRequestStatusDenied, resolvedBy)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *accessRequestRepo) Approve(request *models.AccessRequest, accessType string, resolvedBy uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := resolveAccessRequest(tx, request.ID, models.AccessRequestStatusApproved, resolvedBy)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAccessRequestNotPending
+		}
+
+		if err := tx.Where("document_id = ? AND user_id = ? AND status IN ?", request.DocumentID, request.RequesterID,
+			[]models.PermissionStatus{models.PermissionStatusPending, models.PermissionStatusAccepted}).
+			Delete(&models.Permission{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.Permission{
+			DocumentID: request.DocumentID,
+			UserID:     request.RequesterID,
+			AccessType: accessType,
+			Status:     models.PermissionStatusAccepted,
+			SharedBy:   resolvedBy,
+		}).Error
+	})
+}
+
+func resolveAccessRequest(db *gorm.DB, requestID uuid.UUID, status models.AccessRequestStatus, resolvedBy uuid.UUID) *gorm.DB {
+	return db.Model(&models.AccessRequest{}).
+		Where("id = ? AND status = ?", requestID, models.AccessRequestStatusPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"resolved_by": resolvedBy,
+			"resolved_at": time.Now(),
+		})
+}
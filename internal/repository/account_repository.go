@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DeletedUserName, silinen kullanıcıların sohbet mesajlarında gösterilen addır.
+const DeletedUserName = "Deleted user"
+
+// AccountData, bir kullanıcı hakkında saklanan tüm kayıtlardır.
+type AccountData struct {
+	User                    models.User
+	Documents               []models.Document // çöp kutusundakiler dahil sahip olunan belgeler
+	Permissions             []models.Permission
+	Messages                []models.Message
+	Comments                []models.Comment
+	Suggestions             []models.Suggestion
+	Notifications           []models.Notification
+	Sessions                []models.Session
+	AccessTokens            []models.PersonalAccessToken
+	Identities              []models.UserIdentity
+	TeamMemberships         []models.TeamMember
+	OrganizationMemberships []models.OrganizationMember
+	AccessRequests          []models.AccessRequest
+	Transfers               []models.OwnershipTransfer
+	MFAEnabled              bool
+}
+
+type AccountRepository interface {
+	// Delete, kullanıcının hesabını tek transaction içinde siler. transferTo verilmişse sahip olunan
+	// belgeler ona devredilir, verilmemişse tüm bağlı kayıtlarıyla kalıcı olarak silinir; çöp
+	// kutusundaki belgeler her durumda silinir. Sohbet mesajları anonimleştirilir, izinler,
+	// üyelikler, oturumlar ve kimlik bilgileri silinir. Kullanıcı satırı mesajların ve yorumların
+	// yazar bağlantısı bozulmasın diye kişisel bilgilerinden arındırılıp silinmiş olarak işaretlenir.
+	Delete(userID uuid.UUID, transferTo *uuid.UUID) error
+	// Export, kullanıcı hakkında saklanan kayıtları toplar.
+	Export(userID uuid.UUID) (*AccountData, error)
+}
+
+type accountRepo struct {
+	db *gorm.DB
+}
+
+func NewAccountRepository(db *gorm.DB) AccountRepository {
+	return &accountRepo{db: db}
+}
+
+func (r *accountRepo) Delete(userID uuid.UUID, transferTo *uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var owned []models.Document
+		if err := tx.Unscoped().Select("id", "deleted_at").Where("owner_id = ?", userID).Find(&owned).Error; err != nil {
+			return err
+		}
+
+		var purge []uuid.UUID
+		for _, doc := range owned {
+			if transferTo == nil || doc.DeletedAt.Valid {
+				purge = append(purge, doc.ID)
+				continue
+			}
+			transferred, err := transferOwnership(tx, doc.ID, userID, *transferTo)
+			if err != nil {
+				return err
+			}
+			if transferred {
+				if err := resolveTransfers(tx.Where("document_id = ?", doc.ID), models.TransferStatusCancelled).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if err := purgeDocuments(tx, purge); err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Model(&models.Message{}).
+			Where("user_id = ?", userID).
+			Update("user_name", DeletedUserName).Error; err != nil {
+			return err
+		}
+		if err := resolveTransfers(tx.Where("from_user_id = ? OR to_user_id = ?", userID, userID), models.TransferStatusCancelled).Error; err != nil {
+			return err
+		}
+
+		var ownedTeams []uuid.UUID
+		if err := tx.Model(&models.Team{}).Where("owner_id = ?", userID).Pluck("id", &ownedTeams).Error; err != nil {
+			return err
+		}
+		if len(ownedTeams) > 0 {
+			for _, model := range []interface{}{&models.TeamMember{}, &models.TeamPermission{}} {
+				if err := tx.Where("team_id IN ?", ownedTeams).Delete(model).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Where("id IN ?", ownedTeams).Delete(&models.Team{}).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, model := range []interface{}{
+			&models.Permission{}, &models.TeamMember{}, &models.OrganizationMember{}, &models.Session{},
+			&models.PersonalAccessToken{}, &models.UserIdentity{}, &models.UserToken{}, &models.UserMFA{},
+			&models.RecoveryCode{}, &models.Notification{},
+		} {
+			if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("requester_id = ?", userID).Delete(&models.AccessRequest{}).Error; err != nil {
+			return err
+		}
+
+		// Kullanıcı adı ve e-posta benzersiz olduğundan silinen satırda kullanıcıya özgü yer tutucular kalır.
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"username":       fmt.Sprintf("deleted-%s", userID),
+			"email":          fmt.Sprintf("deleted-%s@deleted.invalid", userID),
+			"full_name":      "",
+			"pending_email":  "",
+			"password_hash":  "",
+			"email_verified": false,
+			"profile_image":  "",
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.User{}, "id = ?", userID).Error
+	})
+}
+
+// purgeDocuments, belgeleri ve belgelere bağlı tüm kayıtları kalıcı olarak siler.
+func purgeDocuments(tx *gorm.DB, documentIDs []uuid.UUID) error {
+	if len(documentIDs) == 0 {
+		return nil
+	}
+	for _, model := range []interface{}{
+		&models.DocumentVersion{}, &models.Permission{}, &models.TeamPermission{}, &models.ShareLink{},
+		&models.DocumentAttribution{}, &models.DocumentOperation{}, &models.VersionRetentionPolicy{}, &models.Comment{},
+		&models.Suggestion{}, &models.OwnershipTransfer{}, &models.EmailInvitation{},
+		&models.AccessRequest{}, &models.Notification{}, &models.Message{},
+	} {
+		if err := tx.Unscoped().Where("document_id IN ?", documentIDs).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Unscoped().Where("id IN ?", documentIDs).Delete(&models.Document{}).Error
+}
+
+func (r *accountRepo) Export(userID uuid.UUID) (*AccountData, error) {
+	data := &AccountData{}
+	if err := r.db.First(&data.User, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+
+	queries := []struct {
+		dest  interface{}
+		query *gorm.DB
+	}{
+		{&data.Documents, r.db.Unscoped().Where("owner_id = ?", userID)},
+		{&data.Permissions, r.db.Where("user_id = ?", userID)},
+		{&data.Messages, r.db.Where("user_id = ?", userID)},
+		{&data.Comments, r.db.Where("author_id = ?", userID)},
+		{&data.Suggestions, r.db.Where("author_id = ?", userID)},
+		{&data.Notifications, r.db.Where("user_id = ?", userID)},
+		{&data.Sessions, r.db.Where("user_id = ?", userID)},
+		{&data.AccessTokens, r.db.Where("user_id = ?", userID)},
+		{&data.Identities, r.db.Where("user_id = ?", userID)},
+		{&data.TeamMemberships, r.db.Where("user_id = ?", userID)},
+		{&data.OrganizationMemberships, r.db.Where("user_id = ?", userID)},
+		{&data.AccessRequests, r.db.Where("requester_id = ?", userID)},
+		{&data.Transfers, r.db.Where("from_user_id = ? OR to_user_id = ?", userID, userID)},
+	}
+	for _, q := range queries {
+		if err := q.query.Order("created_at").Find(q.dest).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var mfaCount int64
+	if err := r.db.Model(&models.UserMFA{}).Where("user_id = ? AND enabled = ?", userID, true).Count(&mfaCount).Error; err != nil {
+		return nil, err
+	}
+	data.MFAEnabled = mfaCount > 0
+	return data, nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// operationBatchSize, operasyon kaydı okunurken tek seferde alınan kayıt sayısıdır.
+const operationBatchSize = 500
+
+type AttributionRepository interface {
+	GetByDocumentID(documentID uuid.UUID) (*models.DocumentAttribution, error)
+	Save(attribution *models.DocumentAttribution) error
+	// RecordOperations, operasyonları sırayla kaydeder. Belgenin henüz hiç operasyon kaydı
+	// yoksa baseline (nil değilse) önce yazılır.
+	RecordOperations(baseline *models.DocumentOperation, operations []models.DocumentOperation) error
+	// ForEachOperation, afterID'den sonraki operasyonları kayıt sırasıyla fn'e verir.
+	ForEachOperation(documentID uuid.UUID, afterID int64, fn func(operation *models.DocumentOperation) error) error
+}
+
+type attributionRepo struct {
+	db *gorm.DB
+}
+
+func NewAttributionRepository(db *gorm.DB) AttributionRepository {
+	return &attributionRepo{db: db}
+}
+
+func (r *attributionRepo) GetByDocumentID(documentID uuid.UUID) (*models.DocumentAttribution, error) {
+	var attribution models.DocumentAttribution
+	if err := r.db.Where("document_id = ?", documentID).First(&attribution).Error; err != nil {
+		return nil, err
+	}
+	return &attribution, nil
+}
+
+func (r *attributionRepo) Save(attribution *models.DocumentAttribution) error {
+	return r.db.Save(attribution).Error
+}
+
+func (r *attributionRepo) RecordOperations(baseline *models.DocumentOperation, operations []models.DocumentOperation) error {
+	if len(operations) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if baseline != nil {
+			var count int64
+			if err := tx.Model(&models.DocumentOperation{}).
+				Where("document_id = ?", baseline.DocumentID).
+				Limit(1).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				if err := tx.Create(baseline).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Create(&operations).Error
+	})
+}
+
+func (r *attributionRepo) ForEachOperation(documentID uuid.UUID, afterID int64, fn func(operation *models.DocumentOperation) error) error {
+	lastID := afterID
+	for {
+		var batch []models.DocumentOperation
+		if err := r.db.Where("document_id = ? AND id > ?", documentID, lastID).
+			Order("id asc").
+			Limit(operationBatchSize).
+			Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		for i := range batch {
+			lastID = batch[i].ID
+			if err := fn(&batch[i]); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	Create(entry *models.AuditLog) error
+	// List, filtreye uyan kayıtları en yeniden eskiye döndürür.
+	List(filter AuditLogFilter) ([]models.AuditLog, int64, error)
+}
+
+// AuditLogFilter, denetim kaydı listesinin filtrelenmesi ve sayfalanması için kullanılır.
+// Boş alanlar filtrelenmez.
+type AuditLogFilter struct {
+	ActorID  *uuid.UUID
+	TargetID *uuid.UUID
+	Action   models.AuditAction
+	Limit    int
+	Offset   int
+}
+
+type auditLogRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepo{db: db}
+}
+
+func (r *auditLogRepo) Create(entry *models.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *auditLogRepo) List(filter AuditLogFilter) ([]models.AuditLog, int64, error) {
+	query := r.db.Model(&models.AuditLog{})
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.AuditLog
+	if err := query.Order("created_at desc").Offset(filter.Offset).Limit(filter.Limit).
+		Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
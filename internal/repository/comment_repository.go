@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CommentRepository interface {
+	Create(comment *models.Comment) error
+	GetByID(id any, comment *models.Comment) error
+	// GetByDocumentID, belgenin yorumlarını oluşturulma sırasıyla döndürür. includeResolved
+	// false ise çözülmüş dizilerin kök yorumları ve yanıtları hariç tutulur.
+	GetByDocumentID(documentID uuid.UUID, includeResolved bool) ([]models.Comment, error)
+	// GetThreads, belgenin kök yorumlarını (çözülmüşler dahil) döndürür.
+	GetThreads(documentID uuid.UUID) ([]models.Comment, error)
+	UpdateBody(commentID uuid.UUID, body string) error
+	UpdateAnchor(commentID uuid.UUID, start, length int) error
+	SetResolved(commentID uuid.UUID, resolvedBy *uuid.UUID, resolvedAt *time.Time) error
+	// Delete, yorumu ve kök yorumsa tüm yanıtlarını siler.
+	Delete(commentID uuid.UUID) error
+}
+
+type commentRepo struct {
+	*GenericRepository[models.Comment]
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepo{
+		GenericRepository: NewGenericRepository[models.Comment](db),
+		db:                db,
+	}
+}
+
+func (r *commentRepo) GetByDocumentID(documentID uuid.UUID, includeResolved bool) ([]models.Comment, error) {
+	query := r.db.Where("document_id = ?", documentID)
+	if !includeResolved {
+		resolvedThreads := r.db.Model(&models.Comment{}).
+			Select("id").
+			Where("document_id = ? AND parent_id IS NULL AND resolved_at IS NOT NULL", documentID)
+		query = query.Where("resolved_at IS NULL AND (parent_id IS NULL OR parent_id NOT IN (?))", resolvedThreads)
+	}
+
+	var comments []models.Comment
+	if err := query.Order("created_at").Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *commentRepo) GetThreads(documentID uuid.UUID) ([]models.Comment, error) {
+	var comments []models.Comment
+	if err := r.db.Where("document_id = ? AND parent_id IS NULL", documentID).
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *commentRepo) UpdateBody(commentID uuid.UUID, body string) error {
+	return r.db.Model(&models.Comment{}).
+		Where("id = ?", commentID).
+		Update("body", body).Error
+}
+
+func (r *commentRepo) UpdateAnchor(commentID uuid.UUID, start, length int) error {
+	return r.db.Model(&models.Comment{}).
+		Where("id = ?", commentID).
+		UpdateColumns(map[string]interface{}{"anchor_start": start, "anchor_length": length}).Error
+}
+
+func (r *commentRepo) SetResolved(commentID uuid.UUID, resolvedBy *uuid.UUID, resolvedAt *time.Time) error {
+	return r.db.Model(&models.Comment{}).
+		Where("id = ?", commentID).
+		Updates(map[string]interface{}{"resolved_by": resolvedBy, "resolved_at": resolvedAt}).Error
+}
+
+func (r *commentRepo) Delete(commentID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("parent_id = ?", commentID).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Comment{}, "id = ?", commentID).Error
+	})
+}
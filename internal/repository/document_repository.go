@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/dione-docs-backend/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -14,12 +17,37 @@ type DocumentRepository interface {
 	GetByOwnerID(ownerID uuid.UUID) ([]models.Document, error)
 	GetSharedWithUser(userID uuid.UUID) ([]models.Document, error)
 	SaveVersion(version *models.DocumentVersion) error
-	GetVersions(documentID uuid.UUID) ([]models.DocumentVersion, error)
+	GetVersions(documentID uuid.UUID, filter VersionFilter) ([]models.DocumentVersion, int64, error)
+	GetVersion(documentID uuid.UUID, version int) (*models.DocumentVersion, error)
+	UpdateVersionLabel(versionID uuid.UUID, name, note string, namedBy uuid.UUID) error
+	DeleteVersions(documentID uuid.UUID, versionIDs []uuid.UUID) (int64, error)
+	GetDocumentIDsWithVersions() ([]uuid.UUID, error)
+	ConvertLegacyVersions() (int, error)
+	// GetByIDWithDeleted, belgeyi silinmiş olsa bile getirir. Çalışma alanı sınırı uygulanmaz.
+	GetByIDWithDeleted(id uuid.UUID) (*models.Document, error)
+	// Restore, silinmiş belgeyi geri getirir. Belge bulunamazsa veya silinmemişse 0 döner.
+	Restore(id uuid.UUID) (int64, error)
+
+	// InOrganization, sorguları verilen çalışma alanıyla sınırlayan bir repository döndürür.
+	// Listeler yalnızca o alanın belgelerini içerir (nil kişisel alandır); organizasyon
+	// verilmişse GetByID de başka alanlardaki belgeleri bulamaz.
+	InOrganization(organizationID *uuid.UUID) DocumentRepository
+}
+
+// VersionFilter, versiyon listesinin filtrelenmesi ve sayfalanması için kullanılır.
+// Limit 0 ise tüm kayıtlar döner.
+type VersionFilter struct {
+	NamedOnly bool
+	Limit     int
+	Offset    int
 }
 
 type documentRepo struct {
 	*GenericRepository[models.Document]
 	db *gorm.DB
+
+	scoped         bool
+	organizationID *uuid.UUID
 }
 
 func NewDocumentRepository(db *gorm.DB) DocumentRepository {
@@ -29,13 +57,38 @@ func NewDocumentRepository(db *gorm.DB) DocumentRepository {
 	}
 }
 
+func (r *documentRepo) InOrganization(organizationID *uuid.UUID) DocumentRepository {
+	scoped := *r
+	scoped.scoped = true
+	scoped.organizationID = organizationID
+	return &scoped
+}
+
+// scope, repository bir çalışma alanıyla sınırlandırılmışsa sorguya alan filtresini ekler.
+func (r *documentRepo) scope(db *gorm.DB) *gorm.DB {
+	if !r.scoped {
+		return db
+	}
+	if r.organizationID == nil {
+		return db.Where("documents.organization_id IS NULL")
+	}
+	return db.Where("documents.organization_id = ?", *r.organizationID)
+}
+
+func (r *documentRepo) GetByID(id any, doc *models.Document) error {
+	if r.scoped && r.organizationID != nil {
+		return r.scope(r.db).First(doc, "documents.id = ?", id).Error
+	}
+	return r.GenericRepository.GetByID(id, doc)
+}
+
 func (r *documentRepo) Update(doc *models.Document) error {
 	return r.db.Save(doc).Error
 }
 
 func (r *documentRepo) GetByOwnerID(ownerID uuid.UUID) ([]models.Document, error) {
 	var docs []models.Document
-	if err := r.db.Where("owner_id = ?", ownerID).Find(&docs).Error; err != nil {
+	if err := r.scope(r.db).Where("owner_id = ?", ownerID).Find(&docs).Error; err != nil {
 		return nil, err
 	}
 	return docs, nil
@@ -43,24 +96,158 @@ func (r *documentRepo) GetByOwnerID(ownerID uuid.UUID) ([]models.Document, error
 
 func (r *documentRepo) GetSharedWithUser(userID uuid.UUID) ([]models.Document, error) {
 	var docs []models.Document
-	if err := r.db.Joins("JOIN permissions ON permissions.document_id = documents.id").
-		Where("permissions.user_id = ?", userID).
+	direct := notExpired(r.db.Model(&models.Permission{}), "expires_at").
+		Select("document_id").
+		Where("user_id = ? AND status <> ?", userID, models.PermissionStatusExpired)
+	team := r.db.Model(&models.TeamPermission{}).
+		Select("team_permissions.document_id").
+		Joins("JOIN team_members ON team_members.team_id = team_permissions.team_id").
+		Where("team_members.user_id = ?", userID)
+	organization := r.db.Model(&models.Document{}).
+		Select("documents.id").
+		Joins("JOIN organizations ON organizations.id = documents.organization_id").
+		Joins("JOIN organization_members ON organization_members.organization_id = documents.organization_id").
+		Where("organization_members.user_id = ?", userID).
+		Where("organization_members.role IN ? OR (organization_members.role = ? AND organizations.default_access <> '')",
+			[]models.OrganizationRole{models.OrganizationRoleOwner, models.OrganizationRoleAdmin}, models.OrganizationRoleMember)
+
+	if err := r.scope(r.db).
+		Where("documents.id IN (?) OR documents.id IN (?) OR documents.id IN (?)", direct, team, organization).
+		Where("documents.owner_id <> ?", userID).
 		Find(&docs).Error; err != nil {
 		return nil, err
 	}
 	return docs, nil
 }
 
+// SaveVersion, versiyonu kaydeder. İçerik mümkünse son snapshot'a göre Delta farkı
+// olarak saklanır; bu durumda version.Content kaydedilen fark ile değiştirilir.
+// Aynı numaralı kayıt zaten varsa (isimlendirilmek için önceden saklanmış güncel versiyon)
+// içerik yeniden yazılmaz, yalnızca değişikliği yapan kişi ve işlem bilgisi güncellenir.
 func (r *documentRepo) SaveVersion(version *models.DocumentVersion) error {
-	return r.db.Create(version).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.DocumentVersion
+		err := tx.Select("id").
+			Where("document_id = ? AND version = ?", version.DocumentID, version.Version).
+			Take(&existing).Error
+		if err == nil {
+			version.ID = existing.ID
+			return tx.Model(&existing).Updates(map[string]interface{}{
+				"changed_by":            version.ChangedBy,
+				"action":                version.Action,
+				"restored_from_version": version.RestoredFromVersion,
+			}).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := encodeVersion(tx, version); err != nil {
+			return err
+		}
+		return tx.Create(version).Error
+	})
 }
 
-func (r *documentRepo) GetVersions(documentID uuid.UUID) ([]models.DocumentVersion, error) {
+// GetVersions, versiyonları içerik (content) olmadan listeler ve filtreye uyan
+// toplam kayıt sayısını döndürür. İçerik için GetVersion kullanılmalıdır.
+func (r *documentRepo) GetVersions(documentID uuid.UUID, filter VersionFilter) ([]models.DocumentVersion, int64, error) {
+	query := r.db.Model(&models.DocumentVersion{}).Where("document_id = ?", documentID)
+	if filter.NamedOnly {
+		query = query.Where("name <> ''")
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
 	var versions []models.DocumentVersion
-	if err := r.db.Where("document_id = ?", documentID).
-		Order("version desc").
-		Find(&versions).Error; err != nil {
+	listQuery := query.Omit("content").Order("version desc").Offset(filter.Offset)
+	if filter.Limit > 0 {
+		listQuery = listQuery.Limit(filter.Limit)
+	}
+	if err := listQuery.Find(&versions).Error; err != nil {
+		return nil, 0, err
+	}
+	return versions, total, nil
+}
+
+func (r *documentRepo) GetVersion(documentID uuid.UUID, version int) (*models.DocumentVersion, error) {
+	var v models.DocumentVersion
+	if err := r.db.Where("document_id = ? AND version = ?", documentID, version).
+		Order("created_at desc").
+		First(&v).Error; err != nil {
 		return nil, err
 	}
-	return versions, nil
+	if err := decodeVersion(r.db, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// UpdateVersionLabel, versiyonun adını ve notunu günceller. İsimlendiren kişi ve zaman
+// yalnızca ad değiştiğinde yazılır; sadece not değişirse ilk isimlendirme bilgisi korunur.
+func (r *documentRepo) UpdateVersionLabel(versionID uuid.UUID, name, note string, namedBy uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.DocumentVersion
+		if err := tx.Select("id", "name").Where("id = ?", versionID).Take(&current).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"name": name,
+			"note": note,
+		}
+		if name != current.Name {
+			updates["named_by"] = nil
+			updates["named_at"] = nil
+			if name != "" {
+				updates["named_by"] = namedBy
+				updates["named_at"] = time.Now()
+			}
+		}
+		return tx.Model(&models.DocumentVersion{}).
+			Where("id = ?", versionID).
+			Updates(updates).Error
+	})
+}
+
+// DeleteVersions, verilen versiyonları siler. İsimlendirilmiş versiyonlar ve hâlâ
+// Delta farklarının dayandığı snapshot'lar listede olsalar bile korunur; silinen
+// kayıt sayısı döndürülür.
+func (r *documentRepo) DeleteVersions(documentID uuid.UUID, versionIDs []uuid.UUID) (int64, error) {
+	if len(versionIDs) == 0 {
+		return 0, nil
+	}
+	result := r.db.Where("document_id = ? AND id IN ? AND name = ''", documentID, versionIDs).
+		Where("NOT EXISTS (SELECT 1 FROM document_versions dependent WHERE dependent.base_version_id = document_versions.id)").
+		Delete(&models.DocumentVersion{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *documentRepo) GetDocumentIDsWithVersions() ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := r.db.Model(&models.DocumentVersion{}).
+		Distinct("document_id").
+		Pluck("document_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *documentRepo) GetByIDWithDeleted(id uuid.UUID) (*models.Document, error) {
+	var doc models.Document
+	if err := r.db.Unscoped().First(&doc, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *documentRepo) Restore(id uuid.UUID) (int64, error) {
+	result := r.db.Unscoped().Model(&models.Document{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	return result.RowsAffected, result.Error
 }
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EmailInvitationRepository interface {
+	Create(invitation *models.EmailInvitation) error
+	Update(invitation *models.EmailInvitation) error
+	// GetPending, belge için e-posta adresine gönderilmiş bekleyen daveti döndürür.
+	GetPending(documentID uuid.UUID, email string) (*models.EmailInvitation, error)
+	GetPendingByDocument(documentID uuid.UUID) ([]models.EmailInvitation, error)
+	DeletePending(documentID uuid.UUID, email string) (int64, error)
+	// ConvertForUser, kullanıcının e-posta adresine gönderilmiş bekleyen davetleri bekleyen
+	// Permission davetiyelerine çevirir ve oluşturulan davetiye sayısını döndürür. Süresi
+	// dolmuş davetler expired olarak işaretlenir; kullanıcının zaten izni olan belgeler atlanır.
+	ConvertForUser(user *models.User) (int, error)
+}
+
+type emailInvitationRepo struct {
+	*GenericRepository[models.EmailInvitation]
+	db *gorm.DB
+}
+
+func NewEmailInvitationRepository(db *gorm.DB) EmailInvitationRepository {
+	return &emailInvitationRepo{
+		GenericRepository: NewGenericRepository[models.EmailInvitation](db),
+		db:                db,
+	}
+}
+
+func (r *emailInvitationRepo) Update(invitation *models.EmailInvitation) error {
+	return r.db.Save(invitation).Error
+}
+
+func (r *emailInvitationRepo) GetPending(documentID uuid.UUID, email string) (*models.EmailInvitation, error) {
+	var invitation models.EmailInvitation
+	if err := r.db.Where("document_id = ? AND email = ? AND status = ?", documentID, models.NormalizeEmail(email), models.EmailInvitationStatusPending).
+		First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *emailInvitationRepo) GetPendingByDocument(documentID uuid.UUID) ([]models.EmailInvitation, error) {
+	var invitations []models.EmailInvitation
+	if err := r.db.Where("document_id = ? AND status = ?", documentID, models.EmailInvitationStatusPending).
+		Order("created_at").
+		Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *emailInvitationRepo) DeletePending(documentID uuid.UUID, email string) (int64, error) {
+	result := r.db.Where("document_id = ? AND email = ? AND status = ?", documentID, models.NormalizeEmail(email), models.EmailInvitationStatusPending).
+		Delete(&models.EmailInvitation{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *emailInvitationRepo) ConvertForUser(user *models.User) (int, error) {
+	converted := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var invitations []models.EmailInvitation
+		if err := tx.Where("email = ? AND status = ?", models.NormalizeEmail(user.Email), models.EmailInvitationStatusPending).
+			Order("created_at").
+			Find(&invitations).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, invitation := range invitations {
+			status := models.EmailInvitationStatusConverted
+			if invitation.ExpiresAt != nil && !now.Before(*invitation.ExpiresAt) {
+				status = models.EmailInvitationStatusExpired
+			} else {
+				created, err := createInvitationPermission(tx, &invitation, user.ID)
+				if err != nil {
+					return err
+				}
+				if created {
+					converted++
+				}
+			}
+
+			if err := tx.Model(&models.EmailInvitation{}).
+				Where("id = ?", invitation.ID).
+				Update("status", status).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return converted, nil
+}
+
+// createInvitationPermission, e-posta davetini kullanıcı için bekleyen bir Permission davetiyesine
+// çevirir. Kullanıcının belgede zaten bekleyen veya kabul edilmiş bir kaydı varsa false döner.
+func createInvitationPermission(tx *gorm.DB, invitation *models.EmailInvitation, userID uuid.UUID) (bool, error) {
+	var existing models.Permission
+	err := tx.Where("document_id = ? AND user_id = ? AND status IN ?", invitation.DocumentID, userID,
+		[]models.PermissionStatus{models.PermissionStatusPending, models.PermissionStatusAccepted}).
+		First(&existing).Error
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+
+	if err := tx.Create(&models.Permission{
+		DocumentID: invitation.DocumentID,
+		UserID:     userID,
+		AccessType: invitation.AccessType,
+		Status:     models.PermissionStatusPending,
+		SharedBy:   invitation.InvitedBy,
+		ExpiresAt:  invitation.ExpiresAt,
+	}).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MFARepository interface {
+	GetByUserID(userID uuid.UUID) (*models.UserMFA, error)
+	// IsEnabled, kullanıcının doğrulanmış bir TOTP ayarı olup olmadığını döndürür.
+	IsEnabled(userID uuid.UUID) (bool, error)
+	// Save, kullanıcının TOTP ayarını oluşturur veya günceller.
+	Save(mfa *models.UserMFA) error
+	// Enable, ayarı etkinleştirir ve kullanıcının kurtarma kodlarını codeHashes ile değiştirir.
+	Enable(userID uuid.UUID, step int64, codeHashes []string) error
+	// Delete, kullanıcının TOTP ayarını ve kurtarma kodlarını siler.
+	Delete(userID uuid.UUID) error
+	// UseStep, step son kullanılan adımdan büyükse onu kaydeder; aynı kod tekrar gelirse false döner.
+	UseStep(userID uuid.UUID, step int64) (bool, error)
+	ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error
+	// UseRecoveryCode, kullanılmamış kurtarma kodunu kullanıldı olarak işaretler. Kod yoksa false döner.
+	UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error)
+	CountRecoveryCodes(userID uuid.UUID) (int64, error)
+}
+
+type mfaRepo struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepo{db: db}
+}
+
+func (r *mfaRepo) GetByUserID(userID uuid.UUID) (*models.UserMFA, error) {
+	var mfa models.UserMFA
+	if err := r.db.Where("user_id = ?", userID).First(&mfa).Error; err != nil {
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+func (r *mfaRepo) IsEnabled(userID uuid.UUID) (bool, error) {
+	_, err := r.getEnabled(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *mfaRepo) getEnabled(userID uuid.UUID) (*models.UserMFA, error) {
+	var mfa models.UserMFA
+	if err := r.db.Where("user_id = ? AND enabled = ?", userID, true).First(&mfa).Error; err != nil {
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+func (r *mfaRepo) Save(mfa *models.UserMFA) error {
+	return r.db.Save(mfa).Error
+}
+
+func (r *mfaRepo) Enable(userID uuid.UUID, step int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.UserMFA{}).
+			Where("user_id = ?", userID).
+			Updates(map[string]interface{}{
+				"enabled":        true,
+				"confirmed_at":   time.Now(),
+				"last_used_step": step,
+			}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func (r *mfaRepo) Delete(userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserMFA{}).Error
+	})
+}
+
+func (r *mfaRepo) UseStep(userID uuid.UUID, step int64) (bool, error) {
+	result := r.db.Model(&models.UserMFA{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *mfaRepo) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func (r *mfaRepo) UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	result := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *mfaRepo) CountRecoveryCodes(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]models.RecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = models.RecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
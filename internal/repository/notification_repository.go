@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	GetByUserID(userID uuid.UUID, unreadOnly bool, limit, offset int) ([]models.Notification, int64, error)
+	MarkRead(userID, notificationID uuid.UUID) (int64, error)
+	MarkAllRead(userID uuid.UUID) error
+}
+
+type notificationRepo struct {
+	*GenericRepository[models.Notification]
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepo{
+		GenericRepository: NewGenericRepository[models.Notification](db),
+		db:                db,
+	}
+}
+
+func (r *notificationRepo) GetByUserID(userID uuid.UUID, unreadOnly bool, limit, offset int) ([]models.Notification, int64, error) {
+	query := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []models.Notification
+	if err := query.Order("created_at desc").Offset(offset).Limit(limit).
+		Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+func (r *notificationRepo) MarkRead(userID, notificationID uuid.UUID) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", notificationID, userID).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+func (r *notificationRepo) MarkAllRead(userID uuid.UUID) error {
+	return r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+}
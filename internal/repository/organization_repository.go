@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository interface {
+	// Create, organizasyonu oluşturur ve oluşturan kullanıcıyı owner olarak ekler.
+	Create(organization *models.Organization) error
+	GetByID(id any, organization *models.Organization) error
+	Update(organization *models.Organization) error
+	// Delete, organizasyonu ve üyeliklerini siler.
+	Delete(organizationID uuid.UUID) error
+	GetByUserID(userID uuid.UUID) ([]models.Organization, error)
+	CountDocuments(organizationID uuid.UUID) (int64, error)
+
+	GetMembers(organizationID uuid.UUID) ([]models.OrganizationMember, error)
+	GetMember(organizationID, userID uuid.UUID) (*models.OrganizationMember, error)
+	AddMember(member *models.OrganizationMember) error
+	UpdateMemberRole(organizationID, userID uuid.UUID, role models.OrganizationRole) error
+	RemoveMember(organizationID, userID uuid.UUID) error
+	CountOwners(organizationID uuid.UUID) (int64, error)
+}
+
+type organizationRepo struct {
+	*GenericRepository[models.Organization]
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepo{
+		GenericRepository: NewGenericRepository[models.Organization](db),
+		db:                db,
+	}
+}
+
+func (r *organizationRepo) Create(organization *models.Organization) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organization).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.OrganizationMember{
+			OrganizationID: organization.ID,
+			UserID:         organization.CreatedBy,
+			Role:           models.OrganizationRoleOwner,
+		}).Error
+	})
+}
+
+func (r *organizationRepo) Update(organization *models.Organization) error {
+	return r.db.Save(organization).Error
+}
+
+func (r *organizationRepo) Delete(organizationID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organization_id = ?", organizationID).Delete(&models.OrganizationMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Organization{}, "id = ?", organizationID).Error
+	})
+}
+
+func (r *organizationRepo) GetByUserID(userID uuid.UUID) ([]models.Organization, error) {
+	var organizations []models.Organization
+	if err := r.db.Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		Order("organizations.name").
+		Find(&organizations).Error; err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
+
+func (r *organizationRepo) CountDocuments(organizationID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Document{}).
+		Where("organization_id = ?", organizationID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *organizationRepo) GetMembers(organizationID uuid.UUID) ([]models.OrganizationMember, error) {
+	var members []models.OrganizationMember
+	if err := r.db.Where("organization_id = ?", organizationID).
+		Order("created_at").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *organizationRepo) GetMember(organizationID, userID uuid.UUID) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	if err := r.db.Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *organizationRepo) AddMember(member *models.OrganizationMember) error {
+	return r.db.Create(member).Error
+}
+
+func (r *organizationRepo) UpdateMemberRole(organizationID, userID uuid.UUID, role models.OrganizationRole) error {
+	return r.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Update("role", role).Error
+}
+
+func (r *organizationRepo) RemoveMember(organizationID, userID uuid.UUID) error {
+	return r.db.Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Delete(&models.OrganizationMember{}).Error
+}
+
+func (r *organizationRepo) CountOwners(organizationID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", organizationID, models.OrganizationRoleOwner).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// organizationScope, belgeye bağlı kayıtların (izin, erişim isteği, devir isteği) sorgularını
+// bir çalışma alanıyla sınırlar. Sıfır değeri sınırlama yapmaz; organizationID nil ise
+// kişisel alan kastedilir.
+type organizationScope struct {
+	scoped         bool
+	organizationID *uuid.UUID
+}
+
+func newOrganizationScope(organizationID *uuid.UUID) organizationScope {
+	return organizationScope{scoped: true, organizationID: organizationID}
+}
+
+// apply, column'daki belge ID'sinin çalışma alanındaki bir belgeye ait olmasını şart koşar.
+func (s organizationScope) apply(db *gorm.DB, column string) *gorm.DB {
+	if !s.scoped {
+		return db
+	}
+	documents := db.Session(&gorm.Session{NewDB: true}).Model(&models.Document{}).Select("id")
+	if s.organizationID == nil {
+		documents = documents.Where("organization_id IS NULL")
+	} else {
+		documents = documents.Where("organization_id = ?", *s.organizationID)
+	}
+	return db.Where(column+" IN (?)", documents)
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrTransferNotPending, devir isteği artık beklemede olmadığında veya belgenin sahibi
+// istekten sonra değiştiğinde döner.
+var ErrTransferNotPending = errors.New("ownership transfer is no longer pending")
+
+type OwnershipTransferRepository interface {
+	Create(transfer *models.OwnershipTransfer) error
+	GetByID(id any, transfer *models.OwnershipTransfer) error
+	GetPendingByDocument(documentID uuid.UUID) (*models.OwnershipTransfer, error)
+	// GetPendingByRecipient, kullanıcıya gönderilmiş bekleyen devir isteklerini en yeniden eskiye döndürür.
+	GetPendingByRecipient(userID uuid.UUID) ([]models.OwnershipTransfer, error)
+	// Resolve, bekleyen isteği reddedildi veya iptal edildi olarak işaretler. İstek artık
+	// beklemede değilse false döner.
+	Resolve(transferID uuid.UUID, status models.TransferStatus) (bool, error)
+	// Accept, bekleyen isteği kabul eder ve sahipliği tek bir transaction içinde devreder.
+	// İstek beklemede değilse veya belgenin sahibi değişmişse ErrTransferNotPending döner.
+	Accept(transfer *models.OwnershipTransfer) error
+	// TransferDocuments, belgelerin sahipliğini onay beklemeden from'dan to'ya devreder ve
+	// bu belgelerdeki bekleyen devir isteklerini iptal eder. Sahibi from olmayan belgeler atlanır.
+	TransferDocuments(documentIDs []uuid.UUID, from, to uuid.UUID) (int, error)
+
+	// InOrganization, kullanıcıya göre listelenen istekleri verilen çalışma alanındaki
+	// belgelerle sınırlayan bir repository döndürür (nil kişisel alandır).
+	InOrganization(organizationID *uuid.UUID) OwnershipTransferRepository
+}
+
+type ownershipTransferRepo struct {
+	*GenericRepository[models.OwnershipTransfer]
+	db *gorm.DB
+
+	organization organizationScope
+}
+
+func NewOwnershipTransferRepository(db *gorm.DB) OwnershipTransferRepository {
+	return &ownershipTransferRepo{
+		GenericRepository: NewGenericRepository[models.OwnershipTransfer](db),
+		db:                db,
+	}
+}
+
+func (r *ownershipTransferRepo) InOrganization(organizationID *uuid.UUID) OwnershipTransferRepository {
+	scoped := *r
+	scoped.organization = newOrganizationScope(organizationID)
+	return &scoped
+}
+
+func (r *ownershipTransferRepo) GetPendingByDocument(documentID uuid.UUID) (*models.OwnershipTransfer, error) {
+	var transfer models.OwnershipTransfer
+	if err := r.db.Where("document_id = ? AND status = ?", documentID, models.TransferStatusPending).
+		First(&transfer).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *ownershipTransferRepo) GetPendingByRecipient(userID uuid.UUID) ([]models.OwnershipTransfer, error) {
+	var transfers []models.OwnershipTransfer
+	if err := r.organization.apply(r.db, "document_id").
+		Where("to_user_id = ? AND status = ?", userID, models.TransferStatusPending).
+		Order("created_at desc").
+		Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+func (r *ownershipTransferRepo) Resolve(transferID uuid.UUID, status models.TransferStatus) (bool, error) {
+	result := resolveTransfers(r.db.Where("id = ?", transferID), status)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ownershipTransferRepo) Accept(transfer *models.OwnershipTransfer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := resolveTransfers(tx.Where("id = ?", transfer.ID), models.TransferStatusAccepted)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTransferNotPending
+		}
+
+		transferred, err := transferOwnership(tx, transfer.DocumentID, transfer.FromUserID, transfer.ToUserID)
+		if err != nil {
+			return err
+		}
+		if !transferred {
+			return ErrTransferNotPending
+		}
+		return nil
+	})
+}
+
+func (r *ownershipTransferRepo) TransferDocuments(documentIDs []uuid.UUID, from, to uuid.UUID) (int, error) {
+	count := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, documentID := range documentIDs {
+			transferred, err := transferOwnership(tx, documentID, from, to)
+			if err != nil {
+				return err
+			}
+			if !transferred {
+				continue
+			}
+			if err := resolveTransfers(tx.Where("document_id = ?", documentID), models.TransferStatusCancelled).Error; err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// resolveTransfers, sorgunun kapsadığı bekleyen istekleri status durumuna çeker.
+func resolveTransfers(query *gorm.DB, status models.TransferStatus) *gorm.DB {
+	return query.Model(&models.OwnershipTransfer{}).
+		Where("status = ?", models.TransferStatusPending).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"responded_at": time.Now(),
+		})
+}
+
+// transferOwnership, belgenin sahibini from'dan to'ya çevirir. Yeni sahibin artık gereksiz olan
+// izinleri silinir, önceki sahip belgede kalıcı admin izni alır. Belgenin sahibi from değilse
+// hiçbir şey yapmadan false döner.
+func transferOwnership(tx *gorm.DB, documentID, from, to uuid.UUID) (bool, error) {
+	result := tx.Model(&models.Document{}).
+		Where("id = ? AND owner_id = ?", documentID, from).
+		Update("owner_id", to)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	if err := tx.Where("document_id = ? AND user_id IN ?", documentID, []uuid.UUID{from, to}).
+		Delete(&models.Permission{}).Error; err != nil {
+		return false, err
+	}
+	if err := tx.Create(&models.Permission{
+		DocumentID: documentID,
+		UserID:     from,
+		AccessType: string(models.AccessTypeAdmin),
+		Status:     models.PermissionStatusAccepted,
+		SharedBy:   to,
+	}).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/dione-docs-backend/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -19,11 +21,36 @@ type PermissionRepository interface {
 	GetPendingInvitationsByUserID(userID uuid.UUID) ([]models.Permission, error)
 	UpdateStatus(permissionID uuid.UUID, status models.PermissionStatus) error
 	GetByDocumentAndUserWithAnyStatus(documentID, userID uuid.UUID) (*models.Permission, error) // Herhangi bir statüdeki izni/davetiyeyi getirir
+
+	// GetEffectiveAccessType, kullanıcının doğrudan, takım ve organizasyon üyeliğinden gelen
+	// izinlerinden en yetkili erişim tipini döndürür. Belge sahipliği dikkate alınmaz; izin yoksa "" döner.
+	GetEffectiveAccessType(documentID, userID uuid.UUID) (string, error)
+
+	UpdateExpiresAt(permissionID uuid.UUID, expiresAt *time.Time) error
+	// ExpireDue, süresi dolmuş kabul edilmiş izinleri 'expired' durumuna çeker ve bunları döndürür.
+	ExpireDue(now time.Time) ([]models.Permission, error)
+	// UpdateShareLink, iznin hangi paylaşım bağlantısından geldiğini kaydeder (nil doğrudan paylaşımdır).
+	UpdateShareLink(permissionID uuid.UUID, linkID *uuid.UUID) error
+	// DeleteByShareLink, paylaşım bağlantısının verdiği tüm izinleri siler.
+	DeleteByShareLink(linkID uuid.UUID) error
+	// GetAllByDocument, belgenin bekleyen, reddedilen ve süresi dolanlar dahil tüm izinlerini döndürür.
+	GetAllByDocument(documentID uuid.UUID) ([]models.Permission, error)
+
+	// InOrganization, kullanıcıya göre listelenen izinleri verilen çalışma alanındaki
+	// belgelerle sınırlayan bir repository döndürür (nil kişisel alandır).
+	InOrganization(organizationID *uuid.UUID) PermissionRepository
+}
+
+// notExpired, süresi dolmuş izinleri sorgudan hariç tutar.
+func notExpired(db *gorm.DB, column string) *gorm.DB {
+	return db.Where(column+" IS NULL OR "+column+" > ?", time.Now())
 }
 
 type permissionRepo struct {
 	*GenericRepository[models.Permission]
 	db *gorm.DB
+
+	organization organizationScope
 }
 
 func NewPermissionRepository(db *gorm.DB) PermissionRepository {
@@ -33,6 +60,12 @@ func NewPermissionRepository(db *gorm.DB) PermissionRepository {
 	}
 }
 
+func (r *permissionRepo) InOrganization(organizationID *uuid.UUID) PermissionRepository {
+	scoped := *r
+	scoped.organization = newOrganizationScope(organizationID)
+	return &scoped
+}
+
 // Create metodu artık status'ü dikkate alarak çalışacak,
 // ShareDocument handler'ında permission objesi oluşturulurken Status="pending" ve SharedBy atanmalı.
 // GenericRepository.Create kullanıldığı için burada özel bir Create implementasyonuna gerek yok,
@@ -43,7 +76,8 @@ func NewPermissionRepository(db *gorm.DB) PermissionRepository {
 // Eğer herhangi bir statüdeki izni getirmek isterseniz GetByDocumentAndUserWithAnyStatus metodunu kullanın.
 func (r *permissionRepo) GetByDocumentAndUser(documentID, userID uuid.UUID) (*models.Permission, error) {
 	var permission models.Permission
-	if err := r.db.Where("document_id = ? AND user_id = ? AND status = ?", documentID, userID, models.PermissionStatusAccepted).
+	if err := notExpired(r.db, "expires_at").
+		Where("document_id = ? AND user_id = ? AND status = ?", documentID, userID, models.PermissionStatusAccepted).
 		First(&permission).Error; err != nil {
 		return nil, err
 	}
@@ -51,10 +85,12 @@ func (r *permissionRepo) GetByDocumentAndUser(documentID, userID uuid.UUID) (*mo
 }
 
 // GetAcceptedByDocumentAndUser sadece kabul edilmiş ve belirli bir accessType'a sahip izni getirir.
+// Süresi dolmuş izinler, süpürücü henüz işaretlememiş olsa bile yok sayılır.
 // Middleware veya yetkilendirme kontrolleri için kullanılabilir.
 func (r *permissionRepo) GetAcceptedByDocumentAndUser(documentID, userID uuid.UUID) (*models.Permission, error) {
 	var permission models.Permission
-	if err := r.db.Where("document_id = ? AND user_id = ? AND status = ?", documentID, userID, models.PermissionStatusAccepted).
+	if err := notExpired(r.db, "expires_at").
+		Where("document_id = ? AND user_id = ? AND status = ?", documentID, userID, models.PermissionStatusAccepted).
 		First(&permission).Error; err != nil {
 		return nil, err
 	}
@@ -77,7 +113,8 @@ func (r *permissionRepo) GetByDocumentAndUserWithAnyStatus(documentID, userID uu
 // Eğer tüm statüleri listelemek gerekirse yeni bir metod eklenebilir.
 func (r *permissionRepo) GetByDocument(documentID uuid.UUID) ([]models.Permission, error) {
 	var permissions []models.Permission
-	if err := r.db.Where("document_id = ? AND status = ?", documentID, models.PermissionStatusAccepted).
+	if err := notExpired(r.db, "expires_at").
+		Where("document_id = ? AND status = ?", documentID, models.PermissionStatusAccepted).
 		Find(&permissions).Error; err != nil {
 		return nil, err
 	}
@@ -103,7 +140,8 @@ func (r *permissionRepo) DeleteByDocumentAndUser(documentID, userID uuid.UUID) e
 
 func (r *permissionRepo) GetPendingInvitationsByUserID(userID uuid.UUID) ([]models.Permission, error) {
 	var invitations []models.Permission
-	if err := r.db.Where("user_id = ? AND status = ?", userID, models.PermissionStatusPending).
+	if err := r.organization.apply(r.db, "document_id").
+		Where("user_id = ? AND status = ?", userID, models.PermissionStatusPending).
 		Order("created_at desc").
 		Find(&invitations).Error; err != nil {
 		return nil, err
@@ -116,3 +154,92 @@ func (r *permissionRepo) UpdateStatus(permissionID uuid.UUID, status models.Perm
 		Where("id = ?", permissionID).
 		Update("status", status).Error
 }
+
+func (r *permissionRepo) UpdateExpiresAt(permissionID uuid.UUID, expiresAt *time.Time) error {
+	return r.db.Model(&models.Permission{}).
+		Where("id = ?", permissionID).
+		Update("expires_at", expiresAt).Error
+}
+
+func (r *permissionRepo) UpdateShareLink(permissionID uuid.UUID, linkID *uuid.UUID) error {
+	return r.db.Model(&models.Permission{}).
+		Where("id = ?", permissionID).
+		Update("share_link_id", linkID).Error
+}
+
+func (r *permissionRepo) DeleteByShareLink(linkID uuid.UUID) error {
+	return r.db.Where("share_link_id = ?", linkID).
+		Delete(&models.Permission{}).Error
+}
+
+func (r *permissionRepo) ExpireDue(now time.Time) ([]models.Permission, error) {
+	var expired []models.Permission
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ?", models.PermissionStatusAccepted, now).
+			Find(&expired).Error; err != nil {
+			return err
+		}
+		for _, permission := range expired {
+			// (document_id, user_id, status) tekil olduğundan önceki süresi dolmuş kayıt kaldırılır.
+			if err := tx.Where("document_id = ? AND user_id = ? AND status = ?", permission.DocumentID, permission.UserID, models.PermissionStatusExpired).
+				Delete(&models.Permission{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Permission{}).
+				Where("id = ?", permission.ID).
+				Update("status", models.PermissionStatusExpired).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+func (r *permissionRepo) GetEffectiveAccessType(documentID, userID uuid.UUID) (string, error) {
+	var direct []string
+	if err := notExpired(r.db.Model(&models.Permission{}), "expires_at").
+		Where("document_id = ? AND user_id = ? AND status = ?", documentID, userID, models.PermissionStatusAccepted).
+		Pluck("access_type", &direct).Error; err != nil {
+		return "", err
+	}
+
+	var team []string
+	if err := r.db.Model(&models.TeamPermission{}).
+		Joins("JOIN team_members ON team_members.team_id = team_permissions.team_id").
+		Where("team_permissions.document_id = ? AND team_members.user_id = ?", documentID, userID).
+		Pluck("team_permissions.access_type", &team).Error; err != nil {
+		return "", err
+	}
+
+	var organization []struct {
+		Role          models.OrganizationRole
+		DefaultAccess string
+	}
+	if err := r.db.Table("documents").
+		Select("organization_members.role, organizations.default_access").
+		Joins("JOIN organizations ON organizations.id = documents.organization_id").
+		Joins("JOIN organization_members ON organization_members.organization_id = documents.organization_id").
+		Where("documents.id = ? AND organization_members.user_id = ?", documentID, userID).
+		Scan(&organization).Error; err != nil {
+		return "", err
+	}
+
+	accessTypes := append(direct, team...)
+	for _, membership := range organization {
+		member := models.OrganizationMember{Role: membership.Role}
+		accessTypes = append(accessTypes, member.DocumentAccess(membership.DefaultAccess))
+	}
+	return models.HighestAccess(accessTypes...), nil
+}
+
+func (r *permissionRepo) GetAllByDocument(documentID uuid.UUID) ([]models.Permission, error) {
+	var permissions []models.Permission
+	if err := r.db.Where("document_id = ?", documentID).Order("created_at").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
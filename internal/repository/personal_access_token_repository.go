@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PersonalAccessTokenRepository interface {
+	Create(token *models.PersonalAccessToken) error
+	GetByHash(tokenHash string) (*models.PersonalAccessToken, error)
+	// GetActiveByUserID, kullanıcının iptal edilmemiş token'larını en yeniden eskiye döndürür.
+	GetActiveByUserID(userID uuid.UUID) ([]models.PersonalAccessToken, error)
+	Touch(tokenID uuid.UUID) error
+	// Revoke, kullanıcının token'ını iptal eder. Token bulunamazsa veya zaten iptal edilmişse 0 döner.
+	Revoke(userID, tokenID uuid.UUID) (int64, error)
+	// RevokeAll, kullanıcının iptal edilmemiş tüm token'larını iptal eder.
+	RevokeAll(userID uuid.UUID) (int64, error)
+}
+
+type personalAccessTokenRepo struct {
+	*GenericRepository[models.PersonalAccessToken]
+	db *gorm.DB
+}
+
+func NewPersonalAccessTokenRepository(db *gorm.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepo{
+		GenericRepository: NewGenericRepository[models.PersonalAccessToken](db),
+		db:                db,
+	}
+}
+
+func (r *personalAccessTokenRepo) GetByHash(tokenHash string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *personalAccessTokenRepo) GetActiveByUserID(userID uuid.UUID) ([]models.PersonalAccessToken, error) {
+	var tokens []models.PersonalAccessToken
+	if err := r.db.Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("created_at desc").
+		Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *personalAccessTokenRepo) Touch(tokenID uuid.UUID) error {
+	return r.db.Model(&models.PersonalAccessToken{}).
+		Where("id = ?", tokenID).
+		Update("last_used_at", time.Now()).Error
+}
+
+func (r *personalAccessTokenRepo) Revoke(userID, tokenID uuid.UUID) (int64, error) {
+	result := r.db.Model(&models.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", tokenID, userID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+func (r *personalAccessTokenRepo) RevokeAll(userID uuid.UUID) (int64, error) {
+	result := r.db.Model(&models.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/ratelimit"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RateLimitRepository, hız sınırı durumunu Postgres'te tutan ratelimit.Store uygulamasıdır.
+// Birden fazla sunucu aynı kovaları paylaşır; her güncelleme satır kilidiyle yapılır.
+type RateLimitRepository interface {
+	ratelimit.Store
+}
+
+type rateLimitRepo struct {
+	db *gorm.DB
+}
+
+func NewRateLimitRepository(db *gorm.DB) RateLimitRepository {
+	return &rateLimitRepo{db: db}
+}
+
+func (r *rateLimitRepo) Take(ctx context.Context, key string, rule ratelimit.Rule) (ratelimit.Result, error) {
+	var result ratelimit.Result
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		// Sıfır değerli kova dolu kabul edilir. Satır önce oluşturulup kilitlenir; böylece aynı
+		// anahtara gelen eşzamanlı istekler, ilk istekte bile sırayla işlenir.
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.RateLimitBucket{Key: key, ExpiresAt: now}).Error; err != nil {
+			return err
+		}
+		var row models.RateLimitBucket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&row, "key = ?", key).Error; err != nil {
+			return err
+		}
+
+		bucket, taken := ratelimit.Bucket{Tokens: row.Tokens, RefilledAt: row.RefilledAt}.Take(rule, now)
+		result = taken
+		return tx.Model(&row).Updates(map[string]interface{}{
+			"tokens":      bucket.Tokens,
+			"refilled_at": bucket.RefilledAt,
+			"expires_at":  now.Add(taken.Reset),
+		}).Error
+	})
+	return result, err
+}
+
+func (r *rateLimitRepo) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	var row models.RateLimitFailure
+	err := r.db.WithContext(ctx).Where("key = ? AND locked_until > ?", key, time.Now()).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return row.LockedUntil, nil
+}
+
+func (r *rateLimitRepo) RecordFailure(ctx context.Context, key string, policy ratelimit.LockoutPolicy) (time.Time, error) {
+	var lockedUntil time.Time
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.RateLimitFailure{Key: key, ExpiresAt: now}).Error; err != nil {
+			return err
+		}
+		var row models.RateLimitFailure
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&row, "key = ?", key).Error; err != nil {
+			return err
+		}
+
+		failures := ratelimit.Failures{Count: row.Count, LastFailure: row.LastFailure, LockedUntil: row.LockedUntil}.
+			Record(policy, now)
+		lockedUntil = failures.LockedUntil
+		return tx.Model(&row).Updates(map[string]interface{}{
+			"count":        failures.Count,
+			"last_failure": failures.LastFailure,
+			"locked_until": failures.LockedUntil,
+			"expires_at":   failures.ExpiresAt(policy),
+		}).Error
+	})
+	return lockedUntil, err
+}
+
+func (r *rateLimitRepo) ResetFailures(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Where("key = ?", key).Delete(&models.RateLimitFailure{}).Error
+}
+
+func (r *rateLimitRepo) Cleanup(ctx context.Context) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&models.RateLimitBucket{}).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&models.RateLimitFailure{}).Error
+}
@@ -3,17 +3,55 @@ package repository
 import "gorm.io/gorm"
 
 type Repository struct {
-	User       UserRepository
-	Document   DocumentRepository
-	Permission PermissionRepository
-	Message    MessageRepository
+	User         UserRepository
+	Document     DocumentRepository
+	Permission   PermissionRepository
+	Message      MessageRepository
+	Retention    RetentionRepository
+	Attribution  AttributionRepository
+	ShareLink    ShareLinkRepository
+	Notification NotificationRepository
+	Team         TeamRepository
+	Organization OrganizationRepository
+	Comment      CommentRepository
+	Suggestion   SuggestionRepository
+	Transfer     OwnershipTransferRepository
+	EmailInvite  EmailInvitationRepository
+	AccessReq    AccessRequestRepository
+	Session      SessionRepository
+	Identity     UserIdentityRepository
+	UserToken    UserTokenRepository
+	MFA          MFARepository
+	AccessToken  PersonalAccessTokenRepository
+	Account      AccountRepository
+	RateLimit    RateLimitRepository
+	AuditLog     AuditLogRepository
 }
 
 func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{
-		User:       NewUserRepository(db),
-		Document:   NewDocumentRepository(db),
-		Permission: NewPermissionRepository(db),
-		Message:    NewMessageRepository(db),
+		User:         NewUserRepository(db),
+		Document:     NewDocumentRepository(db),
+		Permission:   NewPermissionRepository(db),
+		Message:      NewMessageRepository(db),
+		Retention:    NewRetentionRepository(db),
+		Attribution:  NewAttributionRepository(db),
+		ShareLink:    NewShareLinkRepository(db),
+		Notification: NewNotificationRepository(db),
+		Team:         NewTeamRepository(db),
+		Organization: NewOrganizationRepository(db),
+		Comment:      NewCommentRepository(db),
+		Suggestion:   NewSuggestionRepository(db),
+		Transfer:     NewOwnershipTransferRepository(db),
+		EmailInvite:  NewEmailInvitationRepository(db),
+		AccessReq:    NewAccessRequestRepository(db),
+		Session:      NewSessionRepository(db),
+		Identity:     NewUserIdentityRepository(db),
+		UserToken:    NewUserTokenRepository(db),
+		MFA:          NewMFARepository(db),
+		AccessToken:  NewPersonalAccessTokenRepository(db),
+		Account:      NewAccountRepository(db),
+		RateLimit:    NewRateLimitRepository(db),
+		AuditLog:     NewAuditLogRepository(db),
 	}
 }
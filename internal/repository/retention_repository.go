@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RetentionRepository interface {
+	GetByDocumentID(documentID uuid.UUID) (*models.VersionRetentionPolicy, error)
+	Save(policy *models.VersionRetentionPolicy) error
+	DeleteByDocumentID(documentID uuid.UUID) error
+}
+
+type retentionRepo struct {
+	db *gorm.DB
+}
+
+func NewRetentionRepository(db *gorm.DB) RetentionRepository {
+	return &retentionRepo{db: db}
+}
+
+func (r *retentionRepo) GetByDocumentID(documentID uuid.UUID) (*models.VersionRetentionPolicy, error) {
+	var policy models.VersionRetentionPolicy
+	if err := r.db.Where("document_id = ?", documentID).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *retentionRepo) Save(policy *models.VersionRetentionPolicy) error {
+	return r.db.Save(policy).Error
+}
+
+func (r *retentionRepo) DeleteByDocumentID(documentID uuid.UUID) error {
+	return r.db.Where("document_id = ?", documentID).
+		Delete(&models.VersionRetentionPolicy{}).Error
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Create(session *models.Session) error
+	GetByID(id any, session *models.Session) error
+	GetByRefreshTokenHash(hash string) (*models.Session, error)
+	GetByPreviousTokenHash(hash string) (*models.Session, error)
+	// GetActiveByUserID, kullanıcının aktif oturumlarını son kullanıma göre döndürür.
+	GetActiveByUserID(userID uuid.UUID) ([]models.Session, error)
+	// Rotate, oturumun yenileme token'ını newHash ile değiştirir. Token bu arada başka bir
+	// istekle değiştirilmişse false döner.
+	Rotate(sessionID uuid.UUID, currentHash, newHash string, expiresAt time.Time, ipAddress, userAgent string) (bool, error)
+	Touch(sessionID uuid.UUID, ipAddress, userAgent string) error
+	Revoke(userID, sessionID uuid.UUID) (int64, error)
+	// RevokeAll, kullanıcının tüm aktif oturumlarını iptal eder; except nil değilse o oturum korunur.
+	RevokeAll(userID uuid.UUID, except *uuid.UUID) (int64, error)
+	// DeleteStale, before'dan önce süresi dolmuş veya iptal edilmiş oturumları siler.
+	DeleteStale(before time.Time) (int64, error)
+}
+
+type sessionRepo struct {
+	*GenericRepository[models.Session]
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepo{
+		GenericRepository: NewGenericRepository[models.Session](db),
+		db:                db,
+	}
+}
+
+func (r *sessionRepo) GetByRefreshTokenHash(hash string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.Where("refresh_token_hash = ?", hash).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepo) GetByPreviousTokenHash(hash string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.Where("previous_token_hash = ?", hash).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepo) GetActiveByUserID(userID uuid.UUID) ([]models.Session, error) {
+	var sessions []models.Session
+	if err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at desc").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepo) Rotate(sessionID uuid.UUID, currentHash, newHash string, expiresAt time.Time, ipAddress, userAgent string) (bool, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", sessionID, currentHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  newHash,
+			"previous_token_hash": currentHash,
+			"expires_at":          expiresAt,
+			"last_used_at":        time.Now(),
+			"ip_address":          ipAddress,
+			"user_agent":          userAgent,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *sessionRepo) Touch(sessionID uuid.UUID, ipAddress, userAgent string) error {
+	return r.db.Model(&models.Session{}).
+		Where("id = ?", sessionID).
+		Updates(map[string]interface{}{
+			"last_used_at": time.Now(),
+			"ip_address":   ipAddress,
+			"user_agent":   userAgent,
+		}).Error
+}
+
+func (r *sessionRepo) Revoke(userID, sessionID uuid.UUID) (int64, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+func (r *sessionRepo) RevokeAll(userID uuid.UUID, except *uuid.UUID) (int64, error) {
+	query := r.db.Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if except != nil {
+		query = query.Where("id <> ?", *except)
+	}
+	result := query.Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+func (r *sessionRepo) DeleteStale(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ? OR revoked_at < ?", before, before).Delete(&models.Session{})
+	return result.RowsAffected, result.Error
+}
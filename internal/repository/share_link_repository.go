@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ShareLinkRepository interface {
+	Create(link *models.ShareLink) error
+	GetByID(id any, link *models.ShareLink) error
+	GetByToken(token string) (*models.ShareLink, error)
+	GetByDocumentID(documentID uuid.UUID) ([]models.ShareLink, error)
+	Revoke(documentID, linkID uuid.UUID) (int64, error)
+	// RecordUse, bağlantı hâlâ kullanılabilirse kullanım sayısını artırır; değilse false döner.
+	RecordUse(linkID uuid.UUID) (bool, error)
+}
+
+type shareLinkRepo struct {
+	*GenericRepository[models.ShareLink]
+	db *gorm.DB
+}
+
+func NewShareLinkRepository(db *gorm.DB) ShareLinkRepository {
+	return &shareLinkRepo{
+		GenericRepository: NewGenericRepository[models.ShareLink](db),
+		db:                db,
+	}
+}
+
+func (r *shareLinkRepo) GetByToken(token string) (*models.ShareLink, error) {
+	var link models.ShareLink
+	if err := r.db.Where("token = ?", token).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *shareLinkRepo) GetByDocumentID(documentID uuid.UUID) ([]models.ShareLink, error) {
+	var links []models.ShareLink
+	if err := r.db.Where("document_id = ?", documentID).
+		Order("created_at desc").
+		Find(&links).Error; err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (r *shareLinkRepo) Revoke(documentID, linkID uuid.UUID) (int64, error) {
+	result := r.db.Model(&models.ShareLink{}).
+		Where("id = ? AND document_id = ? AND revoked_at IS NULL", linkID, documentID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+func (r *shareLinkRepo) RecordUse(linkID uuid.UUID) (bool, error) {
+	result := r.db.Model(&models.ShareLink{}).
+		Where("id = ? AND revoked_at IS NULL", linkID).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Where("max_uses = 0 OR use_count < max_uses").
+		Update("use_count", gorm.Expr("use_count + 1"))
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SuggestionRepository interface {
+	Create(suggestion *models.Suggestion) error
+	GetByID(id any, suggestion *models.Suggestion) error
+	// GetByDocumentID, belgenin önerilerini oluşturulma sırasıyla döndürür. status boşsa tümü döner.
+	GetByDocumentID(documentID uuid.UUID, status models.SuggestionStatus) ([]models.Suggestion, error)
+	UpdateChange(suggestionID uuid.UUID, change []byte) error
+	// Resolve, bekleyen öneriyi kabul edildi veya reddedildi olarak işaretler. Öneri artık
+	// beklemede değilse false döner; böylece aynı öneri iki kez uygulanmaz.
+	Resolve(suggestionID uuid.UUID, status models.SuggestionStatus, resolvedBy uuid.UUID) (bool, error)
+	// Reopen, Resolve'dan sonra uygulanamayan bir öneriyi yeniden beklemeye alır.
+	Reopen(suggestionID uuid.UUID) error
+}
+
+type suggestionRepo struct {
+	*GenericRepository[models.Suggestion]
+	db *gorm.DB
+}
+
+func NewSuggestionRepository(db *gorm.DB) SuggestionRepository {
+	return &suggestionRepo{
+		GenericRepository: NewGenericRepository[models.Suggestion](db),
+		db:                db,
+	}
+}
+
+func (r *suggestionRepo) GetByDocumentID(documentID uuid.UUID, status models.SuggestionStatus) ([]models.Suggestion, error) {
+	query := r.db.Where("document_id = ?", documentID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var suggestions []models.Suggestion
+	if err := query.Order("created_at").Find(&suggestions).Error; err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+func (r *suggestionRepo) UpdateChange(suggestionID uuid.UUID, change []byte) error {
+	return r.db.Model(&models.Suggestion{}).
+		Where("id = ? AND status = ?", suggestionID, models.SuggestionStatusPending).
+		UpdateColumn("change", change).Error
+}
+
+func (r *suggestionRepo) Resolve(suggestionID uuid.UUID, status models.SuggestionStatus, resolvedBy uuid.UUID) (bool, error) {
+	result := r.db.Model(&models.Suggestion{}).
+		Where("id = ? AND status = ?", suggestionID, models.SuggestionStatusPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"resolved_by": resolvedBy,
+			"resolved_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *suggestionRepo) Reopen(suggestionID uuid.UUID) error {
+	return r.db.Model(&models.Suggestion{}).
+		Where("id = ?", suggestionID).
+		Updates(map[string]interface{}{
+			"status":      models.SuggestionStatusPending,
+			"resolved_by": nil,
+			"resolved_at": nil,
+		}).Error
+}
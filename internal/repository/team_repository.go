@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TeamRepository interface {
+	// Create, takımı oluşturur ve sahibini admin üye olarak ekler.
+	Create(team *models.Team) error
+	GetByID(id any, team *models.Team) error
+	Update(team *models.Team) error
+	// Delete, takımı üyeleri ve belge izinleriyle birlikte siler.
+	Delete(teamID uuid.UUID) error
+	GetByUserID(userID uuid.UUID) ([]models.Team, error)
+
+	GetMembers(teamID uuid.UUID) ([]models.TeamMember, error)
+	GetMember(teamID, userID uuid.UUID) (*models.TeamMember, error)
+	AddMember(member *models.TeamMember) error
+	UpdateMemberRole(teamID, userID uuid.UUID, role models.TeamRole) error
+	RemoveMember(teamID, userID uuid.UUID) error
+
+	// SaveDocumentPermission, takımın belgedeki iznini oluşturur veya erişim tipini günceller.
+	SaveDocumentPermission(permission *models.TeamPermission) error
+	GetDocumentPermissions(documentID uuid.UUID) ([]models.TeamPermission, error)
+	DeleteDocumentPermission(documentID, teamID uuid.UUID) (int64, error)
+	// GetRestrictedDocuments, takımla paylaşılmış ve organizasyonu paylaşımı kısıtlayan belgeleri döndürür.
+	GetRestrictedDocuments(teamID uuid.UUID) ([]models.Document, error)
+}
+
+type teamRepo struct {
+	*GenericRepository[models.Team]
+	db *gorm.DB
+}
+
+func NewTeamRepository(db *gorm.DB) TeamRepository {
+	return &teamRepo{
+		GenericRepository: NewGenericRepository[models.Team](db),
+		db:                db,
+	}
+}
+
+func (r *teamRepo) Create(team *models.Team) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(team).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.TeamMember{
+			TeamID:  team.ID,
+			UserID:  team.OwnerID,
+			Role:    models.TeamRoleAdmin,
+			AddedBy: team.OwnerID,
+		}).Error
+	})
+}
+
+func (r *teamRepo) Update(team *models.Team) error {
+	return r.db.Save(team).Error
+}
+
+func (r *teamRepo) Delete(teamID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("team_id = ?", teamID).Delete(&models.TeamPermission{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id = ?", teamID).Delete(&models.TeamMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Team{}, "id = ?", teamID).Error
+	})
+}
+
+func (r *teamRepo) GetByUserID(userID uuid.UUID) ([]models.Team, error) {
+	var teams []models.Team
+	if err := r.db.Joins("JOIN team_members ON team_members.team_id = teams.id").
+		Where("team_members.user_id = ?", userID).
+		Order("teams.name").
+		Find(&teams).Error; err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+func (r *teamRepo) GetMembers(teamID uuid.UUID) ([]models.TeamMember, error) {
+	var members []models.TeamMember
+	if err := r.db.Where("team_id = ?", teamID).
+		Order("created_at").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *teamRepo) GetMember(teamID, userID uuid.UUID) (*models.TeamMember, error) {
+	var member models.TeamMember
+	if err := r.db.Where("team_id = ? AND user_id = ?", teamID, userID).
+		First(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *teamRepo) AddMember(member *models.TeamMember) error {
+	return r.db.Create(member).Error
+}
+
+func (r *teamRepo) UpdateMemberRole(teamID, userID uuid.UUID, role models.TeamRole) error {
+	return r.db.Model(&models.TeamMember{}).
+		Where("team_id = ? AND user_id = ?", teamID, userID).
+		Update("role", role).Error
+}
+
+func (r *teamRepo) RemoveMember(teamID, userID uuid.UUID) error {
+	return r.db.Where("team_id = ? AND user_id = ?", teamID, userID).
+		Delete(&models.TeamMember{}).Error
+}
+
+func (r *teamRepo) SaveDocumentPermission(permission *models.TeamPermission) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "document_id"}, {Name: "team_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"access_type", "shared_by", "updated_at"}),
+	}).Create(permission).Error
+}
+
+func (r *teamRepo) GetDocumentPermissions(documentID uuid.UUID) ([]models.TeamPermission, error) {
+	var permissions []models.TeamPermission
+	if err := r.db.Where("document_id = ?", documentID).
+		Order("created_at").
+		Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *teamRepo) DeleteDocumentPermission(documentID, teamID uuid.UUID) (int64, error) {
+	result := r.db.Where("document_id = ? AND team_id = ?", documentID, teamID).
+		Delete(&models.TeamPermission{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *teamRepo) GetRestrictedDocuments(teamID uuid.UUID) ([]models.Document, error) {
+	var docs []models.Document
+	if err := r.db.Model(&models.Document{}).
+		Joins("JOIN team_permissions ON team_permissions.document_id = documents.id").
+		Joins("JOIN organizations ON organizations.id = documents.organization_id").
+		Where("team_permissions.team_id = ? AND organizations.restrict_sharing = ?", teamID, true).
+		Find(&docs).Error; err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
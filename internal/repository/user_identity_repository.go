@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UserIdentityRepository interface {
+	Create(identity *models.UserIdentity) error
+	GetByProviderSubject(provider, subject string) (*models.UserIdentity, error)
+	GetByUserID(userID uuid.UUID) ([]models.UserIdentity, error)
+	// CreateWithUser, harici kimlikle ilk kez giriş yapan kullanıcıyı ve kimliğini tek işlemde oluşturur.
+	CreateWithUser(user *models.User, identity *models.UserIdentity) error
+}
+
+type userIdentityRepo struct {
+	*GenericRepository[models.UserIdentity]
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepo{
+		GenericRepository: NewGenericRepository[models.UserIdentity](db),
+		db:                db,
+	}
+}
+
+func (r *userIdentityRepo) GetByProviderSubject(provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	if err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *userIdentityRepo) GetByUserID(userID uuid.UUID) ([]models.UserIdentity, error) {
+	var identities []models.UserIdentity
+	if err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+func (r *userIdentityRepo) CreateWithUser(user *models.User, identity *models.UserIdentity) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		identity.UserID = user.ID
+		return tx.Create(identity).Error
+	})
+}
@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"strings"
+
 	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -10,6 +13,14 @@ type UserRepository interface {
 	Delete(user *models.User) error
 	GetByID(id any, user *models.User) error
 	GetByEmail(email string) (*models.User, error)
+	GetByUsername(username string) (*models.User, error)
+	UpdatePassword(userID uuid.UUID, passwordHash string) error
+	MarkEmailVerified(userID uuid.UUID) error
+	// UpdateFields, kullanıcının yalnızca verilen sütunlarını günceller.
+	UpdateFields(userID uuid.UUID, fields map[string]interface{}) error
+	// Search, kullanıcı adı, e-posta veya ad soyadında query geçen kullanıcıları kayıt tarihine
+	// göre en yeniden eskiye döndürür. query boşsa tüm kullanıcılar listelenir.
+	Search(query string, limit, offset int) ([]models.User, int64, error)
 }
 
 type userRepo struct {
@@ -31,3 +42,48 @@ func (r *userRepo) GetByEmail(email string) (*models.User, error) {
 	}
 	return &user, nil
 }
+
+func (r *userRepo) GetByUsername(username string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepo) UpdatePassword(userID uuid.UUID, passwordHash string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("password_hash", passwordHash).Error
+}
+
+func (r *userRepo) MarkEmailVerified(userID uuid.UUID) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("email_verified", true).Error
+}
+
+func (r *userRepo) UpdateFields(userID uuid.UUID, fields map[string]interface{}) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Updates(fields).Error
+}
+
+func (r *userRepo) Search(query string, limit, offset int) ([]models.User, int64, error) {
+	db := r.db.Model(&models.User{})
+	if query = strings.TrimSpace(query); query != "" {
+		pattern := "%" + escapeLike(query) + "%"
+		db = db.Where("username ILIKE ? OR email ILIKE ? OR full_name ILIKE ?", pattern, pattern, pattern)
+	}
+	db = db.Session(&gorm.Session{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	if err := db.Order("created_at desc").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// escapeLike, LIKE kalıbında özel anlamı olan karakterleri kaçışlar.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UserTokenRepository interface {
+	Create(token *models.UserToken) error
+	// Consume, amacı ve özeti eşleşen, kullanılmamış ve süresi dolmamış token'ı kullanıldı olarak
+	// işaretler ve döndürür. Böyle bir token yoksa gorm.ErrRecordNotFound döner.
+	Consume(purpose models.UserTokenPurpose, tokenHash string) (*models.UserToken, error)
+	// InvalidateForUser, kullanıcının verilen amaçla üretilmiş kullanılmamış token'larını geçersiz kılar.
+	InvalidateForUser(userID uuid.UUID, purpose models.UserTokenPurpose) error
+	DeleteStale(before time.Time) (int64, error)
+}
+
+type userTokenRepo struct {
+	*GenericRepository[models.UserToken]
+	db *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepo{
+		GenericRepository: NewGenericRepository[models.UserToken](db),
+		db:                db,
+	}
+}
+
+func (r *userTokenRepo) Consume(purpose models.UserTokenPurpose, tokenHash string) (*models.UserToken, error) {
+	var token models.UserToken
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, time.Now()).
+			First(&token).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.UserToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *userTokenRepo) InvalidateForUser(userID uuid.UUID, purpose models.UserTokenPurpose) error {
+	return r.db.Model(&models.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
+
+func (r *userTokenRepo) DeleteStale(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ? OR used_at < ?", before, before).Delete(&models.UserToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/dione-docs-backend/internal/delta"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// versionSnapshotInterval, iki tam içerik snapshot'ı arasında en fazla kaç versiyonun
+// Delta farkı olarak saklanacağını belirler. Her fark doğrudan snapshot'a göre tutulduğundan
+// bir versiyonu okumak en fazla iki satır gerektirir ve aradaki versiyonlar silinebilir.
+const versionSnapshotInterval = 20
+
+// versionBatchSize, versiyonlar sırayla işlenirken tek seferde belleğe alınan satır sayısıdır.
+const versionBatchSize = 200
+
+// encodeVersion, versiyonu mümkünse son snapshot'a göre Delta farkı olarak kodlar.
+// İçerik bir Quill Delta belgesi değilse, snapshot aralığı dolmuşsa veya fark tam
+// içerikten küçük değilse versiyon yeni bir snapshot olarak saklanır.
+func encodeVersion(tx *gorm.DB, version *models.DocumentVersion) error {
+	version.Encoding = models.VersionEncodingFull
+	version.BaseVersionID = nil
+
+	var base models.DocumentVersion
+	err := tx.Where("document_id = ? AND encoding = ?", version.DocumentID, models.VersionEncodingFull).
+		Order("version desc").
+		First(&base).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var dependents int64
+	if err := tx.Model(&models.DocumentVersion{}).
+		Where("base_version_id = ?", base.ID).
+		Count(&dependents).Error; err != nil {
+		return err
+	}
+	if dependents+1 >= versionSnapshotInterval {
+		return nil
+	}
+
+	if encoded, ok := encodeAgainst(base.Content, version.Content); ok {
+		version.Encoding = models.VersionEncodingDelta
+		version.BaseVersionID = &base.ID
+		version.Content = encoded
+	}
+	return nil
+}
+
+// encodeAgainst, content'i base'e göre Delta farkı olarak döndürür. Fark daha küçük
+// değilse veya farktan içerik birebir geri üretilemiyorsa false döner.
+func encodeAgainst(baseContent, content []byte) ([]byte, bool) {
+	base, err := delta.Parse(baseContent)
+	if err != nil || !base.IsDocument() {
+		return nil, false
+	}
+	target, err := delta.Parse(content)
+	if err != nil || !target.IsDocument() {
+		return nil, false
+	}
+
+	diff, err := delta.Diff(base, target)
+	if err != nil {
+		return nil, false
+	}
+	encoded, err := diff.Marshal()
+	if err != nil || len(encoded) >= len(content) {
+		return nil, false
+	}
+
+	rebuilt, err := delta.Compose(base, diff).Marshal()
+	if err != nil || !jsonEqual(rebuilt, content) {
+		return nil, false
+	}
+	return encoded, true
+}
+
+// decodeVersion, Delta olarak saklanan bir versiyonun tam içeriğini Content alanına yazar.
+func decodeVersion(db *gorm.DB, version *models.DocumentVersion) error {
+	return newVersionDecoder(db).decode(version)
+}
+
+// versionDecoder, art arda okunan versiyonlar için snapshot içeriklerini önbelleğe alır.
+type versionDecoder struct {
+	db    *gorm.DB
+	bases map[uuid.UUID][]byte
+}
+
+func newVersionDecoder(db *gorm.DB) *versionDecoder {
+	return &versionDecoder{db: db, bases: make(map[uuid.UUID][]byte)}
+}
+
+func (d *versionDecoder) decode(version *models.DocumentVersion) error {
+	if version.Encoding != models.VersionEncodingDelta {
+		if version.Encoding == models.VersionEncodingFull {
+			d.remember(version.ID, version.Content)
+		}
+		return nil
+	}
+	if version.BaseVersionID == nil {
+		return fmt.Errorf("version %s has no base snapshot", version.ID)
+	}
+
+	baseContent, ok := d.bases[*version.BaseVersionID]
+	if !ok {
+		var base models.DocumentVersion
+		if err := d.db.Select("id", "content").
+			Where("id = ?", *version.BaseVersionID).
+			First(&base).Error; err != nil {
+			return fmt.Errorf("failed to load base snapshot of version %s: %w", version.ID, err)
+		}
+		baseContent = base.Content
+		d.remember(base.ID, baseContent)
+	}
+
+	content, err := applyDelta(baseContent, version.Content)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild version %s: %w", version.ID, err)
+	}
+	version.Content = content
+	return nil
+}
+
+// remember, yalnızca en son snapshot'ı tutar; versiyonlar artan sırada okunduğunda
+// daha eski snapshot'lara tekrar ihtiyaç duyulmaz.
+func (d *versionDecoder) remember(id uuid.UUID, content []byte) {
+	clear(d.bases)
+	d.bases[id] = content
+}
+
+func applyDelta(baseContent, diffContent []byte) ([]byte, error) {
+	base, err := delta.Parse(baseContent)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := delta.Parse(diffContent)
+	if err != nil {
+		return nil, err
+	}
+	return delta.Compose(base, diff).Marshal()
+}
+
+func jsonEqual(a, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// ConvertLegacyVersions, delta kodlamasından önce kaydedilmiş versiyonları snapshot ve
+// Delta farkı düzenine dönüştürür. Yalnızca "legacy" kayıtlara dokunduğu için her
+// açılışta güvenle çalıştırılabilir; dönüştürülen kayıt sayısını döndürür.
+func (r *documentRepo) ConvertLegacyVersions() (int, error) {
+	var documentIDs []uuid.UUID
+	if err := r.db.Model(&models.DocumentVersion{}).
+		Where("encoding = ?", models.VersionEncodingLegacy).
+		Distinct("document_id").
+		Pluck("document_id", &documentIDs).Error; err != nil {
+		return 0, err
+	}
+
+	converted := 0
+	for _, documentID := range documentIDs {
+		n, err := r.convertLegacyDocumentVersions(documentID)
+		converted += n
+		if err != nil {
+			return converted, fmt.Errorf("document %s: %w", documentID, err)
+		}
+	}
+	return converted, nil
+}
+
+func (r *documentRepo) convertLegacyDocumentVersions(documentID uuid.UUID) (int, error) {
+	converted := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var base *models.DocumentVersion
+		dependents := 0
+		lastVersion := -1
+
+		for {
+			var batch []models.DocumentVersion
+			if err := tx.Where("document_id = ? AND encoding = ? AND version > ?", documentID, models.VersionEncodingLegacy, lastVersion).
+				Order("version asc").
+				Limit(versionBatchSize).
+				Find(&batch).Error; err != nil {
+				return err
+			}
+			if len(batch) == 0 {
+				return nil
+			}
+
+			for i := range batch {
+				version := batch[i]
+				lastVersion = version.Version
+
+				if base != nil && dependents+1 < versionSnapshotInterval {
+					if encoded, ok := encodeAgainst(base.Content, version.Content); ok {
+						if err := tx.Model(&models.DocumentVersion{}).Where("id = ?", version.ID).Updates(map[string]interface{}{
+							"encoding":        models.VersionEncodingDelta,
+							"base_version_id": base.ID,
+							"content":         encoded,
+						}).Error; err != nil {
+							return err
+						}
+						dependents++
+						converted++
+						continue
+					}
+				}
+
+				if err := tx.Model(&models.DocumentVersion{}).Where("id = ?", version.ID).
+					Update("encoding", models.VersionEncodingFull).Error; err != nil {
+					return err
+				}
+				base = &version
+				dependents = 0
+				converted++
+			}
+		}
+	})
+	return converted, err
+}
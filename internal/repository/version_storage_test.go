@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/google/uuid"
+)
+
+func quillDocument(text string) []byte {
+	return []byte(fmt.Sprintf(`{"ops":[{"insert":%q}]}`, text))
+}
+
+func TestEncodeAgainstRoundTrip(t *testing.T) {
+	longText := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 40)
+	base := quillDocument(longText + "\n")
+
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{"insert", quillDocument("Intro. " + longText + "\n")},
+		{"delete", quillDocument(longText[20:] + "\n")},
+		{"replace", quillDocument(strings.Replace(longText, "lazy", "sleepy", 3) + "\n")},
+		{"format", []byte(`{"ops":[{"insert":"The quick","attributes":{"bold":true}},{"insert":` +
+			fmt.Sprintf("%q", longText[9:]+"\n") + `}]}`)},
+		{"embed", []byte(`{"ops":[{"insert":{"image":"https://example.com/a.png"}},{"insert":` +
+			fmt.Sprintf("%q", longText+"\n") + `}]}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, ok := encodeAgainst(base, tt.content)
+			if !ok {
+				t.Fatal("encodeAgainst did not produce a delta")
+			}
+			if len(encoded) >= len(tt.content) {
+				t.Fatalf("encoded size %d is not smaller than content size %d", len(encoded), len(tt.content))
+			}
+
+			rebuilt, err := applyDelta(base, encoded)
+			if err != nil {
+				t.Fatalf("applyDelta: %v", err)
+			}
+			if !jsonEqual(rebuilt, tt.content) {
+				t.Fatalf("rebuilt content %s, want %s", rebuilt, tt.content)
+			}
+		})
+	}
+}
+
+func TestEncodeAgainstFallsBackToSnapshot(t *testing.T) {
+	tests := []struct {
+		name          string
+		base, content []byte
+	}{
+		{"base is not a document", []byte(`{"ops":[{"retain":1}]}`), quillDocument("a\n")},
+		{"content is not a document", quillDocument("a\n"), []byte(`{"ops":[{"delete":1}]}`)},
+		{"content is not a delta", quillDocument("a\n"), []byte(`"plain text"`)},
+		{"diff is not smaller", quillDocument("abc\n"), quillDocument("xyz\n")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := encodeAgainst(tt.base, tt.content); ok {
+				t.Fatal("encodeAgainst = ok, want snapshot fallback")
+			}
+		})
+	}
+}
+
+func TestVersionDecoderRoundTrip(t *testing.T) {
+	text := strings.Repeat("version history keeps every save. ", 30)
+	contents := [][]byte{
+		quillDocument(text + "\n"),
+		quillDocument(text + "One more line.\n"),
+		quillDocument("Title\n" + text + "One more line.\n"),
+		quillDocument("Title\n" + text[40:] + "One more line.\n"),
+	}
+
+	snapshot := models.DocumentVersion{
+		ID:       uuid.New(),
+		Version:  1,
+		Content:  contents[0],
+		Encoding: models.VersionEncodingFull,
+	}
+	versions := []models.DocumentVersion{snapshot}
+	for i, content := range contents[1:] {
+		encoded, ok := encodeAgainst(snapshot.Content, content)
+		if !ok {
+			t.Fatalf("version %d was not delta encoded", i+2)
+		}
+		versions = append(versions, models.DocumentVersion{
+			ID:            uuid.New(),
+			Version:       i + 2,
+			Content:       encoded,
+			Encoding:      models.VersionEncodingDelta,
+			BaseVersionID: &snapshot.ID,
+		})
+	}
+
+	// Snapshot önce okunduğundan sonraki farklar veritabanına gitmeden önbellekten çözülür.
+	decoder := newVersionDecoder(nil)
+	for i := range versions {
+		if err := decoder.decode(&versions[i]); err != nil {
+			t.Fatalf("decode version %d: %v", versions[i].Version, err)
+		}
+		if !jsonEqual(versions[i].Content, contents[i]) {
+			t.Fatalf("version %d decoded to %s, want %s", versions[i].Version, versions[i].Content, contents[i])
+		}
+	}
+}
+
+func TestVersionDecoderRequiresBase(t *testing.T) {
+	version := models.DocumentVersion{
+		ID:       uuid.New(),
+		Content:  []byte(`{"ops":[{"retain":1}]}`),
+		Encoding: models.VersionEncodingDelta,
+	}
+	if err := newVersionDecoder(nil).decode(&version); err == nil {
+		t.Fatal("decode succeeded without a base snapshot")
+	}
+}
+
+func TestVersionDecoderKeepsLegacyContent(t *testing.T) {
+	content := quillDocument("legacy\n")
+	version := models.DocumentVersion{ID: uuid.New(), Content: content, Encoding: models.VersionEncodingLegacy}
+	if err := newVersionDecoder(nil).decode(&version); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if string(version.Content) != string(content) {
+		t.Fatalf("legacy content changed to %s", version.Content)
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PersonalAccessTokenPrefix, kişisel erişim token'larını JWT'lerden ayıran önektir.
+const PersonalAccessTokenPrefix = "ddpat_"
+
+// ErrInvalidAccessToken, kişisel erişim token'ı bulunamadığında, iptal edildiğinde veya süresi dolduğunda döner.
+var ErrInvalidAccessToken = errors.New("invalid or expired personal access token")
+
+// AccessTokenService, kişisel erişim token'larını üretir ve doğrular.
+type AccessTokenService struct {
+	tokens repository.PersonalAccessTokenRepository
+}
+
+func NewAccessTokenService(tokens repository.PersonalAccessTokenRepository) *AccessTokenService {
+	return &AccessTokenService{tokens: tokens}
+}
+
+// IsPersonalAccessToken, bearer değerinin kişisel erişim token'ı biçiminde olup olmadığını döndürür.
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// Create, kullanıcı için yeni bir token oluşturur ve kaydı ile birlikte yalnızca bir kez
+// gösterilecek token değerini döndürür.
+func (s *AccessTokenService) Create(userID uuid.UUID, name string, scopes []models.TokenScope, expiresAt *time.Time) (*models.PersonalAccessToken, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, "", err
+	}
+	secret := PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	token := &models.PersonalAccessToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hashAccessToken(secret),
+		Prefix:    secret[:len(PersonalAccessTokenPrefix)+6],
+		Scopes:    models.JoinTokenScopes(scopes),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.tokens.Create(token); err != nil {
+		return nil, "", err
+	}
+	return token, secret, nil
+}
+
+// Authenticate, token'ı doğrular ve kaydını döndürür. Son kullanım zamanı en fazla dakikada bir güncellenir.
+func (s *AccessTokenService) Authenticate(secret string) (*models.PersonalAccessToken, error) {
+	token, err := s.tokens.GetByHash(hashAccessToken(secret))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidAccessToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !token.IsActive(now) {
+		return nil, ErrInvalidAccessToken
+	}
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > sessionTouchInterval {
+		if err := s.tokens.Touch(token.ID); err != nil {
+			log.Printf("Could not update last use of personal access token %s: %v", token.ID, err)
+		}
+	}
+	return token, nil
+}
+
+func hashAccessToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/dione-docs-backend/internal/config"
+	"github.com/dione-docs-backend/internal/mail"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 48 * time.Hour
+)
+
+var (
+	// ErrInvalidAccountToken, hesap token'ı bulunamadığında, kullanıldığında veya süresi dolduğunda döner.
+	ErrInvalidAccountToken = errors.New("invalid or expired token")
+	ErrInvalidPassword     = errors.New("current password is incorrect")
+	ErrEmailInUse          = errors.New("email already in use")
+)
+
+// AccountService, e-postayla gönderilen tek kullanımlık token'larla şifre sıfırlama ve
+// e-posta doğrulama akışlarını yürütür.
+type AccountService struct {
+	repo   *repository.Repository
+	mailer mail.Sender
+	cfg    *config.Config
+}
+
+func NewAccountService(repo *repository.Repository, mailer mail.Sender, cfg *config.Config) *AccountService {
+	return &AccountService{
+		repo:   repo,
+		mailer: mailer,
+		cfg:    cfg,
+	}
+}
+
+// RequestPasswordReset, adrese kayıtlı bir kullanıcı varsa şifre sıfırlama bağlantısı gönderir.
+// Adresin kayıtlı olup olmadığı çağırana bildirilmez.
+func (s *AccountService) RequestPasswordReset(email string) error {
+	user, err := s.repo.User.GetByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Yalnızca son gönderilen bağlantı geçerli olur.
+	if err := s.repo.UserToken.InvalidateForUser(user.ID, models.UserTokenPasswordReset); err != nil {
+		return err
+	}
+	token, err := s.issue(user.ID, models.UserTokenPasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Dione Docs şifre sıfırlama",
+		Body: fmt.Sprintf("Merhaba %s,\n\nŞifrenizi sıfırlamak için aşağıdaki bağlantıyı kullanın. Bağlantı %d dakika geçerlidir ve yalnızca bir kez kullanılabilir:\n%s/reset-password?token=%s\n\n"+
+			"Bu isteği siz yapmadıysanız bu e-postayı yok sayabilirsiniz.\n",
+			user.DisplayName(), int(passwordResetTTL.Minutes()), s.cfg.AppURL, url.QueryEscape(token)),
+	})
+}
+
+// ResetPassword, şifre sıfırlama token'ını kullanarak kullanıcının şifresini değiştirir. Token
+// e-postayla geldiği için adres de doğrulanmış sayılır ve kullanıcının tüm oturumları kapatılır.
+func (s *AccountService) ResetPassword(token, newPassword string) error {
+	record, err := s.repo.UserToken.Consume(models.UserTokenPasswordReset, s.hash(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidAccountToken
+	}
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.User.UpdatePassword(record.UserID, string(hashedPassword)); err != nil {
+		return err
+	}
+	if err := s.repo.User.MarkEmailVerified(record.UserID); err != nil {
+		return err
+	}
+	if _, err := s.repo.Session.RevokeAll(record.UserID, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SendVerification, kullanıcının e-posta adresine doğrulama bağlantısı gönderir.
+func (s *AccountService) SendVerification(user *models.User) error {
+	if err := s.repo.UserToken.InvalidateForUser(user.ID, models.UserTokenEmailVerification); err != nil {
+		return err
+	}
+	token, err := s.issue(user.ID, models.UserTokenEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Dione Docs e-posta adresinizi doğrulayın",
+		Body: fmt.Sprintf("Merhaba %s,\n\nE-posta adresinizi doğrulamak için aşağıdaki bağlantıyı kullanın. Bağlantı %d saat geçerlidir:\n%s/verify-email?token=%s\n",
+			user.DisplayName(), int(emailVerificationTTL.Hours()), s.cfg.AppURL, url.QueryEscape(token)),
+	})
+}
+
+// VerifyEmail, doğrulama token'ının ait olduğu kullanıcının e-posta adresini doğrulanmış olarak işaretler.
+func (s *AccountService) VerifyEmail(token string) error {
+	record, err := s.repo.UserToken.Consume(models.UserTokenEmailVerification, s.hash(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidAccountToken
+	}
+	if err != nil {
+		return err
+	}
+	return s.repo.User.MarkEmailVerified(record.UserID)
+}
+
+// ChangePassword, mevcut şifreyi doğrulayarak kullanıcının şifresini değiştirir ve keepSession dışındaki
+// tüm oturumlarını kapatır. Şifresi olmayan (yalnızca harici kimlikle giriş yapan) kullanıcılar
+// mevcut şifre vermeden şifre belirleyebilir.
+func (s *AccountService) ChangePassword(user *models.User, currentPassword, newPassword string, keepSession *uuid.UUID) error {
+	if user.PasswordHash != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+			return ErrInvalidPassword
+		}
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.User.UpdatePassword(user.ID, string(hashedPassword)); err != nil {
+		return err
+	}
+	_, err = s.repo.Session.RevokeAll(user.ID, keepSession)
+	return err
+}
+
+// RequestEmailChange, yeni adresi doğrulama bekleyen adres olarak kaydeder ve yeni adrese onay
+// bağlantısı gönderir. Adres onaylanana kadar kullanıcının e-postası değişmez.
+func (s *AccountService) RequestEmailChange(user *models.User, newEmail string) error {
+	if _, err := s.repo.User.GetByEmail(newEmail); err == nil {
+		return ErrEmailInUse
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if err := s.repo.User.UpdateFields(user.ID, map[string]interface{}{"pending_email": newEmail}); err != nil {
+		return err
+	}
+	if err := s.repo.UserToken.InvalidateForUser(user.ID, models.UserTokenEmailChange); err != nil {
+		return err
+	}
+	token, err := s.issue(user.ID, models.UserTokenEmailChange, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(mail.Message{
+		To:      newEmail,
+		Subject: "Dione Docs yeni e-posta adresinizi onaylayın",
+		Body: fmt.Sprintf("Merhaba %s,\n\nHesabınızın e-posta adresini bu adresle değiştirmek için aşağıdaki bağlantıyı kullanın. Bağlantı %d saat geçerlidir:\n%s/confirm-email-change?token=%s\n",
+			user.DisplayName(), int(emailVerificationTTL.Hours()), s.cfg.AppURL, url.QueryEscape(token)),
+	}); err != nil {
+		return err
+	}
+
+	// Eski adres de bilgilendirilir; değişikliği kullanıcı yapmadıysa fark edebilir.
+	if err := s.mailer.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Dione Docs e-posta değişikliği isteği",
+		Body: fmt.Sprintf("Merhaba %s,\n\nHesabınızın e-posta adresinin %s olarak değiştirilmesi istendi. Değişiklik yeni adres onaylandığında geçerli olur.\n\n"+
+			"Bu isteği siz yapmadıysanız şifrenizi değiştirin.\n", user.DisplayName(), newEmail),
+	}); err != nil {
+		log.Printf("Could not send email change notice to %s: %v", user.Email, err)
+	}
+	return nil
+}
+
+// ConfirmEmailChange, onay token'ıyla kullanıcının e-posta adresini bekleyen adresle değiştirir.
+func (s *AccountService) ConfirmEmailChange(token string) error {
+	record, err := s.repo.UserToken.Consume(models.UserTokenEmailChange, s.hash(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidAccountToken
+	}
+	if err != nil {
+		return err
+	}
+
+	var user models.User
+	if err := s.repo.User.GetByID(record.UserID, &user); err != nil {
+		return err
+	}
+	if user.PendingEmail == "" {
+		return ErrInvalidAccountToken
+	}
+	if _, err := s.repo.User.GetByEmail(user.PendingEmail); err == nil {
+		return ErrEmailInUse
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return s.repo.User.UpdateFields(user.ID, map[string]interface{}{
+		"email":          user.PendingEmail,
+		"pending_email":  "",
+		"email_verified": true,
+	})
+}
+
+// RunCleanup, bir günden uzun süre önce kullanılmış veya süresi dolmuş token'ları siler.
+func (s *AccountService) RunCleanup(ctx context.Context) error {
+	deleted, err := s.repo.UserToken.DeleteStale(time.Now().Add(-24 * time.Hour))
+	if err != nil {
+		return fmt.Errorf("failed to delete stale account tokens: %w", err)
+	}
+	if deleted > 0 {
+		log.Printf("Account token cleanup: deleted %d stale tokens", deleted)
+	}
+	return nil
+}
+
+// issue, rastgele bir token üretir ve imzalı özetini ttl süresiyle kaydeder.
+func (s *AccountService) issue(userID uuid.UUID, purpose models.UserTokenPurpose, ttl time.Duration) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	if err := s.repo.UserToken.Create(&models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: s.hash(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// hash, token'ı sunucu anahtarıyla imzalar; veritabanı sızsa bile geçerli token üretilemez.
+func (s *AccountService) hash(token string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.JWTSecret))
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
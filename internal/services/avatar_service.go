@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dione-docs-backend/internal/avatar"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/storage"
+	"github.com/google/uuid"
+)
+
+// avatarKeyPrefix, yüklenen avatarların depodaki klasörüdür.
+const avatarKeyPrefix = "avatars/"
+
+// AvatarService, profil resimlerini standart boyutlara küçültüp blob deposunda saklar.
+type AvatarService struct {
+	users   repository.UserRepository
+	storage storage.Storage
+}
+
+func NewAvatarService(users repository.UserRepository, storage storage.Storage) *AvatarService {
+	return &AvatarService{
+		users:   users,
+		storage: storage,
+	}
+}
+
+// Upload, resmi işler, her boyutu yeni bir sürüm klasörüne yazar ve kullanıcının avatarını
+// bu sürüme çevirir. Eski sürüm silinir; sürüm değiştiği için önbellekteki eski URL'ler geçersiz olur.
+func (s *AvatarService) Upload(ctx context.Context, user *models.User, r io.Reader) error {
+	images, err := avatar.Process(r)
+	if err != nil {
+		return err
+	}
+
+	prefix := fmt.Sprintf("%s%s/%d", avatarKeyPrefix, user.ID, time.Now().UnixNano())
+	for size, data := range images {
+		if err := s.storage.Put(ctx, avatarKey(prefix, size), bytes.NewReader(data)); err != nil {
+			s.remove(ctx, prefix)
+			return err
+		}
+	}
+
+	if err := s.users.UpdateFields(user.ID, map[string]interface{}{"profile_image": prefix}); err != nil {
+		s.remove(ctx, prefix)
+		return err
+	}
+	if IsStoredAvatar(user.ProfileImage) {
+		s.remove(ctx, user.ProfileImage)
+	}
+	user.ProfileImage = prefix
+	return nil
+}
+
+// Delete, kullanıcının avatarını kaldırır.
+func (s *AvatarService) Delete(ctx context.Context, user *models.User) error {
+	if err := s.users.UpdateFields(user.ID, map[string]interface{}{"profile_image": ""}); err != nil {
+		return err
+	}
+	if IsStoredAvatar(user.ProfileImage) {
+		s.remove(ctx, user.ProfileImage)
+	}
+	user.ProfileImage = ""
+	return nil
+}
+
+// Open, kullanıcının yüklenmiş avatarının istenen boyuttaki PNG'sini açar.
+func (s *AvatarService) Open(ctx context.Context, userID uuid.UUID, size int) (io.ReadCloser, error) {
+	var user models.User
+	if err := s.users.GetByID(userID, &user); err != nil {
+		return nil, storage.ErrNotFound
+	}
+	if !IsStoredAvatar(user.ProfileImage) || !avatar.ValidSize(size) {
+		return nil, storage.ErrNotFound
+	}
+	return s.storage.Get(ctx, avatarKey(user.ProfileImage, size))
+}
+
+// IsStoredAvatar, ProfileImage değerinin depoya yüklenmiş bir avatarı gösterip göstermediğini döndürür.
+// Diğer değerler (ör. Google profil resmi) harici URL'lerdir.
+func IsStoredAvatar(profileImage string) bool {
+	return strings.HasPrefix(profileImage, avatarKeyPrefix)
+}
+
+// AvatarVersion, depodaki avatarın önbellek kırmak için URL'lere eklenen sürümüdür.
+func AvatarVersion(profileImage string) string {
+	return profileImage[strings.LastIndex(profileImage, "/")+1:]
+}
+
+func (s *AvatarService) remove(ctx context.Context, prefix string) {
+	if err := s.storage.DeletePrefix(ctx, prefix); err != nil {
+		log.Printf("Could not delete avatar files under %s: %v", prefix, err)
+	}
+}
+
+func avatarKey(prefix string, size int) string {
+	return prefix + "/" + strconv.Itoa(size) + ".png"
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dione-docs-backend/internal/delta"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BlameRange, belgenin güncel içeriğindeki bir aralığın yazarını belirtir.
+type BlameRange struct {
+	Start   int       `json:"start"`
+	Length  int       `json:"length"`
+	UserID  uuid.UUID `json:"user_id"`
+	Version int       `json:"version"`
+}
+
+// BlameService, belgenin operasyon kaydından her bölümü yazan kullanıcıyı hesaplar.
+//
+// Canlı oturumdaki her operasyon hub tarafından gönderen kullanıcıyla, hub dışındaki
+// değişiklikler (düzenleme, geri yükleme, öneri kabulü) değişikliği yapan kullanıcıyla
+// kaydedilir; bu yüzden aynı oturumda birlikte yazan kullanıcıların metinleri ayrı ayrı
+// atfedilir ve saklama politikasının sildiği versiyonlar atfı etkilemez. Kayıt tutulmaya
+// başlamadan önce var olan içerik, kaydın temel girdisiyle belge sahibine (ilk versiyonsa)
+// veya bilinmeyen yazara atfedilir.
+type BlameService struct {
+	attributionRepo repository.AttributionRepository
+}
+
+func NewBlameService(attributionRepo repository.AttributionRepository) *BlameService {
+	return &BlameService{attributionRepo: attributionRepo}
+}
+
+// attributionState, operasyonlar sırayla uygulanırken oluşan içeriği ve yazar aralıklarını tutar.
+type attributionState struct {
+	content         delta.Delta
+	spans           []models.AttributionSpan
+	lastOperationID int64
+}
+
+// apply, operation'ı içeriğe ve yazar aralıklarına uygular. Değişiklik çözülemiyorsa veya
+// içeriğin uzunluğuna uymuyorsa (ör. kaydedilemeyen operasyonlar) atlanır; aradaki fark
+// Blame sırasında kayıtlı içerikle karşılaştırılarak giderilir.
+func (s *attributionState) apply(operation *models.DocumentOperation) {
+	s.lastOperationID = operation.ID
+	change, err := delta.Parse(operation.Change)
+	if err != nil || baseLength(change) > s.content.Length() {
+		return
+	}
+	s.content = delta.Compose(s.content, change)
+	s.spans = applyAttribution(s.spans, change, operation.UserID, operation.Version)
+}
+
+// Blame, belgenin güncel içeriği için yazar aralıklarını döndürür. Önceden hesaplanmış
+// indeks varsa yalnızca sonraki operasyonlar işlenir.
+func (s *BlameService) Blame(doc *models.Document) ([]BlameRange, error) {
+	spans, err := s.attribution(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := make([]BlameRange, 0, len(spans))
+	start := 0
+	for _, span := range spans {
+		ranges = append(ranges, BlameRange{
+			Start:   start,
+			Length:  span.Length,
+			UserID:  span.UserID,
+			Version: span.Version,
+		})
+		start += span.Length
+	}
+	return ranges, nil
+}
+
+func (s *BlameService) attribution(doc *models.Document) ([]models.AttributionSpan, error) {
+	state, err := s.cachedState(doc.ID)
+	if err != nil {
+		return nil, err
+	}
+	cachedID := state.lastOperationID
+
+	err = s.attributionRepo.ForEachOperation(doc.ID, cachedID, func(operation *models.DocumentOperation) error {
+		state.apply(operation)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if state.lastOperationID != cachedID {
+		if err := s.saveState(doc, state); err != nil {
+			return nil, err
+		}
+	}
+
+	// Kayıtlı içerik, henüz kaydedilmemiş operasyonlar veya kayda geçmemiş değişiklikler
+	// yüzünden operasyonların oluşturduğu içerikten farklı olabilir. Fark yazarı bilinmeyen
+	// değişiklik olarak uygulanır; önbelleğe yalnızca operasyonların sonucu yazılır.
+	current, err := delta.ParseDocument(doc.Content)
+	if err != nil {
+		return nil, fmt.Errorf("head: %w", err)
+	}
+	change, err := delta.Diff(state.content, current)
+	if err != nil {
+		return nil, fmt.Errorf("head: %w", err)
+	}
+	author := uuid.Nil
+	if state.lastOperationID == 0 && doc.Version == 1 {
+		// Henüz operasyon kaydı yok; ilk versiyonun içeriği belge sahibine aittir.
+		author = doc.OwnerID
+	}
+	spans := append([]models.AttributionSpan(nil), state.spans...)
+	return applyAttribution(spans, change, author, doc.Version), nil
+}
+
+// cachedState, önbellekteki indeksi yükler. Önbellek yoksa veya okunamıyorsa boş içerikten başlanır.
+func (s *BlameService) cachedState(documentID uuid.UUID) (*attributionState, error) {
+	state := &attributionState{}
+	cached, err := s.attributionRepo.GetByDocumentID(documentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var spans []models.AttributionSpan
+	if err := json.Unmarshal(cached.Spans, &spans); err != nil {
+		return state, nil
+	}
+	content, err := delta.ParseDocument(cached.Content)
+	if err != nil || content.Length() != spansLength(spans) {
+		return state, nil
+	}
+	state.content = content
+	state.spans = spans
+	state.lastOperationID = cached.LastOperationID
+	return state, nil
+}
+
+func (s *BlameService) saveState(doc *models.Document, state *attributionState) error {
+	spans, err := json.Marshal(state.spans)
+	if err != nil {
+		return err
+	}
+	content, err := state.content.Marshal()
+	if err != nil {
+		return err
+	}
+	return s.attributionRepo.Save(&models.DocumentAttribution{
+		DocumentID:      doc.ID,
+		Version:         doc.Version,
+		LastOperationID: state.lastOperationID,
+		Content:         content,
+		Spans:           spans,
+	})
+}
+
+// baseLength, change'in uygulanabilmesi için belgenin sahip olması gereken en az uzunluktur.
+func baseLength(change delta.Delta) int {
+	length := 0
+	for _, op := range change.Ops {
+		if op.Retain > 0 || op.Delete > 0 {
+			length += op.Length()
+		}
+	}
+	return length
+}
+
+func spansLength(spans []models.AttributionSpan) int {
+	total := 0
+	for _, span := range spans {
+		total += span.Length
+	}
+	return total
+}
+
+// applyAttribution, change Delta'sını yazar aralıklarına uygular; eklenen metin
+// author'a ve version'a atfedilir, biçim değişiklikleri yazarı değiştirmez.
+func applyAttribution(spans []models.AttributionSpan, change delta.Delta, author uuid.UUID, version int) []models.AttributionSpan {
+	var result []models.AttributionSpan
+	index, offset := 0, 0
+
+	// consume, mevcut aralıklardan length kadar ilerler; keep true ise bunları sonuca kopyalar.
+	consume := func(length int, keep bool) {
+		for length > 0 && index < len(spans) {
+			available := spans[index].Length - offset
+			n := min(length, available)
+			if keep {
+				span := spans[index]
+				span.Length = n
+				result = appendSpan(result, span)
+			}
+			length -= n
+			offset += n
+			if offset == spans[index].Length {
+				index++
+				offset = 0
+			}
+		}
+	}
+
+	for _, op := range change.Ops {
+		switch {
+		case op.Retain > 0:
+			consume(op.Retain, true)
+		case op.Delete > 0:
+			consume(op.Delete, false)
+		default:
+			result = appendSpan(result, models.AttributionSpan{Length: op.Length(), UserID: author, Version: version})
+		}
+	}
+	for index < len(spans) {
+		consume(spans[index].Length-offset, true)
+	}
+	return result
+}
+
+func appendSpan(spans []models.AttributionSpan, span models.AttributionSpan) []models.AttributionSpan {
+	if span.Length <= 0 {
+		return spans
+	}
+	if n := len(spans); n > 0 && spans[n-1].UserID == span.UserID && spans[n-1].Version == span.Version {
+		spans[n-1].Length += span.Length
+		return spans
+	}
+	return append(spans, span)
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/dione-docs-backend/internal/config"
+	"github.com/dione-docs-backend/internal/mfa"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/signing"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// mfaChallengeTTL, şifre doğrulandıktan sonra ikinci adımın tamamlanması için tanınan süredir.
+const mfaChallengeTTL = 5 * time.Minute
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrMFANotEnrolled    = errors.New("two-factor enrollment has not been started")
+	ErrInvalidMFACode    = errors.New("invalid two-factor code")
+	ErrInvalidMFAToken   = errors.New("invalid or expired two-factor challenge")
+)
+
+// MFAEnrollment, doğrulama uygulamasına eklenecek TOTP anahtarıdır.
+type MFAEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// MFAService, TOTP iki adımlı doğrulamanın kurulumunu, girişteki ikinci adımı ve kurtarma kodlarını yönetir.
+type MFAService struct {
+	repo *repository.Repository
+	cfg  *config.Config
+	keys *signing.KeySet
+}
+
+func NewMFAService(repo *repository.Repository, cfg *config.Config, keys *signing.KeySet) *MFAService {
+	return &MFAService{
+		repo: repo,
+		cfg:  cfg,
+		keys: keys,
+	}
+}
+
+// IsEnabled, kullanıcının girişte ikinci adım gerektirip gerektirmediğini döndürür.
+func (s *MFAService) IsEnabled(userID uuid.UUID) (bool, error) {
+	return s.repo.MFA.IsEnabled(userID)
+}
+
+// Enroll, kullanıcı için yeni bir TOTP anahtarı üretir. Anahtar Confirm ile doğrulanana kadar
+// girişte kullanılmaz; tamamlanmamış bir kurulum yenisiyle değiştirilir.
+func (s *MFAService) Enroll(user *models.User) (*MFAEnrollment, error) {
+	existing, err := s.repo.MFA.GetByUserID(user.ID)
+	if err == nil && existing.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	secret, err := mfa.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := mfa.Seal(s.cfg.MFAEncryptionKey, secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.MFA.Save(&models.UserMFA{UserID: user.ID, Secret: sealed}); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollment{
+		Secret: secret,
+		URI:    mfa.URI(s.cfg.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm, uygulamadan gelen ilk kodla kurulumu tamamlar ve kullanıcıya gösterilecek kurtarma kodlarını döndürür.
+func (s *MFAService) Confirm(userID uuid.UUID, code string) ([]string, error) {
+	setting, err := s.repo.MFA.GetByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMFANotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+	if setting.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	step, ok, err := s.validateTOTP(setting, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.MFA.Enable(userID, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Verify, TOTP kodunu veya kullanılmamış bir kurtarma kodunu doğrular. Kullanılan kodlar tekrar kabul edilmez.
+func (s *MFAService) Verify(userID uuid.UUID, code string) error {
+	setting, err := s.repo.MFA.GetByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrMFANotEnabled
+	}
+	if err != nil {
+		return err
+	}
+	if !setting.Enabled {
+		return ErrMFANotEnabled
+	}
+
+	step, ok, err := s.validateTOTP(setting, code)
+	if err != nil {
+		return err
+	}
+	if ok {
+		used, err := s.repo.MFA.UseStep(userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	used, err := s.repo.MFA.UseRecoveryCode(userID, s.hash(mfa.NormalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// Disable, geçerli bir kodla iki adımlı doğrulamayı kapatır ve kurtarma kodlarını siler.
+func (s *MFAService) Disable(userID uuid.UUID, code string) error {
+	if err := s.Verify(userID, code); err != nil {
+		return err
+	}
+	return s.repo.MFA.Delete(userID)
+}
+
+// RegenerateRecoveryCodes, geçerli bir kodla eski kurtarma kodlarını geçersiz kılar ve yenilerini döndürür.
+func (s *MFAService) RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error) {
+	if err := s.Verify(userID, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.MFA.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// RemainingRecoveryCodes, kullanıcının kullanılmamış kurtarma kodu sayısını döndürür.
+func (s *MFAService) RemainingRecoveryCodes(userID uuid.UUID) (int64, error) {
+	return s.repo.MFA.CountRecoveryCodes(userID)
+}
+
+// IssueChallenge, şifresi doğrulanmış kullanıcı için ikinci adımda kullanılacak kısa ömürlü token'ı üretir.
+func (s *MFAService) IssueChallenge(userID uuid.UUID) (string, int, error) {
+	token, err := utils.GenerateMFAToken(s.keys, userID.String(), mfaChallengeTTL)
+	if err != nil {
+		return "", 0, err
+	}
+	return token, int(mfaChallengeTTL.Seconds()), nil
+}
+
+// ParseChallenge, ikinci adım token'ını doğrular ve ait olduğu kullanıcının ID'sini ve token'ın
+// ID'sini döndürür. Hız sınırları denemeleri bu değerlere göre sayar.
+func (s *MFAService) ParseChallenge(token string) (uuid.UUID, string, error) {
+	subject, challengeID, err := utils.ParseMFAToken(s.keys, token)
+	if err != nil {
+		return uuid.Nil, "", ErrInvalidMFAToken
+	}
+	userID, err := uuid.Parse(subject)
+	if err != nil {
+		return uuid.Nil, "", ErrInvalidMFAToken
+	}
+	return userID, challengeID, nil
+}
+
+// CompleteChallenge, ikinci adım token'ını ve kodu doğrular ve giriş yapan kullanıcının ID'sini döndürür.
+func (s *MFAService) CompleteChallenge(token, code string) (uuid.UUID, error) {
+	userID, _, err := s.ParseChallenge(token)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := s.Verify(userID, code); err != nil {
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
+
+func (s *MFAService) validateTOTP(setting *models.UserMFA, code string) (int64, bool, error) {
+	secret, err := mfa.Open(s.cfg.MFAEncryptionKey, setting.Secret)
+	if err != nil {
+		return 0, false, err
+	}
+	step, ok := mfa.Validate(secret, code, time.Now())
+	return step, ok, nil
+}
+
+func (s *MFAService) newRecoveryCodes() ([]string, []string, error) {
+	codes, err := mfa.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = s.hash(mfa.NormalizeRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hash, kurtarma kodunu sunucu anahtarıyla imzalar.
+func (s *MFAService) hash(code string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.MFAEncryptionKey))
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/google/uuid"
+)
+
+// SessionDisconnector, bir kullanıcının belgeye ait canlı bağlantılarını kapatabilen
+// bileşenlerdir (ör. websocket hub yöneticileri).
+type SessionDisconnector interface {
+	DisconnectUser(documentID, userID uuid.UUID)
+	// ActiveDocuments, canlı bağlantısı olabilecek (hub'ı açık) belgeleri döndürür.
+	ActiveDocuments() []uuid.UUID
+}
+
+// PermissionExpiryService, süresi dolan izinleri işaretler, kullanıcının canlı
+// bağlantılarını kapatır ve belge sahibini bilgilendirir.
+type PermissionExpiryService struct {
+	repo          *repository.Repository
+	disconnectors []SessionDisconnector
+}
+
+func NewPermissionExpiryService(repo *repository.Repository) *PermissionExpiryService {
+	return &PermissionExpiryService{repo: repo}
+}
+
+// AddDisconnector, süresi dolan kullanıcıların bağlantılarını kapatacak bir bileşen ekler.
+// Zamanlayıcı başlamadan önce çağrılmalıdır.
+func (s *PermissionExpiryService) AddDisconnector(disconnector SessionDisconnector) {
+	s.disconnectors = append(s.disconnectors, disconnector)
+}
+
+// RunAll, süresi dolmuş tüm izinleri işler.
+func (s *PermissionExpiryService) RunAll(ctx context.Context) error {
+	expired, err := s.repo.Permission.ExpireDue(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to expire permissions: %w", err)
+	}
+
+	for _, permission := range expired {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for _, disconnector := range s.disconnectors {
+			disconnector.DisconnectUser(permission.DocumentID, permission.UserID)
+		}
+		if err := s.notifyOwner(permission); err != nil {
+			log.Printf("Permission expiry: failed to notify owner of document %s: %v", permission.DocumentID, err)
+		}
+	}
+
+	if len(expired) > 0 {
+		log.Printf("Permission expiry: %d permissions expired", len(expired))
+	}
+	return nil
+}
+
+func (s *PermissionExpiryService) notifyOwner(permission models.Permission) error {
+	var doc models.Document
+	if err := s.repo.Document.GetByID(permission.DocumentID, &doc); err != nil {
+		return err
+	}
+
+	who := permission.UserID.String()
+	var user models.User
+	if err := s.repo.User.GetByID(permission.UserID, &user); err == nil {
+		who = user.Email
+	}
+
+	return s.repo.Notification.Create(&models.Notification{
+		UserID:     doc.OwnerID,
+		Type:       models.NotificationTypePermissionExpired,
+		DocumentID: &doc.ID,
+		ActorID:    &permission.UserID,
+		Message:    fmt.Sprintf("%s kullanıcısının \"%s\" belgesine erişim süresi doldu.", who, doc.Title),
+	})
+}
@@ -0,0 +1,509 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/dione-docs-backend/internal/avatar"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/storage"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrSoleOrganizationOwner, kullanıcı başka üyeleri olan bir organizasyonun tek sahibiyse döner.
+	ErrSoleOrganizationOwner = errors.New("user is the only owner of an organization")
+	// ErrInvalidTransferRecipient, belgeler devredilecek kullanıcı organizasyon belgelerini alamıyorsa döner.
+	ErrInvalidTransferRecipient = errors.New("recipient cannot own the user's organization documents")
+)
+
+// PrivacyService, kullanıcının hesabını silme ve hakkında saklanan verileri dışa aktarma
+// isteklerini yürütür.
+type PrivacyService struct {
+	repo    *repository.Repository
+	avatars *AvatarService
+}
+
+func NewPrivacyService(repo *repository.Repository, avatars *AvatarService) *PrivacyService {
+	return &PrivacyService{
+		repo:    repo,
+		avatars: avatars,
+	}
+}
+
+// DeleteAccount, kullanıcının hesabını siler. transferTo verilmişse sahip olunan belgeler ona
+// devredilir, verilmemişse kalıcı olarak silinir. Organizasyon belgeleri yalnızca o
+// organizasyonun misafir olmayan bir üyesine devredilebilir. Kullanıcı başka üyeleri olan bir
+// organizasyonun tek sahibiyse önce sahipliği devretmesi gerekir.
+func (s *PrivacyService) DeleteAccount(ctx context.Context, user *models.User, transferTo *models.User) error {
+	organizations, err := s.repo.Organization.GetByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+	for _, organization := range organizations {
+		member, err := s.repo.Organization.GetMember(organization.ID, user.ID)
+		if err != nil {
+			return err
+		}
+		if member.Role != models.OrganizationRoleOwner {
+			continue
+		}
+		owners, err := s.repo.Organization.CountOwners(organization.ID)
+		if err != nil {
+			return err
+		}
+		members, err := s.repo.Organization.GetMembers(organization.ID)
+		if err != nil {
+			return err
+		}
+		if owners == 1 && len(members) > 1 {
+			return fmt.Errorf("%w: %s", ErrSoleOrganizationOwner, organization.Name)
+		}
+	}
+
+	var (
+		recipientID *uuid.UUID
+		transferred []models.Document
+	)
+	if transferTo != nil {
+		if transferTo.ID == user.ID {
+			return ErrInvalidTransferRecipient
+		}
+		if transferred, err = s.repo.Document.GetByOwnerID(user.ID); err != nil {
+			return err
+		}
+		if err := s.checkRecipient(transferred, transferTo.ID); err != nil {
+			return err
+		}
+		recipientID = &transferTo.ID
+	}
+
+	profileImage := user.ProfileImage
+	if err := s.repo.Account.Delete(user.ID, recipientID); err != nil {
+		return err
+	}
+	if IsStoredAvatar(profileImage) {
+		s.avatars.remove(ctx, profileImage)
+	}
+
+	for i := range transferred {
+		if err := s.repo.Notification.Create(&models.Notification{
+			UserID:     transferTo.ID,
+			Type:       models.NotificationTypeOwnershipReceived,
+			DocumentID: &transferred[i].ID,
+			Message:    fmt.Sprintf("\"%s\" belgesinin sahipliği, hesabını silen %s tarafından size devredildi.", transferred[i].Title, user.DisplayName()),
+		}); err != nil {
+			log.Printf("DeleteAccount - bildirim kaydedilemedi (belge %s): %v", transferred[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// checkRecipient, alıcının organizasyon belgelerinin bulunduğu her organizasyonda misafir
+// olmayan bir üye olduğunu doğrular.
+func (s *PrivacyService) checkRecipient(docs []models.Document, recipientID uuid.UUID) error {
+	checked := make(map[uuid.UUID]bool)
+	for _, doc := range docs {
+		if doc.OrganizationID == nil || checked[*doc.OrganizationID] {
+			continue
+		}
+		member, err := s.repo.Organization.GetMember(*doc.OrganizationID, recipientID)
+		if err != nil || member.Role == models.OrganizationRoleGuest {
+			return ErrInvalidTransferRecipient
+		}
+		checked[*doc.OrganizationID] = true
+	}
+	return nil
+}
+
+// Export, kullanıcı hakkında saklanan verileri JSON dosyaları ve yüklenmiş avatar resimleri
+// içeren bir zip arşivi olarak w'ye yazar. Şifre ve token özetleri gibi gizli değerler dahil edilmez.
+func (s *PrivacyService) Export(ctx context.Context, user *models.User, w io.Writer) error {
+	data, err := s.repo.Account.Export(user.ID)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+	files := []struct {
+		name  string
+		value interface{}
+	}{
+		{"profile.json", newExportProfile(data)},
+		{"documents.json", mapExport(data.Documents, newExportDocument)},
+		{"permissions.json", mapExport(data.Permissions, newExportPermission)},
+		{"chat_messages.json", mapExport(data.Messages, newExportMessage)},
+		{"comments.json", mapExport(data.Comments, newExportComment)},
+		{"suggestions.json", mapExport(data.Suggestions, newExportSuggestion)},
+		{"notifications.json", mapExport(data.Notifications, newExportNotification)},
+		{"sessions.json", mapExport(data.Sessions, newExportSession)},
+		{"access_tokens.json", mapExport(data.AccessTokens, newExportAccessToken)},
+		{"linked_identities.json", mapExport(data.Identities, newExportIdentity)},
+		{"team_memberships.json", mapExport(data.TeamMemberships, newExportTeamMembership)},
+		{"organization_memberships.json", mapExport(data.OrganizationMemberships, newExportOrganizationMembership)},
+		{"access_requests.json", mapExport(data.AccessRequests, newExportAccessRequest)},
+		{"ownership_transfers.json", mapExport(data.Transfers, newExportTransfer)},
+	}
+	for _, file := range files {
+		if err := writeJSONFile(archive, file.name, file.value); err != nil {
+			return err
+		}
+	}
+
+	if IsStoredAvatar(data.User.ProfileImage) {
+		for _, size := range avatar.Sizes {
+			if err := s.writeAvatar(ctx, archive, user.ID, size); err != nil {
+				return err
+			}
+		}
+	}
+	return archive.Close()
+}
+
+func (s *PrivacyService) writeAvatar(ctx context.Context, archive *zip.Writer, userID uuid.UUID, size int) error {
+	reader, err := s.avatars.Open(ctx, userID, size)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	file, err := archive.Create(fmt.Sprintf("avatar/%d.png", size))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, reader)
+	return err
+}
+
+func writeJSONFile(archive *zip.Writer, name string, value interface{}) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func mapExport[T, R any](items []T, convert func(*T) R) []R {
+	result := make([]R, len(items))
+	for i := range items {
+		result[i] = convert(&items[i])
+	}
+	return result
+}
+
+// rawJSON, jsonb olarak saklanan içeriği arşive base64 yerine JSON olarak yazar.
+func rawJSON(content []byte) json.RawMessage {
+	if len(content) == 0 {
+		return nil
+	}
+	return json.RawMessage(content)
+}
+
+type exportProfile struct {
+	ID            uuid.UUID `json:"id"`
+	Username      string    `json:"username"`
+	FullName      string    `json:"full_name"`
+	Email         string    `json:"email"`
+	PendingEmail  string    `json:"pending_email,omitempty"`
+	EmailVerified bool      `json:"email_verified"`
+	ProfileImage  string    `json:"profile_image,omitempty"`
+	HasPassword   bool      `json:"has_password"`
+	MFAEnabled    bool      `json:"mfa_enabled"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func newExportProfile(data *repository.AccountData) exportProfile {
+	return exportProfile{
+		ID:            data.User.ID,
+		Username:      data.User.Username,
+		FullName:      data.User.FullName,
+		Email:         data.User.Email,
+		PendingEmail:  data.User.PendingEmail,
+		EmailVerified: data.User.EmailVerified,
+		ProfileImage:  data.User.ProfileImage,
+		HasPassword:   data.User.PasswordHash != "",
+		MFAEnabled:    data.MFAEnabled,
+		CreatedAt:     data.User.CreatedAt,
+		UpdatedAt:     data.User.UpdatedAt,
+	}
+}
+
+type exportDocument struct {
+	ID             uuid.UUID       `json:"id"`
+	Title          string          `json:"title"`
+	Description    string          `json:"description"`
+	OrganizationID *uuid.UUID      `json:"organization_id,omitempty"`
+	Content        json.RawMessage `json:"content"`
+	Version        int             `json:"version"`
+	IsPublic       bool            `json:"is_public"`
+	Status         string          `json:"status"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	DeletedAt      *time.Time      `json:"deleted_at,omitempty"`
+}
+
+func newExportDocument(doc *models.Document) exportDocument {
+	export := exportDocument{
+		ID:             doc.ID,
+		Title:          doc.Title,
+		Description:    doc.Description,
+		OrganizationID: doc.OrganizationID,
+		Content:        rawJSON(doc.Content),
+		Version:        doc.Version,
+		IsPublic:       doc.IsPublic,
+		Status:         doc.Status,
+		CreatedAt:      doc.CreatedAt,
+		UpdatedAt:      doc.UpdatedAt,
+	}
+	if doc.DeletedAt.Valid {
+		export.DeletedAt = &doc.DeletedAt.Time
+	}
+	return export
+}
+
+type exportPermission struct {
+	DocumentID uuid.UUID  `json:"document_id"`
+	AccessType string     `json:"access_type"`
+	Status     string     `json:"status"`
+	SharedBy   uuid.UUID  `json:"shared_by"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newExportPermission(permission *models.Permission) exportPermission {
+	return exportPermission{
+		DocumentID: permission.DocumentID,
+		AccessType: permission.AccessType,
+		Status:     string(permission.Status),
+		SharedBy:   permission.SharedBy,
+		ExpiresAt:  permission.ExpiresAt,
+		CreatedAt:  permission.CreatedAt,
+	}
+}
+
+type exportMessage struct {
+	ID         uuid.UUID `json:"id"`
+	DocumentID uuid.UUID `json:"document_id"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func newExportMessage(message *models.Message) exportMessage {
+	return exportMessage{
+		ID:         message.ID,
+		DocumentID: message.DocumentID,
+		Content:    message.Content,
+		CreatedAt:  message.CreatedAt,
+	}
+}
+
+type exportComment struct {
+	ID         uuid.UUID  `json:"id"`
+	DocumentID uuid.UUID  `json:"document_id"`
+	ParentID   *uuid.UUID `json:"parent_id,omitempty"`
+	Body       string     `json:"body"`
+	Quote      string     `json:"quote,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func newExportComment(comment *models.Comment) exportComment {
+	return exportComment{
+		ID:         comment.ID,
+		DocumentID: comment.DocumentID,
+		ParentID:   comment.ParentID,
+		Body:       comment.Body,
+		Quote:      comment.Quote,
+		ResolvedAt: comment.ResolvedAt,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+	}
+}
+
+type exportSuggestion struct {
+	ID         uuid.UUID       `json:"id"`
+	DocumentID uuid.UUID       `json:"document_id"`
+	Change     json.RawMessage `json:"change"`
+	Status     string          `json:"status"`
+	ResolvedAt *time.Time      `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+func newExportSuggestion(suggestion *models.Suggestion) exportSuggestion {
+	return exportSuggestion{
+		ID:         suggestion.ID,
+		DocumentID: suggestion.DocumentID,
+		Change:     rawJSON(suggestion.Change),
+		Status:     string(suggestion.Status),
+		ResolvedAt: suggestion.ResolvedAt,
+		CreatedAt:  suggestion.CreatedAt,
+	}
+}
+
+type exportNotification struct {
+	Type       string     `json:"type"`
+	DocumentID *uuid.UUID `json:"document_id,omitempty"`
+	Message    string     `json:"message"`
+	ReadAt     *time.Time `json:"read_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newExportNotification(notification *models.Notification) exportNotification {
+	return exportNotification{
+		Type:       string(notification.Type),
+		DocumentID: notification.DocumentID,
+		Message:    notification.Message,
+		ReadAt:     notification.ReadAt,
+		CreatedAt:  notification.CreatedAt,
+	}
+}
+
+type exportSession struct {
+	ID         uuid.UUID  `json:"id"`
+	DeviceName string     `json:"device_name,omitempty"`
+	IPAddress  string     `json:"ip_address,omitempty"`
+	UserAgent  string     `json:"user_agent,omitempty"`
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newExportSession(session *models.Session) exportSession {
+	return exportSession{
+		ID:         session.ID,
+		DeviceName: session.DeviceName,
+		IPAddress:  session.IPAddress,
+		UserAgent:  session.UserAgent,
+		LastUsedAt: session.LastUsedAt,
+		ExpiresAt:  session.ExpiresAt,
+		RevokedAt:  session.RevokedAt,
+		CreatedAt:  session.CreatedAt,
+	}
+}
+
+type exportAccessToken struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     string     `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newExportAccessToken(token *models.PersonalAccessToken) exportAccessToken {
+	return exportAccessToken{
+		ID:         token.ID,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.Scopes,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		RevokedAt:  token.RevokedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+type exportIdentity struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newExportIdentity(identity *models.UserIdentity) exportIdentity {
+	return exportIdentity{
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
+
+type exportTeamMembership struct {
+	TeamID    uuid.UUID `json:"team_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newExportTeamMembership(member *models.TeamMember) exportTeamMembership {
+	return exportTeamMembership{
+		TeamID:    member.TeamID,
+		Role:      string(member.Role),
+		CreatedAt: member.CreatedAt,
+	}
+}
+
+type exportOrganizationMembership struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func newExportOrganizationMembership(member *models.OrganizationMember) exportOrganizationMembership {
+	return exportOrganizationMembership{
+		OrganizationID: member.OrganizationID,
+		Role:           string(member.Role),
+		CreatedAt:      member.CreatedAt,
+	}
+}
+
+type exportAccessRequest struct {
+	ID         uuid.UUID  `json:"id"`
+	DocumentID uuid.UUID  `json:"document_id"`
+	AccessType string     `json:"access_type"`
+	Message    string     `json:"message,omitempty"`
+	Status     string     `json:"status"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newExportAccessRequest(request *models.AccessRequest) exportAccessRequest {
+	return exportAccessRequest{
+		ID:         request.ID,
+		DocumentID: request.DocumentID,
+		AccessType: request.AccessType,
+		Message:    request.Message,
+		Status:     string(request.Status),
+		ResolvedAt: request.ResolvedAt,
+		CreatedAt:  request.CreatedAt,
+	}
+}
+
+type exportTransfer struct {
+	ID          uuid.UUID  `json:"id"`
+	DocumentID  uuid.UUID  `json:"document_id"`
+	FromUserID  uuid.UUID  `json:"from_user_id"`
+	ToUserID    uuid.UUID  `json:"to_user_id"`
+	Status      string     `json:"status"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func newExportTransfer(transfer *models.OwnershipTransfer) exportTransfer {
+	return exportTransfer{
+		ID:          transfer.ID,
+		DocumentID:  transfer.DocumentID,
+		FromUserID:  transfer.FromUserID,
+		ToUserID:    transfer.ToUserID,
+		Status:      string(transfer.Status),
+		RespondedAt: transfer.RespondedAt,
+		CreatedAt:   transfer.CreatedAt,
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/dione-docs-backend/internal/config"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RetentionPolicy, versiyonların ne kadar süreyle hangi sıklıkta saklanacağını tanımlar.
+// KeepAll süresince tüm versiyonlar, HourlyFor süresince her saatin en yeni versiyonu,
+// daha eskiler için her günün en yeni versiyonu saklanır. İsimlendirilmiş versiyonlar
+// her zaman saklanır.
+type RetentionPolicy struct {
+	KeepAll   time.Duration
+	HourlyFor time.Duration
+	Disabled  bool
+}
+
+// RetentionPlan, bir belge için politikanın uygulanması halinde saklanacak ve silinecek versiyonları içerir.
+type RetentionPlan struct {
+	DocumentID uuid.UUID
+	Policy     RetentionPolicy
+	Overridden bool
+	Keep       []models.DocumentVersion
+	Prune      []models.DocumentVersion
+}
+
+type RetentionService struct {
+	docRepo       repository.DocumentRepository
+	retentionRepo repository.RetentionRepository
+	defaults      RetentionPolicy
+}
+
+func NewRetentionService(docRepo repository.DocumentRepository, retentionRepo repository.RetentionRepository, cfg *config.Config) *RetentionService {
+	return &RetentionService{
+		docRepo:       docRepo,
+		retentionRepo: retentionRepo,
+		defaults: RetentionPolicy{
+			KeepAll:   time.Duration(cfg.VersionKeepAllHours) * time.Hour,
+			HourlyFor: time.Duration(cfg.VersionHourlyDays) * 24 * time.Hour,
+		},
+	}
+}
+
+// Defaults, belge bazında geçersiz kılınmamış belgeler için kullanılan politikayı döndürür.
+func (s *RetentionService) Defaults() RetentionPolicy {
+	return s.defaults
+}
+
+// PolicyFor, belgeye uygulanacak politikayı ve bunun belgeye özel olup olmadığını döndürür.
+func (s *RetentionService) PolicyFor(documentID uuid.UUID) (RetentionPolicy, bool, error) {
+	override, err := s.retentionRepo.GetByDocumentID(documentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.defaults, false, nil
+		}
+		return RetentionPolicy{}, false, err
+	}
+	return RetentionPolicy{
+		KeepAll:   time.Duration(override.KeepAllHours) * time.Hour,
+		HourlyFor: time.Duration(override.HourlyDays) * 24 * time.Hour,
+		Disabled:  override.Disabled,
+	}, true, nil
+}
+
+// Plan, belgenin versiyonlarına politikayı uygular ancak hiçbir şeyi silmez (dry-run).
+func (s *RetentionService) Plan(documentID uuid.UUID, now time.Time) (*RetentionPlan, error) {
+	policy, overridden, err := s.PolicyFor(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load retention policy: %w", err)
+	}
+
+	versions, _, err := s.docRepo.GetVersions(documentID, repository.VersionFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load versions: %w", err)
+	}
+
+	plan := &RetentionPlan{
+		DocumentID: documentID,
+		Policy:     policy,
+		Overridden: overridden,
+	}
+	if policy.Disabled {
+		plan.Keep = versions
+		return plan, nil
+	}
+	plan.Keep, plan.Prune = PlanRetention(versions, policy, now)
+	return plan, nil
+}
+
+// Apply, belge için politikayı uygular ve silinen versiyon sayısını döndürür.
+func (s *RetentionService) Apply(documentID uuid.UUID, now time.Time) (int64, error) {
+	plan, err := s.Plan(documentID, now)
+	if err != nil {
+		return 0, err
+	}
+	if len(plan.Prune) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uuid.UUID, len(plan.Prune))
+	for i, v := range plan.Prune {
+		ids[i] = v.ID
+	}
+	return s.docRepo.DeleteVersions(documentID, ids)
+}
+
+// RunAll, versiyonu olan tüm belgelere politikayı uygular. Scheduler tarafından çağrılır.
+func (s *RetentionService) RunAll(ctx context.Context) error {
+	documentIDs, err := s.docRepo.GetDocumentIDsWithVersions()
+	if err != nil {
+		return fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	now := time.Now()
+	var pruned int64
+	for _, documentID := range documentIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		deleted, err := s.Apply(documentID, now)
+		if err != nil {
+			log.Printf("Retention: document %s skipped: %v", documentID, err)
+			continue
+		}
+		pruned += deleted
+	}
+
+	if pruned > 0 {
+		log.Printf("Retention: pruned %d versions across %d documents", pruned, len(documentIDs))
+	}
+	return nil
+}
+
+// PlanRetention, versiyonları politikaya göre saklanacak ve silinecek olarak ayırır.
+// Her saat/gün diliminde en yeni versiyon saklanır; isimlendirilmiş versiyonlar her zaman saklanır.
+func PlanRetention(versions []models.DocumentVersion, policy RetentionPolicy, now time.Time) (keep, prune []models.DocumentVersion) {
+	// Dilimlerde en yeni versiyonu tutabilmek için versiyon numarasına göre azalan sırada ilerle.
+	ordered := make([]models.DocumentVersion, len(versions))
+	copy(ordered, versions)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].Version > ordered[j].Version
+	})
+
+	seenBuckets := make(map[string]bool)
+	for _, v := range ordered {
+		age := now.Sub(v.CreatedAt)
+
+		var bucket string
+		switch {
+		case v.IsNamed() || age <= policy.KeepAll:
+			keep = append(keep, v)
+			continue
+		case age <= policy.HourlyFor:
+			bucket = "h:" + v.CreatedAt.UTC().Format("2006-01-02T15")
+		default:
+			bucket = "d:" + v.CreatedAt.UTC().Format("2006-01-02")
+		}
+
+		if seenBuckets[bucket] {
+			prune = append(prune, v)
+			continue
+		}
+		seenBuckets[bucket] = true
+		keep = append(keep, v)
+	}
+	return keep, prune
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/dione-docs-backend/internal/models"
+)
+
+func versionNumbers(versions []models.DocumentVersion) []int {
+	numbers := make([]int, len(versions))
+	for i, v := range versions {
+		numbers[i] = v.Version
+	}
+	sort.Ints(numbers)
+	return numbers
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPlanRetention(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 30, 0, 0, time.UTC)
+	policy := RetentionPolicy{KeepAll: 24 * time.Hour, HourlyFor: 7 * 24 * time.Hour}
+
+	at := func(version int, createdAt time.Time, name string) models.DocumentVersion {
+		return models.DocumentVersion{Version: version, CreatedAt: createdAt, Name: name}
+	}
+
+	tests := []struct {
+		name      string
+		versions  []models.DocumentVersion
+		wantKeep  []int
+		wantPrune []int
+	}{
+		{
+			name: "recent versions are all kept",
+			versions: []models.DocumentVersion{
+				at(1, now.Add(-23*time.Hour), ""),
+				at(2, now.Add(-2*time.Hour), ""),
+				at(3, now.Add(-time.Minute), ""),
+			},
+			wantKeep: []int{1, 2, 3},
+		},
+		{
+			name: "newest version of each hour is kept",
+			versions: []models.DocumentVersion{
+				at(1, time.Date(2024, 6, 8, 10, 5, 0, 0, time.UTC), ""),
+				at(2, time.Date(2024, 6, 8, 10, 40, 0, 0, time.UTC), ""),
+				at(3, time.Date(2024, 6, 8, 10, 55, 0, 0, time.UTC), ""),
+				at(4, time.Date(2024, 6, 8, 11, 1, 0, 0, time.UTC), ""),
+			},
+			wantKeep:  []int{3, 4},
+			wantPrune: []int{1, 2},
+		},
+		{
+			name: "newest version of each day is kept after the hourly window",
+			versions: []models.DocumentVersion{
+				at(1, time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), ""),
+				at(2, time.Date(2024, 5, 1, 17, 0, 0, 0, time.UTC), ""),
+				at(3, time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC), ""),
+			},
+			wantKeep:  []int{2, 3},
+			wantPrune: []int{1},
+		},
+		{
+			name: "named versions are always kept",
+			versions: []models.DocumentVersion{
+				at(1, time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), "Draft"),
+				at(2, time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC), ""),
+				at(3, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), ""),
+			},
+			wantKeep:  []int{1, 3},
+			wantPrune: []int{2},
+		},
+		{
+			name: "named version does not occupy its bucket",
+			versions: []models.DocumentVersion{
+				at(1, time.Date(2024, 6, 8, 10, 5, 0, 0, time.UTC), ""),
+				at(2, time.Date(2024, 6, 8, 10, 50, 0, 0, time.UTC), "Release"),
+			},
+			wantKeep: []int{1, 2},
+		},
+		{
+			name: "buckets use version order, not input order",
+			versions: []models.DocumentVersion{
+				at(5, time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC), ""),
+				at(4, time.Date(2024, 5, 3, 11, 0, 0, 0, time.UTC), ""),
+				at(6, time.Date(2024, 5, 3, 13, 0, 0, 0, time.UTC), ""),
+			},
+			wantKeep:  []int{6},
+			wantPrune: []int{4, 5},
+		},
+		{
+			name:     "no versions",
+			versions: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keep, prune := PlanRetention(tt.versions, policy, now)
+			if got := versionNumbers(keep); !equalInts(got, tt.wantKeep) {
+				t.Errorf("keep = %v, want %v", got, tt.wantKeep)
+			}
+			if got := versionNumbers(prune); !equalInts(got, tt.wantPrune) {
+				t.Errorf("prune = %v, want %v", got, tt.wantPrune)
+			}
+		})
+	}
+}
+
+func TestPlanRetentionDoesNotReorderInput(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	versions := []models.DocumentVersion{
+		{Version: 1, CreatedAt: now.Add(-time.Hour)},
+		{Version: 2, CreatedAt: now},
+	}
+	PlanRetention(versions, RetentionPolicy{KeepAll: time.Hour}, now)
+	if versions[0].Version != 1 || versions[1].Version != 2 {
+		t.Fatalf("input reordered: %v", versionNumbers(versions))
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type scheduledJob struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+// Scheduler, arka planda belirli aralıklarla çalışması gereken işleri yönetir.
+// Her iş kendi goroutine'inde çalışır ve context iptal edildiğinde durur.
+type Scheduler struct {
+	jobs []scheduledJob
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every, verilen aralıkla çalıştırılacak bir iş ekler. Start'tan önce çağrılmalıdır.
+func (s *Scheduler) Every(name string, interval time.Duration, run func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, scheduledJob{name: name, interval: interval, run: run})
+}
+
+// Start, tüm işleri başlatır ve hemen döner.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		go s.loop(ctx, job)
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, job scheduledJob) {
+	log.Printf("Scheduler: job %q started (every %s)", job.name, job.interval)
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Scheduler: job %q stopped", job.name)
+			return
+		case <-ticker.C:
+			if err := job.run(ctx); err != nil {
+				log.Printf("Scheduler: job %q failed: %v", job.name, err)
+			}
+		}
+	}
+}
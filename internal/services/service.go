@@ -1,10 +1,54 @@
 package services
 
-import "github.com/dione-docs-backend/internal/repository"
+import (
+	"github.com/dione-docs-backend/internal/config"
+	"github.com/dione-docs-backend/internal/mail"
+	"github.com/dione-docs-backend/internal/ratelimit"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/signing"
+	"github.com/dione-docs-backend/internal/storage"
+)
 
 type Service struct {
+	Import    *ImportService
+	Retention *RetentionService
+	Blame     *BlameService
+	Expiry    *PermissionExpiryService
+	Mailer    mail.Sender
+	Sessions  *SessionService
+	Accounts  *AccountService
+	MFA       *MFAService
+	Tokens    *AccessTokenService
+	Avatars   *AvatarService
+	Privacy   *PrivacyService
+	Limiter   ratelimit.Store
+	Signing   *signing.KeySet
 }
 
-func NewService(repo *repository.Repository) *Service {
-	return &Service{}
+func NewService(repo *repository.Repository, cfg *config.Config, keys *signing.KeySet) *Service {
+	mailer := mail.NewSender(cfg)
+	avatars := NewAvatarService(repo.User, storage.New(cfg))
+	return &Service{
+		Import:    NewImportService(repo.Document, cfg),
+		Retention: NewRetentionService(repo.Document, repo.Retention, cfg),
+		Blame:     NewBlameService(repo.Attribution),
+		Expiry:    NewPermissionExpiryService(repo),
+		Mailer:    mailer,
+		Sessions:  NewSessionService(repo.Session, cfg, keys),
+		Accounts:  NewAccountService(repo, mailer, cfg),
+		MFA:       NewMFAService(repo, cfg, keys),
+		Tokens:    NewAccessTokenService(repo.AccessToken),
+		Avatars:   avatars,
+		Privacy:   NewPrivacyService(repo, avatars),
+		Limiter:   newRateLimitStore(repo, cfg),
+		Signing:   keys,
+	}
+}
+
+// newRateLimitStore, RATE_LIMIT_STORE ayarına göre hız sınırı deposunu seçer.
+func newRateLimitStore(repo *repository.Repository, cfg *config.Config) ratelimit.Store {
+	if cfg.RateLimitStore == "postgres" {
+		return repo.RateLimit
+	}
+	return ratelimit.NewMemoryStore()
 }
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dione-docs-backend/internal/config"
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/dione-docs-backend/internal/repository"
+	"github.com/dione-docs-backend/internal/signing"
+	"github.com/dione-docs-backend/internal/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// sessionTouchInterval, oturumun son kullanım zamanının erişim token'larıyla en fazla ne sıklıkla güncelleneceğidir.
+const sessionTouchInterval = time.Minute
+
+// sessionRetention, iptal edilmiş veya süresi dolmuş oturumların silinmeden önce listede kalacağı süredir.
+const sessionRetention = 7 * 24 * time.Hour
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrSessionRevoked      = errors.New("session has been revoked or expired")
+)
+
+// SessionMetadata, oturumun açıldığı veya kullanıldığı cihaz bilgileridir.
+type SessionMetadata struct {
+	DeviceName string
+	IPAddress  string
+	UserAgent  string
+}
+
+// TokenPair, oturum açıldığında veya yenilendiğinde istemciye verilen token'lardır.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int // erişim token'ının saniye cinsinden ömrü
+	SessionID    uuid.UUID
+}
+
+// SessionService, oturumları açar, yenileme token'larını döndürerek (rotation) yeni erişim
+// token'ları üretir ve erişim token'larının iptal edilmemiş bir oturuma ait olduğunu doğrular.
+type SessionService struct {
+	sessions repository.SessionRepository
+	cfg      *config.Config
+	keys     *signing.KeySet
+}
+
+func NewSessionService(sessions repository.SessionRepository, cfg *config.Config, keys *signing.KeySet) *SessionService {
+	return &SessionService{
+		sessions: sessions,
+		cfg:      cfg,
+		keys:     keys,
+	}
+}
+
+// Start, kullanıcı için yeni bir oturum açar.
+func (s *SessionService) Start(userID uuid.UUID, meta SessionMetadata) (*TokenPair, error) {
+	refreshToken, hash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		UserID:           userID,
+		RefreshTokenHash: hash,
+		DeviceName:       meta.DeviceName,
+		IPAddress:        meta.IPAddress,
+		UserAgent:        meta.UserAgent,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(s.cfg.RefreshTokenTTL),
+	}
+	if err := s.sessions.Create(session); err != nil {
+		return nil, err
+	}
+	return s.issue(session, refreshToken)
+}
+
+// Refresh, yenileme token'ını yenisiyle değiştirir ve yeni bir erişim token'ı üretir. Daha önce
+// kullanılmış bir token tekrar gelirse token çalınmış sayılır ve oturum iptal edilir.
+func (s *SessionService) Refresh(refreshToken string, meta SessionMetadata) (*TokenPair, error) {
+	hash := hashRefreshToken(refreshToken)
+
+	session, err := s.sessions.GetByRefreshTokenHash(hash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if reused, err := s.sessions.GetByPreviousTokenHash(hash); err == nil {
+			log.Printf("Refresh token reuse detected for session %s of user %s; revoking session", reused.ID, reused.UserID)
+			if _, err := s.sessions.Revoke(reused.UserID, reused.ID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsActive(time.Now()) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	newToken, newHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(s.cfg.RefreshTokenTTL)
+	rotated, err := s.sessions.Rotate(session.ID, hash, newHash, expiresAt, meta.IPAddress, meta.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, ErrInvalidRefreshToken
+	}
+	return s.issue(session, newToken)
+}
+
+// Authenticate, erişim token'ını doğrular ve ait olduğu oturumun hâlâ aktif olduğunu kontrol eder.
+func (s *SessionService) Authenticate(tokenString string, meta SessionMetadata) (*utils.Claims, uuid.UUID, error) {
+	claims, err := utils.ParseToken(s.keys, tokenString)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return nil, uuid.Nil, ErrSessionRevoked
+	}
+
+	var session models.Session
+	if err := s.sessions.GetByID(sessionID, &session); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, uuid.Nil, ErrSessionRevoked
+		}
+		return nil, uuid.Nil, err
+	}
+	if !session.IsActive(time.Now()) || session.UserID.String() != claims.UserID {
+		return nil, uuid.Nil, ErrSessionRevoked
+	}
+
+	if time.Since(session.LastUsedAt) > sessionTouchInterval {
+		if err := s.sessions.Touch(session.ID, meta.IPAddress, meta.UserAgent); err != nil {
+			log.Printf("Could not update last use of session %s: %v", session.ID, err)
+		}
+	}
+	return claims, session.ID, nil
+}
+
+// Revoke, kullanıcının bir oturumunu iptal eder. Oturum bulunamazsa veya zaten iptal edilmişse false döner.
+func (s *SessionService) Revoke(userID, sessionID uuid.UUID) (bool, error) {
+	revoked, err := s.sessions.Revoke(userID, sessionID)
+	return revoked > 0, err
+}
+
+// RevokeAll, kullanıcının except dışındaki tüm oturumlarını iptal eder ve iptal edilen sayıyı döndürür.
+func (s *SessionService) RevokeAll(userID uuid.UUID, except *uuid.UUID) (int64, error) {
+	return s.sessions.RevokeAll(userID, except)
+}
+
+// RunCleanup, uzun süre önce iptal edilmiş veya süresi dolmuş oturumları siler.
+func (s *SessionService) RunCleanup(ctx context.Context) error {
+	deleted, err := s.sessions.DeleteStale(time.Now().Add(-sessionRetention))
+	if err != nil {
+		return fmt.Errorf("failed to delete stale sessions: %w", err)
+	}
+	if deleted > 0 {
+		log.Printf("Session cleanup: deleted %d stale sessions", deleted)
+	}
+	return nil
+}
+
+func (s *SessionService) issue(session *models.Session, refreshToken string) (*TokenPair, error) {
+	accessToken, err := utils.GenerateToken(s.keys, session.UserID.String(), session.ID.String(), s.cfg.AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.cfg.AccessTokenTTL.Seconds()),
+		SessionID:    session.ID,
+	}, nil
+}
+
+// newRefreshToken, rastgele bir yenileme token'ı ve veritabanında saklanacak özetini üretir.
+func newRefreshToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
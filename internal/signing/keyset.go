@@ -0,0 +1,230 @@
+// Package signing, JWT'leri asimetrik anahtarlarla (RS256 veya EdDSA) imzalar ve doğrular.
+// Birden fazla anahtar aynı anda geçerli olabilir; her token imzalandığı anahtarın ID'sini
+// (kid) başlığında taşır. Böylece yeni bir anahtara geçildiğinde eski anahtarla imzalanmış
+// token'lar süreleri dolana kadar doğrulanmaya devam eder. Açık anahtarlar JWKS olarak
+// yayımlanır ve diğer servisler token'ları bu anahtarlarla doğrulayabilir.
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dione-docs-backend/internal/config"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// minRSABits, kabul edilen en kısa RSA anahtarıdır.
+const minRSABits = 2048
+
+var (
+	ErrUnknownKey        = errors.New("token signed with an unknown key")
+	ErrUnexpectedSigning = errors.New("unexpected signing method")
+)
+
+// Key, bir imzalama anahtarıdır. private nil ise anahtar yalnızca doğrulama içindir
+// (ör. emekliye ayrılmış bir anahtarın açık kısmı).
+type Key struct {
+	ID        string
+	Algorithm string
+	private   crypto.Signer
+	public    crypto.PublicKey
+}
+
+func (k *Key) method() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+// KeySet, token imzalamak için kullanılan etkin anahtarı ve doğrulamada kabul edilen tüm anahtarları tutar.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	issuer  string
+}
+
+// Load, JWT_KEYS_DIR klasöründeki PEM dosyalarını yükler; dosya adı (uzantısız) anahtarın kid
+// değeridir. Özel anahtarlar PKCS#8 veya PKCS#1, yalnızca doğrulama için tutulan anahtarlar PKIX
+// açık anahtar olabilir. Token'lar JWT_SIGNING_KEY_ID ile seçilen anahtarla, belirtilmemişse
+// adı alfabetik olarak en son gelen özel anahtarla imzalanır. Klasör ayarlanmamışsa yalnızca
+// JWT_ALLOW_EPHEMERAL_KEY açıkken, geliştirme için her açılışta yeni bir Ed25519 anahtarı üretilir.
+func Load(cfg *config.Config) (*KeySet, error) {
+	if cfg.JWTKeysDir == "" {
+		if !cfg.JWTAllowEphemeralKey {
+			return nil, errors.New("JWT_KEYS_DIR must be set (set JWT_ALLOW_EPHEMERAL_KEY=true to use a temporary key in development)")
+		}
+		log.Println("WARNING: JWT_KEYS_DIR is not set; signing tokens with an ephemeral Ed25519 key. Tokens become invalid on restart and cannot be verified by other instances")
+		key, err := generateEd25519Key("ephemeral")
+		if err != nil {
+			return nil, err
+		}
+		return NewKeySet(cfg.JWTIssuer, key.ID, key)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(cfg.JWTKeysDir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*Key, 0, len(paths))
+	for _, path := range paths {
+		key, err := loadKeyFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+
+	signingKeyID := cfg.JWTSigningKeyID
+	if signingKeyID == "" {
+		sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+		for _, key := range keys {
+			if key.private != nil {
+				signingKeyID = key.ID
+			}
+		}
+	}
+	return NewKeySet(cfg.JWTIssuer, signingKeyID, keys...)
+}
+
+// NewKeySet, verilen anahtarlardan bir küme oluşturur. signingKeyID özel anahtarı olan bir
+// anahtarı göstermelidir.
+func NewKeySet(issuer, signingKeyID string, keys ...*Key) (*KeySet, error) {
+	set := &KeySet{
+		keys:   make(map[string]*Key, len(keys)),
+		issuer: issuer,
+	}
+	for _, key := range keys {
+		if _, exists := set.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		set.keys[key.ID] = key
+	}
+
+	signing, ok := set.keys[signingKeyID]
+	if !ok || signing.private == nil {
+		return nil, fmt.Errorf("signing key %q not found or has no private key", signingKeyID)
+	}
+	set.signing = signing
+	return set, nil
+}
+
+// Issuer, token'ların iss claim'idir.
+func (s *KeySet) Issuer() string {
+	return s.issuer
+}
+
+// SigningKeyID, yeni token'ları imzalayan anahtarın kid değeridir.
+func (s *KeySet) SigningKeyID() string {
+	return s.signing.ID
+}
+
+// Sign, claim'leri etkin anahtarla imzalar ve başlığa anahtarın kid değerini yazar.
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.signing.method(), claims)
+	token.Header["kid"] = s.signing.ID
+	return token.SignedString(s.signing.private)
+}
+
+// Parse, token'ı başlığındaki kid ile bulunan anahtarla doğrular ve claim'leri claims'e yazar.
+// Token'ın algoritması anahtarın algoritmasıyla aynı olmalıdır.
+func (s *KeySet) Parse(tokenString string, claims jwt.Claims) error {
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := s.keys[kid]
+		if !ok {
+			return nil, ErrUnknownKey
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, ErrUnexpectedSigning
+		}
+		return key.public, nil
+	})
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
+// generateEd25519Key, yeni bir Ed25519 imzalama anahtarı üretir.
+func generateEd25519Key(id string) (*Key, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{ID: id, Algorithm: AlgorithmEdDSA, private: private, public: public}, nil
+}
+
+func loadKeyFile(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+		return newKey(id, signer, signer.Public())
+	case "RSA PRIVATE KEY":
+		private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newKey(id, private, &private.PublicKey)
+	case "PUBLIC KEY":
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return newKey(id, nil, public)
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
+
+// newKey, anahtarın tipine göre algoritmasını belirler.
+func newKey(id string, private crypto.Signer, public crypto.PublicKey) (*Key, error) {
+	key := &Key{ID: id, private: private, public: public}
+	switch public := public.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSABits)
+		}
+		key.Algorithm = AlgorithmRS256
+	case ed25519.PublicKey:
+		key.Algorithm = AlgorithmEdDSA
+	default:
+		return nil, errors.New("unsupported key type; use RSA or Ed25519")
+	}
+	return key, nil
+}
@@ -0,0 +1,28 @@
+// Package storage, yüklenen dosyaların (avatarlar vb.) saklandığı blob deposunu soyutlar.
+// Yerel geliştirmede dosyalar diske yazılır; başka depolar aynı arayüzle eklenebilir.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/dione-docs-backend/internal/config"
+)
+
+// ErrNotFound, anahtar için kayıtlı bir blob olmadığında döner.
+var ErrNotFound = errors.New("blob not found")
+
+// Storage, anahtar/değer biçiminde blob saklayan depoların arayüzüdür. Anahtarlar "/" ile
+// ayrılmış göreli yollardır (ör. "avatars/<kullanıcı>/<sürüm>/128.png").
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// DeletePrefix, anahtarı prefix ile başlayan tüm blobları siler.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// New, STORAGE_DIR ayarıyla yerel disk deposunu oluşturur.
+func New(cfg *config.Config) Storage {
+	return NewLocalStorage(cfg.StorageDir)
+}
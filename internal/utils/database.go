@@ -58,7 +58,15 @@ func MigrateDB(db *gorm.DB) error {
 		return fmt.Errorf("failed to create uuid extension: %w", err)
 	}
 
-	err := db.AutoMigrate(&models.User{}, &models.Document{}, &models.DocumentVersion{}, &models.Permission{}, &models.Message{})
+	err := db.AutoMigrate(&models.User{}, &models.Document{}, &models.DocumentVersion{}, &models.Permission{}, &models.Message{},
+		&models.VersionRetentionPolicy{}, &models.DocumentAttribution{}, &models.DocumentOperation{}, &models.ShareLink{}, &models.Notification{},
+		&models.Team{}, &models.TeamMember{}, &models.TeamPermission{},
+		&models.Organization{}, &models.OrganizationMember{}, &models.Comment{},
+		&models.Suggestion{}, &models.OwnershipTransfer{}, &models.EmailInvitation{},
+		&models.AccessRequest{}, &models.Session{}, &models.UserIdentity{},
+		&models.UserToken{}, &models.UserMFA{}, &models.RecoveryCode{},
+		&models.PersonalAccessToken{}, &models.RateLimitBucket{}, &models.RateLimitFailure{},
+		&models.AuditLog{})
 	if err != nil {
 		return fmt.Errorf("migration failed: %w", err)
 	}
@@ -1,23 +1,105 @@
 package utils
 
 import (
+	"errors"
 	"time"
 
+	"github.com/dione-docs-backend/internal/signing"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
+// mfaAudience, iki adımlı doğrulama token'larını erişim token'larından ayırır.
+const mfaAudience = "mfa"
+
+// shareViewAudience, paylaşım bağlantısı görüntüleme oturumu token'larını erişim token'larından ayırır.
+const shareViewAudience = "share-view"
+
 type Claims struct {
-	UserID string `json:"user_id"`
+	UserID    string `json:"user_id"`
+	SessionID string `json:"sid"`
 	jwt.StandardClaims
 }
 
-func GenerateToken(userID, secret string) (string, error) {
+// GenerateToken, kullanıcının oturumuna bağlı, ttl süreli bir erişim token'ı üretir.
+func GenerateToken(keys *signing.KeySet, userID, sessionID string, ttl time.Duration) (string, error) {
+	claims := Claims{
+		UserID:    userID,
+		SessionID: sessionID,
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    keys.Issuer(),
+			Subject:   userID,
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: time.Now().Add(ttl).Unix(),
+		},
+	}
+	return keys.Sign(claims)
+}
+
+// ParseToken, erişim token'ının imzasını, yayımcısını ve süresini doğrular ve claim'lerini döndürür.
+func ParseToken(keys *signing.KeySet, tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if err := keys.Parse(tokenString, claims); err != nil {
+		return nil, err
+	}
+	if !claims.VerifyIssuer(keys.Issuer(), true) {
+		return nil, errors.New("unexpected token issuer")
+	}
+	return claims, nil
+}
+
+// GenerateMFAToken, şifresi doğrulanmış ancak ikinci adımı tamamlanmamış kullanıcı için kısa ömürlü
+// bir doğrulama token'ı üretir. Bu token oturum taşımadığından API erişimi için kullanılamaz.
+// Her token'ın deneme sayısının sınırlanabilmesi için benzersiz bir ID'si (jti) vardır.
+func GenerateMFAToken(keys *signing.KeySet, userID string, ttl time.Duration) (string, error) {
 	claims := Claims{
 		UserID: userID,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(24 * time.Hour).Unix(),
+			Issuer:    keys.Issuer(),
+			Subject:   userID,
+			Audience:  mfaAudience,
+			Id:        uuid.NewString(),
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: time.Now().Add(ttl).Unix(),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	return keys.Sign(claims)
+}
+
+// ParseMFAToken, doğrulama token'ını kontrol eder ve ait olduğu kullanıcı ID'sini ve token'ın ID'sini döndürür.
+func ParseMFAToken(keys *signing.KeySet, tokenString string) (userID, challengeID string, err error) {
+	claims, err := ParseToken(keys, tokenString)
+	if err != nil {
+		return "", "", err
+	}
+	if !claims.VerifyAudience(mfaAudience, true) || claims.SessionID != "" || claims.Id == "" {
+		return "", "", errors.New("not an mfa token")
+	}
+	return claims.UserID, claims.Id, nil
+}
+
+// GenerateShareViewToken, paylaşım bağlantısını anonim olarak açan ziyaretçiye verilen görüntüleme
+// oturumu token'ını üretir. Token yalnızca ilgili bağlantıyı tekrar açarken kullanım sayılmaması içindir.
+func GenerateShareViewToken(keys *signing.KeySet, linkID string, ttl time.Duration) (string, error) {
+	claims := jwt.StandardClaims{
+		Issuer:    keys.Issuer(),
+		Subject:   linkID,
+		Audience:  shareViewAudience,
+		Id:        uuid.NewString(),
+		IssuedAt:  time.Now().Unix(),
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}
+	return keys.Sign(claims)
+}
+
+// ParseShareViewToken, görüntüleme oturumu token'ını doğrular ve ait olduğu bağlantının ID'sini döndürür.
+func ParseShareViewToken(keys *signing.KeySet, tokenString string) (string, error) {
+	claims := &jwt.StandardClaims{}
+	if err := keys.Parse(tokenString, claims); err != nil {
+		return "", err
+	}
+	if !claims.VerifyIssuer(keys.Issuer(), true) || !claims.VerifyAudience(shareViewAudience, true) || claims.Subject == "" {
+		return "", errors.New("not a share view token")
+	}
+	return claims.Subject, nil
 }
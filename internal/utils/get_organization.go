@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"github.com/dione-docs-backend/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetOrganizationFromContext, istekte seçilmiş aktif organizasyonu ve kullanıcının oradaki
+// rolünü döndürür. Organizasyon seçilmemişse (kişisel alan) nil döner.
+func GetOrganizationFromContext(c *gin.Context) (*uuid.UUID, models.OrganizationRole) {
+	value, exists := c.Get("organization_id")
+	if !exists {
+		return nil, ""
+	}
+	organizationID, ok := value.(uuid.UUID)
+	if !ok {
+		return nil, ""
+	}
+	role, _ := c.Get("organization_role")
+	organizationRole, _ := role.(models.OrganizationRole)
+	return &organizationID, organizationRole
+}
@@ -23,3 +23,16 @@ func GetUserIDFromContext(c *gin.Context) (uuid.UUID, error) {
 	}
 	return parsedUUID, nil
 }
+
+// GetSessionIDFromContext, isteğin erişim token'ının ait olduğu oturumun ID'sini döndürür.
+func GetSessionIDFromContext(c *gin.Context) (uuid.UUID, error) {
+	value, exists := c.Get("session_id")
+	if !exists {
+		return uuid.Nil, errors.New("oturum kimliği bulunamadı")
+	}
+	sessionID, ok := value.(uuid.UUID)
+	if !ok {
+		return uuid.Nil, errors.New("geçersiz oturum kimliği formatı")
+	}
+	return sessionID, nil
+}